@@ -0,0 +1,88 @@
+package gotlai
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
+// CacheKeyStrategy controls how the Translator derives cache keys.
+type CacheKeyStrategy int
+
+const (
+	// CacheKeyLegacy keys translations by text hash and target language only.
+	// Changing other settings serves translations produced under the old settings.
+	CacheKeyLegacy CacheKeyStrategy = iota
+	// CacheKeySettings also keys translations by a fingerprint of the source
+	// language, model, style, glossary, context and excluded terms.
+	CacheKeySettings
+)
+
+// WithCacheKeyStrategy sets how cache keys are derived (default: CacheKeyLegacy).
+func WithCacheKeyStrategy(strategy CacheKeyStrategy) TranslatorOption {
+	return func(t *Translator) {
+		t.cacheKeyStrategy = strategy
+	}
+}
+
+// WithModel records the model name used in the settings fingerprint.
+// It is only needed when the provider does not report its model.
+func WithModel(model string) TranslatorOption {
+	return func(t *Translator) {
+		t.model = model
+	}
+}
+
+// ModelReporter is implemented by providers that can report the model they use.
+type ModelReporter interface {
+	Model() string
+}
+
+// Fingerprint returns a short hash of every setting that influences translation output.
+func (t *Translator) Fingerprint() string {
+	model := t.model
+	if model == "" {
+		model = providerModel(t.provider)
+	}
+
+	glossaryKeys := make([]string, 0, len(t.glossary))
+	for k := range t.glossary {
+		glossaryKeys = append(glossaryKeys, k)
+	}
+	sort.Strings(glossaryKeys)
+
+	excluded := append([]string(nil), t.excludedTerms...)
+	sort.Strings(excluded)
+
+	var b strings.Builder
+	b.WriteString("source=" + t.sourceLang + "\n")
+	b.WriteString("model=" + model + "\n")
+	b.WriteString("style=" + string(t.style) + "\n")
+	b.WriteString("context=" + t.context + "\n")
+	for _, k := range glossaryKeys {
+		b.WriteString("glossary=" + k + "\x00" + t.glossary[k] + "\n")
+	}
+	for _, term := range excluded {
+		b.WriteString("exclude=" + term + "\n")
+	}
+
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:8])
+}
+
+// cacheKey returns the cache key for a text hash under the configured strategy.
+func (t *Translator) cacheKey(hash string) string {
+	if t.cacheKeyStrategy == CacheKeySettings {
+		return CacheKeyFingerprint(hash, t.targetLang, t.fingerprint)
+	}
+	return CacheKey(hash, t.targetLang)
+}
+
+// providerModel returns the model reported by provider, or "" if it does not report one.
+func providerModel(provider AIProvider) string {
+	if m, ok := provider.(ModelReporter); ok {
+		return m.Model()
+	}
+	return ""
+}
@@ -0,0 +1,111 @@
+package gotlai
+
+import (
+	"context"
+	"testing"
+)
+
+// modelProvider is a mock provider that reports its model.
+type modelProvider struct {
+	*mockProvider
+	model string
+}
+
+func (p *modelProvider) Model() string {
+	return p.model
+}
+
+func TestTranslator_FingerprintChangesWithSettings(t *testing.T) {
+	provider := newMockProvider()
+	base := NewTranslator("es_ES", provider).Fingerprint()
+
+	variants := map[string]TranslatorOption{
+		"source":   WithSourceLang("fr"),
+		"style":    WithStyle(StyleFormal),
+		"context":  WithContext("Legal documents"),
+		"glossary": WithGlossary(map[string]string{"cart": "carrito"}),
+		"excluded": WithExcludedTerms([]string{"API"}),
+		"model":    WithModel("gpt-4o"),
+	}
+
+	for name, opt := range variants {
+		if fp := NewTranslator("es_ES", provider, opt).Fingerprint(); fp == base {
+			t.Errorf("Fingerprint should change when %s changes", name)
+		}
+	}
+}
+
+func TestTranslator_FingerprintStable(t *testing.T) {
+	provider := newMockProvider()
+
+	a := NewTranslator("es_ES", provider,
+		WithGlossary(map[string]string{"a": "1", "b": "2", "c": "3"}),
+		WithExcludedTerms([]string{"API", "SDK"}),
+	)
+	b := NewTranslator("de_DE", provider,
+		WithGlossary(map[string]string{"c": "3", "b": "2", "a": "1"}),
+		WithExcludedTerms([]string{"SDK", "API"}),
+	)
+
+	if a.Fingerprint() != b.Fingerprint() {
+		t.Error("Fingerprint should not depend on map or slice order, or on target language")
+	}
+}
+
+func TestTranslator_FingerprintUsesProviderModel(t *testing.T) {
+	mini := &modelProvider{mockProvider: newMockProvider(), model: "gpt-4o-mini"}
+	full := &modelProvider{mockProvider: newMockProvider(), model: "gpt-4o"}
+
+	if NewTranslator("es_ES", mini).Fingerprint() == NewTranslator("es_ES", full).Fingerprint() {
+		t.Error("Fingerprint should include the provider's model")
+	}
+
+	// Wrapped providers forward the model
+	wrapped := NewRetryableProvider(NewRateLimitedProvider(full, RateLimitConfig{}), DefaultRetryConfig())
+	if wrapped.Model() != "gpt-4o" {
+		t.Errorf("Wrapped provider should report inner model, got %q", wrapped.Model())
+	}
+}
+
+func TestTranslator_CacheKeyStrategy(t *testing.T) {
+	provider := newMockProvider()
+	cache := newMockCache()
+
+	legacy := NewTranslator("es_ES", provider,
+		WithCache(cache),
+		WithProcessor(&mockHTMLProcessor{}),
+	)
+	if _, err := legacy.Process(context.Background(), "<p>Hello</p>", "html"); err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if _, ok := cache.data[CacheKey(HashText("Hello"), "es_ES")]; !ok {
+		t.Error("Legacy strategy should use CacheKey")
+	}
+
+	formal := NewTranslator("es_ES", provider,
+		WithCache(cache),
+		WithProcessor(&mockHTMLProcessor{}),
+		WithStyle(StyleFormal),
+		WithCacheKeyStrategy(CacheKeySettings),
+	)
+	result, err := formal.Process(context.Background(), "<p>Hello</p>", "html")
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if result.CachedCount != 0 {
+		t.Error("Translation under different settings should not be served from cache")
+	}
+
+	key := CacheKeyFingerprint(HashText("Hello"), "es_ES", formal.Fingerprint())
+	if _, ok := cache.data[key]; !ok {
+		t.Errorf("Settings strategy should store under %q", key)
+	}
+
+	result, err = formal.Process(context.Background(), "<p>Hello</p>", "html")
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if result.CachedCount != 1 {
+		t.Errorf("Expected cache hit under same settings, got CachedCount %d", result.CachedCount)
+	}
+}
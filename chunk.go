@@ -0,0 +1,89 @@
+package gotlai
+
+import "unicode/utf8"
+
+// ChunkConfig controls how cache misses are split into provider requests.
+// A zero value for either limit disables that limit.
+type ChunkConfig struct {
+	MaxTexts  int // Maximum number of texts per request
+	MaxTokens int // Maximum estimated tokens (texts + contexts) per request
+}
+
+// DefaultChunkConfig returns limits that fit comfortably within common model context windows.
+func DefaultChunkConfig() ChunkConfig {
+	return ChunkConfig{
+		MaxTexts:  100,
+		MaxTokens: 4000,
+	}
+}
+
+// ChunkResult reports the outcome of a single provider request.
+type ChunkResult struct {
+	Index           int   // Position of the chunk within the batch
+	Texts           int   // Number of texts sent in the request
+	EstimatedTokens int   // Estimated tokens sent in the request
+	Err             error // Provider error, nil on success
+}
+
+// WithChunking splits cache misses into several provider requests using the given limits.
+func WithChunking(cfg ChunkConfig) TranslatorOption {
+	return func(t *Translator) {
+		t.chunking = cfg
+	}
+}
+
+// WithConcurrency sets how many chunk requests may be in flight at once (default: 1).
+// The provider must be safe for concurrent use when n > 1; wrap it with
+// NewRateLimitedProvider to bound the request rate across workers.
+func WithConcurrency(n int) TranslatorOption {
+	return func(t *Translator) {
+		t.concurrency = n
+	}
+}
+
+// EstimateTokens returns a rough token count for text, assuming ~4 characters per token.
+func EstimateTokens(text string) int {
+	if text == "" {
+		return 0
+	}
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// estimateNodeTokens estimates the tokens a node contributes to a request.
+func estimateNodeTokens(node TextNode) int {
+	return EstimateTokens(node.Text) + EstimateTokens(node.Context)
+}
+
+// ChunkNodes splits nodes into consecutive chunks that respect the configured limits.
+// A single node that exceeds MaxTokens on its own is placed in a chunk by itself.
+func ChunkNodes(nodes []TextNode, cfg ChunkConfig) [][]TextNode {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	var chunks [][]TextNode
+	var current []TextNode
+	currentTokens := 0
+
+	for _, node := range nodes {
+		tokens := estimateNodeTokens(node)
+
+		full := cfg.MaxTexts > 0 && len(current) >= cfg.MaxTexts
+		overBudget := cfg.MaxTokens > 0 && currentTokens+tokens > cfg.MaxTokens
+
+		if len(current) > 0 && (full || overBudget) {
+			chunks = append(chunks, current)
+			current = nil
+			currentTokens = 0
+		}
+
+		current = append(current, node)
+		currentTokens += tokens
+	}
+
+	if len(current) > 0 {
+		chunks = append(chunks, current)
+	}
+
+	return chunks
+}
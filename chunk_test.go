@@ -0,0 +1,271 @@
+package gotlai
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func makeNodes(texts ...string) []TextNode {
+	nodes := make([]TextNode, len(texts))
+	for i, text := range texts {
+		nodes[i] = TextNode{ID: text, Text: text, Hash: HashText(text)}
+	}
+	return nodes
+}
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected int
+	}{
+		{"", 0},
+		{"a", 1},
+		{"abcd", 1},
+		{"abcde", 2},
+		{"こんにちは", 2},
+	}
+
+	for _, tt := range tests {
+		if got := EstimateTokens(tt.text); got != tt.expected {
+			t.Errorf("EstimateTokens(%q) = %d, want %d", tt.text, got, tt.expected)
+		}
+	}
+}
+
+func TestChunkNodes_Unlimited(t *testing.T) {
+	nodes := makeNodes("one", "two", "three")
+
+	chunks := ChunkNodes(nodes, ChunkConfig{})
+	if len(chunks) != 1 {
+		t.Fatalf("Expected 1 chunk, got %d", len(chunks))
+	}
+	if len(chunks[0]) != 3 {
+		t.Errorf("Expected 3 nodes in chunk, got %d", len(chunks[0]))
+	}
+}
+
+func TestChunkNodes_MaxTexts(t *testing.T) {
+	nodes := makeNodes("a", "b", "c", "d", "e")
+
+	chunks := ChunkNodes(nodes, ChunkConfig{MaxTexts: 2})
+	if len(chunks) != 3 {
+		t.Fatalf("Expected 3 chunks, got %d", len(chunks))
+	}
+	if len(chunks[0]) != 2 || len(chunks[1]) != 2 || len(chunks[2]) != 1 {
+		t.Errorf("Unexpected chunk sizes: %d, %d, %d", len(chunks[0]), len(chunks[1]), len(chunks[2]))
+	}
+	if chunks[2][0].Text != "e" {
+		t.Errorf("Chunks should preserve order, last node is %q", chunks[2][0].Text)
+	}
+}
+
+func TestChunkNodes_MaxTokens(t *testing.T) {
+	// Each text is 8 characters = 2 tokens
+	nodes := makeNodes("aaaaaaaa", "bbbbbbbb", "cccccccc")
+
+	chunks := ChunkNodes(nodes, ChunkConfig{MaxTokens: 4})
+	if len(chunks) != 2 {
+		t.Fatalf("Expected 2 chunks, got %d", len(chunks))
+	}
+	if len(chunks[0]) != 2 {
+		t.Errorf("Expected 2 nodes in first chunk, got %d", len(chunks[0]))
+	}
+}
+
+func TestChunkNodes_OversizedNode(t *testing.T) {
+	nodes := makeNodes("short", strings.Repeat("x", 100), "tiny")
+
+	chunks := ChunkNodes(nodes, ChunkConfig{MaxTokens: 5})
+	if len(chunks) != 3 {
+		t.Fatalf("Expected oversized node in its own chunk (3 chunks), got %d", len(chunks))
+	}
+	if len(chunks[1]) != 1 {
+		t.Errorf("Oversized node should be alone, got %d nodes", len(chunks[1]))
+	}
+}
+
+func TestTranslator_Chunking(t *testing.T) {
+	provider := newMockProvider()
+	cache := newMockCache()
+	processor := &mockHTMLProcessor{}
+
+	translator := NewTranslator("es_ES", provider,
+		WithCache(cache),
+		WithProcessor(processor),
+		WithChunking(ChunkConfig{MaxTexts: 2}),
+	)
+
+	content := "<p>Hello</p><p>World</p><p>Translate me</p>"
+	result, err := translator.Process(context.Background(), content, "html")
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+
+	if provider.callCount != 2 {
+		t.Errorf("Expected 2 provider calls, got %d", provider.callCount)
+	}
+	if result.TranslatedCount != 3 {
+		t.Errorf("Expected TranslatedCount 3, got %d", result.TranslatedCount)
+	}
+	if len(result.Chunks) != 2 {
+		t.Fatalf("Expected 2 chunk results, got %d", len(result.Chunks))
+	}
+	if result.Chunks[0].Texts != 2 || result.Chunks[1].Texts != 1 {
+		t.Errorf("Unexpected chunk sizes: %d, %d", result.Chunks[0].Texts, result.Chunks[1].Texts)
+	}
+	for _, want := range []string{"Hola", "Mundo", "Tradúceme"} {
+		if !strings.Contains(result.Content, want) {
+			t.Errorf("Result should contain %q, got: %s", want, result.Content)
+		}
+	}
+}
+
+// shortProvider returns one translation fewer than requested.
+type shortProvider struct{}
+
+func (p *shortProvider) Translate(ctx context.Context, req TranslateRequest) ([]string, error) {
+	return req.Texts[:len(req.Texts)-1], nil
+}
+
+func TestTranslator_ChunkCountMismatch(t *testing.T) {
+	translator := NewTranslator("es_ES", &shortProvider{},
+		WithProcessor(&mockHTMLProcessor{}),
+	)
+
+	_, err := translator.Process(context.Background(), "<p>Hello</p><p>World</p>", "html")
+	if err == nil {
+		t.Fatal("Expected error for count mismatch")
+	}
+	if _, ok := err.(*CountMismatchError); !ok {
+		t.Errorf("Expected CountMismatchError, got %T", err)
+	}
+}
+
+// concurrentProvider records how many requests are in flight at once.
+type concurrentProvider struct {
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	calls       int
+	delay       time.Duration
+}
+
+func (p *concurrentProvider) Translate(ctx context.Context, req TranslateRequest) ([]string, error) {
+	p.mu.Lock()
+	p.calls++
+	p.inFlight++
+	if p.inFlight > p.maxInFlight {
+		p.maxInFlight = p.inFlight
+	}
+	p.mu.Unlock()
+
+	defer func() {
+		p.mu.Lock()
+		p.inFlight--
+		p.mu.Unlock()
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(p.delay):
+	}
+
+	results := make([]string, len(req.Texts))
+	for i, text := range req.Texts {
+		results[i] = "[" + text + "]"
+	}
+	return results, nil
+}
+
+func TestTranslator_Concurrency(t *testing.T) {
+	provider := &concurrentProvider{delay: 20 * time.Millisecond}
+	cache := newMockCache()
+
+	translator := NewTranslator("es_ES", provider,
+		WithCache(cache),
+		WithProcessor(&mockHTMLProcessor{}),
+		WithChunking(ChunkConfig{MaxTexts: 1}),
+		WithConcurrency(3),
+	)
+
+	content := "<p>one</p><p>two</p><p>three</p><p>four</p><p>five</p><p>six</p>"
+	result, err := translator.Process(context.Background(), content, "html")
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+
+	if provider.calls != 6 {
+		t.Errorf("Expected 6 provider calls, got %d", provider.calls)
+	}
+	if provider.maxInFlight > 3 {
+		t.Errorf("Expected at most 3 requests in flight, got %d", provider.maxInFlight)
+	}
+	if provider.maxInFlight < 2 {
+		t.Errorf("Expected requests to run concurrently, max in flight was %d", provider.maxInFlight)
+	}
+	if result.TranslatedCount != 6 {
+		t.Errorf("Expected TranslatedCount 6, got %d", result.TranslatedCount)
+	}
+	for i, chunk := range result.Chunks {
+		if chunk.Index != i {
+			t.Errorf("Chunks should be ordered by index, got %d at position %d", chunk.Index, i)
+		}
+	}
+	if len(cache.data) != 6 {
+		t.Errorf("Expected 6 cached translations, got %d", len(cache.data))
+	}
+	if !strings.Contains(result.Content, "[six]") {
+		t.Errorf("Result should contain '[six]', got: %s", result.Content)
+	}
+}
+
+func TestTranslator_ConcurrencyCancelled(t *testing.T) {
+	provider := &concurrentProvider{delay: time.Second}
+
+	translator := NewTranslator("es_ES", provider,
+		WithProcessor(&mockHTMLProcessor{}),
+		WithChunking(ChunkConfig{MaxTexts: 1}),
+		WithConcurrency(2),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := translator.Process(ctx, "<p>one</p><p>two</p><p>three</p><p>four</p>", "html")
+	if err == nil {
+		t.Fatal("Expected error when context is cancelled")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Cancellation should stop dispatch promptly, took %v", elapsed)
+	}
+	if provider.calls > 2 {
+		t.Errorf("Chunks should not start after cancellation, got %d calls", provider.calls)
+	}
+}
+
+func TestTranslator_ConcurrencyWithWrappedProvider(t *testing.T) {
+	inner := &concurrentProvider{}
+	wrapped := NewRetryableProvider(
+		NewRateLimitedProvider(inner, RateLimitConfig{RequestsPerMinute: 6000, BurstSize: 10}),
+		RetryConfig{MaxRetries: 1, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond},
+	)
+
+	translator := NewTranslator("es_ES", wrapped,
+		WithProcessor(&mockHTMLProcessor{}),
+		WithChunking(ChunkConfig{MaxTexts: 1}),
+		WithConcurrency(4),
+	)
+
+	result, err := translator.Process(context.Background(), "<p>one</p><p>two</p><p>three</p>", "html")
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if result.TranslatedCount != 3 {
+		t.Errorf("Expected TranslatedCount 3, got %d", result.TranslatedCount)
+	}
+}
@@ -23,3 +23,21 @@ func CacheKey(hash, targetLang string) string {
 func CacheKeyExtended(hash, sourceLang, targetLang, model string) string {
 	return hash + ":" + sourceLang + ":" + targetLang + ":" + model
 }
+
+// CacheKeyFingerprint generates a cache key that includes a settings fingerprint.
+// Use this when translations must not be shared across different translation settings.
+func CacheKeyFingerprint(hash, targetLang, fingerprint string) string {
+	return hash + ":" + targetLang + ":" + fingerprint
+}
+
+// HashTextWithContext computes the SHA-256 hash of the trimmed text combined with a context key.
+// Identical text with different context keys produces different hashes, so it is
+// deduplicated and cached separately. An empty context key yields HashText(text).
+func HashTextWithContext(text, contextKey string) string {
+	if contextKey == "" {
+		return HashText(text)
+	}
+	trimmed := strings.TrimSpace(text)
+	hash := sha256.Sum256([]byte(trimmed + "\x00" + contextKey))
+	return hex.EncodeToString(hash[:])
+}
@@ -73,3 +73,28 @@ func TestCacheKeyExtended(t *testing.T) {
 		t.Errorf("CacheKeyExtended() = %q, want %q", result, expected)
 	}
 }
+
+func TestCacheKeyFingerprint(t *testing.T) {
+	key := CacheKeyFingerprint("abc123", "es_ES", "f00d")
+	expected := "abc123:es_ES:f00d"
+
+	if key != expected {
+		t.Errorf("CacheKeyFingerprint() = %q, want %q", key, expected)
+	}
+}
+
+func TestHashTextWithContext(t *testing.T) {
+	if HashTextWithContext("Close", "") != HashText("Close") {
+		t.Error("Empty context key should match HashText")
+	}
+
+	button := HashTextWithContext("Close", "button|dialog")
+	address := HashTextWithContext("Close", "address")
+	if button == address {
+		t.Error("Different context keys should produce different hashes")
+	}
+
+	if HashTextWithContext("  Close  ", "address") != address {
+		t.Error("Text should be trimmed before hashing")
+	}
+}
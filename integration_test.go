@@ -302,3 +302,184 @@ func (p *failingMockProvider) Translate(ctx context.Context, req gotlai.Translat
 	}
 	return results, nil
 }
+
+// localeProvider prefixes each text with the target language.
+type localeProvider struct{}
+
+func (p *localeProvider) Translate(ctx context.Context, req gotlai.TranslateRequest) ([]string, error) {
+	results := make([]string, len(req.Texts))
+	for i, text := range req.Texts {
+		results[i] = req.TargetLang + ":" + text
+	}
+	return results, nil
+}
+
+func TestIntegration_ProcessMany(t *testing.T) {
+	c := cache.NewInMemoryCache(3600)
+	translator := gotlai.NewTranslator("es_ES", &localeProvider{},
+		gotlai.WithCache(c),
+		gotlai.WithProcessor(processor.NewHTMLProcessor()),
+	)
+
+	html := `<html><body><h1>Welcome</h1><p>Hello</p></body></html>`
+	langs := []string{"es_ES", "ar_SA", "ja_JP", "en_GB"}
+
+	results, err := translator.ProcessMany(context.Background(), html, "html", langs)
+	if err != nil {
+		t.Fatalf("ProcessMany failed: %v", err)
+	}
+
+	if len(results) != len(langs) {
+		t.Fatalf("Expected %d results, got %d", len(langs), len(results))
+	}
+
+	for _, lang := range []string{"es_ES", "ar_SA", "ja_JP"} {
+		result := results[lang]
+		if !strings.Contains(result.Content, lang+":Welcome") || !strings.Contains(result.Content, lang+":Hello") {
+			t.Errorf("%s: expected translated content, got: %s", lang, result.Content)
+		}
+		if strings.Count(result.Content, ":Hello") != 1 {
+			t.Errorf("%s: translations from other languages leaked into result: %s", lang, result.Content)
+		}
+		if result.TranslatedCount != 2 {
+			t.Errorf("%s: expected TranslatedCount 2, got %d", lang, result.TranslatedCount)
+		}
+	}
+
+	if !strings.Contains(results["ar_SA"].Content, `dir="rtl"`) {
+		t.Errorf("ar_SA result should be RTL, got: %s", results["ar_SA"].Content)
+	}
+
+	// Source language is returned unchanged
+	if results["en_GB"].Content != html {
+		t.Errorf("en_GB should be unchanged, got: %s", results["en_GB"].Content)
+	}
+
+	// Second run is served entirely from the cache
+	results, err = translator.ProcessMany(context.Background(), html, "html", langs)
+	if err != nil {
+		t.Fatalf("Second ProcessMany failed: %v", err)
+	}
+	if results["ja_JP"].CachedCount != 2 || results["ja_JP"].TranslatedCount != 0 {
+		t.Errorf("Expected all cache hits on second run, got cached=%d translated=%d",
+			results["ja_JP"].CachedCount, results["ja_JP"].TranslatedCount)
+	}
+}
+
+func TestIntegration_ProcessStream(t *testing.T) {
+	p := provider.NewMockProvider()
+	c := cache.NewInMemoryCache(3600)
+
+	translator := gotlai.NewTranslator("es_ES", p,
+		gotlai.WithCache(c),
+		gotlai.WithProcessor(processor.NewHTMLProcessor(processor.WithStreamWindow(1))),
+	)
+
+	html := `<html><body><h1>Hello World</h1><p>Hello</p><p>World</p><p>Hello</p></body></html>`
+
+	var out strings.Builder
+	result, err := translator.ProcessStream(context.Background(), strings.NewReader(html), &out, "html")
+	if err != nil {
+		t.Fatalf("ProcessStream failed: %v", err)
+	}
+
+	expected := `<html lang="es-ES" dir="ltr"><body><h1>Hola Mundo</h1><p>Hola</p><p>Mundo</p><p>Hola</p></body></html>`
+	if out.String() != expected {
+		t.Errorf("ProcessStream output = %q, want %q", out.String(), expected)
+	}
+
+	if result.TotalNodes != 3 || result.TranslatedCount != 3 {
+		t.Errorf("Expected 3 nodes translated, got total=%d translated=%d", result.TotalNodes, result.TranslatedCount)
+	}
+	if p.CallCount != 3 {
+		t.Errorf("Expected one provider call per window, got %d", p.CallCount)
+	}
+}
+
+func TestIntegration_ProcessStream_PostProcessors(t *testing.T) {
+	p := provider.NewMockProvider()
+
+	translator := gotlai.NewTranslator("es_ES", p,
+		gotlai.WithProcessor(processor.NewHTMLProcessor()),
+		gotlai.WithHTMLPostProcessors(&gotlai.LocalizedLinks{Locales: []string{"en_US", "es_ES"}}),
+	)
+
+	var out strings.Builder
+	_, err := translator.ProcessStream(context.Background(), strings.NewReader(`<p>Hello</p>`), &out, "html")
+	if _, ok := err.(*gotlai.ProcessorError); !ok {
+		t.Errorf("Expected ProcessorError, got %v", err)
+	}
+	if out.Len() != 0 || p.CallCount != 0 {
+		t.Errorf("Expected nothing to be written or translated, got %q and %d calls", out.String(), p.CallCount)
+	}
+}
+
+func TestIntegration_HTMLFragment(t *testing.T) {
+	translator := gotlai.NewTranslator("es_ES", provider.NewMockProvider(),
+		gotlai.WithProcessor(processor.NewHTMLProcessor(processor.WithFragment("div"))),
+	)
+
+	html := "<h1 class='title'>Hello World</h1>\n<p>Hello<br>World</p>"
+	result, err := translator.ProcessHTML(context.Background(), html)
+	if err != nil {
+		t.Fatalf("ProcessHTML failed: %v", err)
+	}
+
+	expected := "<h1 class='title'>Hola Mundo</h1>\n<p>Hola<br>Mundo</p>"
+	if result.Content != expected {
+		t.Errorf("Fragment = %q, want %q", result.Content, expected)
+	}
+}
+
+func TestIntegration_HTMLMinimalDiff(t *testing.T) {
+	translator := gotlai.NewTranslator("es_ES", provider.NewMockProvider(),
+		gotlai.WithProcessor(processor.NewHTMLProcessor(processor.WithMinimalDiff())),
+	)
+
+	html := "<!DOCTYPE html>\n<html lang=\"en\">\n<body class=page>\n  <p>Hello</p>\n  <br/>\n</body>\n</html>\n"
+	result, err := translator.ProcessHTML(context.Background(), html)
+	if err != nil {
+		t.Fatalf("ProcessHTML failed: %v", err)
+	}
+
+	expected := "<!DOCTYPE html>\n<html lang=\"es-ES\" dir=\"ltr\">\n<body class=page>\n  <p>Hola</p>\n  <br/>\n</body>\n</html>\n"
+	if result.Content != expected {
+		t.Errorf("Minimal diff = %q, want %q", result.Content, expected)
+	}
+}
+
+func TestIntegration_Markdown(t *testing.T) {
+	translator := gotlai.NewTranslator("es_ES", provider.NewMockProvider(),
+		gotlai.WithProcessor(processor.NewMarkdownProcessor()),
+	)
+
+	md := "---\ntitle: Hello\n---\n# Hello World\n\n`Hello` stays code.\n"
+	result, err := translator.Process(context.Background(), md, "markdown")
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+
+	for _, s := range []string{"title: Hola\n", "# Hola Mundo\n", "`Hello`"} {
+		if !strings.Contains(result.Content, s) {
+			t.Errorf("Expected %q in result, got %q", s, result.Content)
+		}
+	}
+}
+
+func TestIntegration_PO(t *testing.T) {
+	translator := gotlai.NewTranslator("es_ES", provider.NewMockProvider(),
+		gotlai.WithProcessor(processor.NewPOProcessor()),
+	)
+
+	po := "msgid \"\"\nmsgstr \"\"\n\"Language: \\n\"\n\nmsgid \"Hello\"\nmsgstr \"\"\n"
+	result, err := translator.Process(context.Background(), po, "po")
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+
+	for _, s := range []string{`"Language: es_ES\n"`, `"Plural-Forms: nplurals=2; plural=(n != 1);\n"`, "msgstr \"Hola\"\n"} {
+		if !strings.Contains(result.Content, s) {
+			t.Errorf("Expected %q in result, got %q", s, result.Content)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package gotlai
+
+import (
+	"context"
+	"sync"
+)
+
+// ProcessMany translates content into several target languages in one call.
+// The content is extracted once; cache lookups and provider requests then run
+// concurrently per language, and the shared parsed content is applied for each
+// language in turn. The cache must be safe for concurrent use (InMemoryCache
+// and RedisCache are). Results are keyed by target language.
+func (t *Translator) ProcessMany(ctx context.Context, content string, contentType string, targetLangs []string) (map[string]*ProcessedContent, error) {
+	// Get processor
+	processor, ok := t.processors[contentType]
+	if !ok {
+		return nil, &ProcessorError{
+			Message:     "no processor registered for content type",
+			ContentType: contentType,
+		}
+	}
+
+	// Extract text nodes once for all languages
+	parsed, nodes, err := processor.Extract(content)
+	if err != nil {
+		return nil, err
+	}
+
+	validator, _ := processor.(TranslationValidator)
+
+	translators := make([]*Translator, len(targetLangs))
+	langNodes := make([][]TextNode, len(targetLangs))
+	batches := make([]*batchResult, len(targetLangs))
+	errs := make([]error, len(targetLangs))
+
+	var wg sync.WaitGroup
+	for i, lang := range targetLangs {
+		translators[i] = t.forTargetLang(lang)
+		if translators[i].isSourceLang() {
+			continue
+		}
+		langNodes[i] = translators[i].targetNodes(processor, parsed, nodes)
+		if len(langNodes[i]) == 0 {
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			batches[i], errs[i] = translators[i].translateBatch(ctx, langNodes[i], validator)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Apply sequentially since all languages share the parsed content
+	results := make(map[string]*ProcessedContent, len(targetLangs))
+	for i, lang := range targetLangs {
+		if batches[i] == nil {
+			results[lang] = &ProcessedContent{
+				Content:         content,
+				TranslatedCount: 0,
+				CachedCount:     0,
+				TotalNodes:      0,
+			}
+			continue
+		}
+
+		result, err := translators[i].applyBatch(processor, parsed, langNodes[i], batches[i])
+		if err != nil {
+			return nil, err
+		}
+		results[lang] = result
+	}
+
+	return results, nil
+}
+
+// forTargetLang returns a copy of the translator that targets another language.
+func (t *Translator) forTargetLang(lang string) *Translator {
+	clone := *t
+	clone.targetLang = lang
+	return &clone
+}
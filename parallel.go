@@ -8,6 +8,13 @@ import (
 // ParallelCacheLookup performs cache lookups in parallel using goroutines.
 // Returns a map of hash to cached value, and a slice of cache misses.
 func ParallelCacheLookup(cache TranslationCache, nodes []TextNode, targetLang string) (map[string]string, []TextNode) {
+	return parallelCacheLookup(cache, nodes, func(hash string) string {
+		return CacheKey(hash, targetLang)
+	})
+}
+
+// parallelCacheLookup performs parallel cache lookups using keyFn to derive cache keys.
+func parallelCacheLookup(cache TranslationCache, nodes []TextNode, keyFn func(hash string) string) (map[string]string, []TextNode) {
 	if cache == nil || len(nodes) == 0 {
 		return make(map[string]string), nodes
 	}
@@ -35,7 +42,7 @@ func ParallelCacheLookup(cache TranslationCache, nodes []TextNode, targetLang st
 		wg.Add(1)
 		go func(h string) {
 			defer wg.Done()
-			key := CacheKey(h, targetLang)
+			key := keyFn(h)
 			if val, ok := cache.Get(key); ok {
 				results <- lookupResult{hash: h, value: val, found: true}
 			} else {
@@ -100,45 +107,24 @@ func (t *ParallelTranslator) WithParallelThreshold(n int) *ParallelTranslator {
 func (t *ParallelTranslator) TranslateBatchParallel(ctx context.Context, nodes []TextNode) (map[string]string, int, int, error) {
 	if t.cache == nil || len(nodes) < t.parallelThreshold {
 		// Fall back to sequential for small batches or no cache
-		return t.translateBatch(ctx, nodes)
+		batch, err := t.translateBatch(ctx, nodes, nil)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		return batch.translations, batch.cachedCount, batch.translatedCount, nil
 	}
 
 	// Parallel cache lookup
-	translations, cacheMisses := ParallelCacheLookup(t.cache, nodes, t.targetLang)
-	cachedCount := len(translations)
+	translations, cacheMisses := parallelCacheLookup(t.cache, nodes, t.cacheKey)
+	batch := &batchResult{
+		translations: translations,
+		cachedCount:  len(translations),
+	}
 
 	// Translate cache misses via AI
-	translatedCount := 0
-	if len(cacheMisses) > 0 && t.provider != nil {
-		texts := make([]string, len(cacheMisses))
-		textContexts := make([]string, len(cacheMisses))
-		for i, node := range cacheMisses {
-			texts[i] = node.Text
-			textContexts[i] = node.Context
-		}
-
-		results, err := t.provider.Translate(ctx, TranslateRequest{
-			Texts:         texts,
-			TargetLang:    t.targetLang,
-			SourceLang:    t.sourceLang,
-			ExcludedTerms: t.excludedTerms,
-			Context:       t.context,
-			TextContexts:  textContexts,
-		})
-		if err != nil {
-			return nil, 0, 0, err
-		}
-
-		// Cache and store results
-		for i, node := range cacheMisses {
-			translations[node.Hash] = results[i]
-			if t.cache != nil {
-				cacheKey := CacheKey(node.Hash, t.targetLang)
-				_ = t.cache.Set(cacheKey, results[i]) // Ignore cache set errors
-			}
-			translatedCount++
-		}
+	if err := t.translateMisses(ctx, cacheMisses, batch); err != nil {
+		return nil, 0, 0, err
 	}
 
-	return translations, cachedCount, translatedCount, nil
+	return batch.translations, batch.cachedCount, batch.translatedCount, nil
 }
@@ -0,0 +1,220 @@
+package gotlai
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// HTMLPostProcessor rewrites translated HTML before it is returned, e.g. to add
+// SEO links. Post-processors run after lang and dir are set on the <html> tag.
+type HTMLPostProcessor interface {
+	PostProcessHTML(content string, targetLang string) (string, error)
+}
+
+// HTMLPostProcessorFunc adapts a function to HTMLPostProcessor.
+type HTMLPostProcessorFunc func(content string, targetLang string) (string, error)
+
+// PostProcessHTML calls f(content, targetLang).
+func (f HTMLPostProcessorFunc) PostProcessHTML(content string, targetLang string) (string, error) {
+	return f(content, targetLang)
+}
+
+// WithHTMLPostProcessors adds post-processors that run, in order, on translated
+// HTML content. Streaming HTML with post-processors configured is an error,
+// as they need the whole document.
+func WithHTMLPostProcessors(pps ...HTMLPostProcessor) TranslatorOption {
+	return func(t *Translator) {
+		t.postProcessors = append(t.postProcessors, pps...)
+	}
+}
+
+// postProcessHTML sets lang and dir on the <html> tag, then runs the configured post-processors.
+func (t *Translator) postProcessHTML(content string) (string, error) {
+	content = t.setHTMLAttributes(content)
+
+	for _, pp := range t.postProcessors {
+		var err error
+		content, err = pp.PostProcessHTML(content, t.targetLang)
+		if err != nil {
+			return "", &ProcessorError{
+				Message:     "HTML post-processing failed",
+				Cause:       err,
+				ContentType: "html",
+			}
+		}
+	}
+
+	return content, nil
+}
+
+// LocalizedLinks is an HTMLPostProcessor that makes translated pages SEO-complete.
+// It rewrites the canonical URL and internal <a href> links to their localized
+// URLs, and inserts <link rel="alternate" hreflang="..."> links for Locales
+// after the canonical link, replacing existing hreflang alternates. Alternates
+// are derived from the canonical URL, so pages without one only get their
+// links rewritten. Tags that are not rewritten are left byte for byte.
+type LocalizedLinks struct {
+	// Locales to link as alternates, e.g. []string{"en_US", "es_ES", "ja_JP"}.
+	// Include the source locale and the target itself.
+	Locales []string
+
+	// XDefault is the locale whose URL is used for hreflang="x-default", or empty for none.
+	XDefault string
+
+	// URL maps a source URL to its URL in a locale. Internal links are relative
+	// URLs or URLs on the canonical URL's host.
+	URL func(rawURL string, locale string) string
+}
+
+// PostProcessHTML rewrites links in content for targetLang.
+func (l *LocalizedLinks) PostProcessHTML(content string, targetLang string) (string, error) {
+	if l.URL == nil {
+		return content, nil
+	}
+
+	canonical, hasCanonical := findCanonical(content)
+	host := ""
+	if u, err := url.Parse(canonical); err == nil {
+		host = u.Host
+	}
+	alternates := hasCanonical && len(l.Locales) > 0
+	inserted := false
+
+	var b strings.Builder
+	z := html.NewTokenizer(strings.NewReader(content))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		raw := string(z.Raw())
+
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			b.WriteString(raw)
+			continue
+		}
+
+		tok := z.Token()
+		switch {
+		case tok.Data == "link" && hasRel(tok, "canonical") && hasAttr(tok, "href"):
+			setAttr(&tok, "href", l.URL(getAttr(tok, "href"), targetLang))
+			b.WriteString(tok.String())
+			if alternates && !inserted {
+				l.writeAlternates(&b, canonical)
+				inserted = true
+			}
+		case tok.Data == "link" && alternates && hasRel(tok, "alternate") && hasAttr(tok, "hreflang"):
+			// Replaced by the generated alternates
+		case tok.Data == "a" && hasAttr(tok, "href") && isInternalURL(getAttr(tok, "href"), host):
+			setAttr(&tok, "href", l.URL(getAttr(tok, "href"), targetLang))
+			b.WriteString(tok.String())
+		default:
+			b.WriteString(raw)
+		}
+	}
+
+	return b.String(), nil
+}
+
+// writeAlternates writes one alternate link per locale, plus x-default.
+func (l *LocalizedLinks) writeAlternates(b *strings.Builder, canonical string) {
+	write := func(hreflang, href string) {
+		b.WriteString("\n")
+		b.WriteString(html.Token{
+			Type: html.StartTagToken,
+			Data: "link",
+			Attr: []html.Attribute{
+				{Key: "rel", Val: "alternate"},
+				{Key: "hreflang", Val: hreflang},
+				{Key: "href", Val: href},
+			},
+		}.String())
+	}
+
+	for _, locale := range l.Locales {
+		write(ToHTMLLang(locale), l.URL(canonical, locale))
+	}
+	if l.XDefault != "" {
+		write("x-default", l.URL(canonical, l.XDefault))
+	}
+}
+
+// findCanonical returns the href of the first <link rel="canonical">.
+func findCanonical(content string) (string, bool) {
+	z := html.NewTokenizer(strings.NewReader(content))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return "", false
+		}
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+		tok := z.Token()
+		if tok.Data == "link" && hasRel(tok, "canonical") && hasAttr(tok, "href") {
+			return getAttr(tok, "href"), true
+		}
+	}
+}
+
+// isInternalURL reports whether a link points to the same site: a relative URL,
+// or an absolute one on host.
+func isInternalURL(rawURL, host string) bool {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return false
+	}
+	if u.Scheme == "" && u.Host == "" {
+		return u.Path != ""
+	}
+	if u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https" {
+		return false
+	}
+	return host != "" && strings.EqualFold(u.Host, host)
+}
+
+// hasRel reports whether a tag's rel attribute contains value.
+func hasRel(tok html.Token, value string) bool {
+	for _, rel := range strings.Fields(getAttr(tok, "rel")) {
+		if strings.EqualFold(rel, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAttr reports whether a tag has the attribute.
+func hasAttr(tok html.Token, key string) bool {
+	for _, attr := range tok.Attr {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// getAttr returns a tag's attribute value, or "" if missing.
+func getAttr(tok html.Token, key string) string {
+	for _, attr := range tok.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// setAttr sets a tag's attribute value, adding it if missing.
+func setAttr(tok *html.Token, key, val string) {
+	for i, attr := range tok.Attr {
+		if attr.Key == key {
+			tok.Attr[i].Val = val
+			return
+		}
+	}
+	tok.Attr = append(tok.Attr, html.Attribute{Key: key, Val: val})
+}
+
+// Verify LocalizedLinks implements HTMLPostProcessor
+var _ HTMLPostProcessor = (*LocalizedLinks)(nil)
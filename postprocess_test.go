@@ -0,0 +1,126 @@
+package gotlai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// localePath maps https://example.com/path to https://example.com/<lang>/path.
+func localePath(rawURL, locale string) string {
+	lang := strings.ToLower(ToHTMLLang(locale))
+	if i := strings.Index(rawURL, "://"); i >= 0 {
+		if j := strings.Index(rawURL[i+3:], "/"); j >= 0 {
+			at := i + 3 + j
+			return rawURL[:at] + "/" + lang + rawURL[at:]
+		}
+	}
+	return "/" + lang + rawURL
+}
+
+const linksHTML = `<html><head>
+<link rel="canonical" href="https://example.com/about">
+<link rel="alternate" hreflang="fr-FR" href="https://example.com/fr/about">
+<link rel=stylesheet href=/style.css>
+</head><body>
+<a href="/contact">Contact</a>
+<a href="https://example.com/team">Team</a>
+<a href="https://other.com/x">Other</a>
+<a href="#top">Top</a>
+<a href="mailto:hi@example.com">Mail</a>
+</body></html>`
+
+func TestLocalizedLinks(t *testing.T) {
+	links := &LocalizedLinks{
+		Locales:  []string{"en_US", "es_ES"},
+		XDefault: "en_US",
+		URL:      localePath,
+	}
+
+	result, err := links.PostProcessHTML(linksHTML, "es_ES")
+	if err != nil {
+		t.Fatalf("PostProcessHTML failed: %v", err)
+	}
+
+	expected := []string{
+		`<link rel="canonical" href="https://example.com/es-es/about">`,
+		`<link rel="alternate" hreflang="en-US" href="https://example.com/en-us/about">`,
+		`<link rel="alternate" hreflang="es-ES" href="https://example.com/es-es/about">`,
+		`<link rel="alternate" hreflang="x-default" href="https://example.com/en-us/about">`,
+		`<link rel=stylesheet href=/style.css>`,
+		`<a href="/es-es/contact">`,
+		`<a href="https://example.com/es-es/team">`,
+		`<a href="https://other.com/x">`,
+		`<a href="#top">`,
+		`<a href="mailto:hi@example.com">`,
+	}
+	for _, s := range expected {
+		if !strings.Contains(result, s) {
+			t.Errorf("Expected %q in result, got:\n%s", s, result)
+		}
+	}
+	if strings.Contains(result, "fr-FR") {
+		t.Errorf("Existing hreflang alternates should be replaced, got:\n%s", result)
+	}
+}
+
+func TestLocalizedLinks_NoCanonical(t *testing.T) {
+	links := &LocalizedLinks{Locales: []string{"en_US", "es_ES"}, URL: localePath}
+
+	result, err := links.PostProcessHTML(`<p><a href="/contact">Contact</a></p>`, "es_ES")
+	if err != nil {
+		t.Fatalf("PostProcessHTML failed: %v", err)
+	}
+	if result != `<p><a href="/es-es/contact">Contact</a></p>` {
+		t.Errorf("Unexpected result: %s", result)
+	}
+}
+
+func TestTranslator_HTMLPostProcessors(t *testing.T) {
+	var order []string
+	first := HTMLPostProcessorFunc(func(content, targetLang string) (string, error) {
+		order = append(order, "first")
+		if !strings.Contains(content, `lang="es-ES"`) {
+			t.Errorf("lang should be set before post-processors, got: %s", content)
+		}
+		return content + "<!-- " + targetLang + " -->", nil
+	})
+	second := HTMLPostProcessorFunc(func(content, targetLang string) (string, error) {
+		order = append(order, "second")
+		return content, nil
+	})
+
+	translator := NewTranslator("es_ES", newMockProvider(),
+		WithProcessor(&mockHTMLProcessor{}),
+		WithHTMLPostProcessors(first, second),
+	)
+
+	result, err := translator.Process(context.Background(), "<html><p>Hello</p></html>", "html")
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if !strings.HasSuffix(result.Content, "<!-- es_ES -->") {
+		t.Errorf("Post-processor output should be returned, got: %s", result.Content)
+	}
+	if strings.Join(order, ",") != "first,second" {
+		t.Errorf("Post-processors should run in order, got %v", order)
+	}
+}
+
+func TestTranslator_HTMLPostProcessorError(t *testing.T) {
+	failing := HTMLPostProcessorFunc(func(content, targetLang string) (string, error) {
+		return "", errors.New("boom")
+	})
+
+	translator := NewTranslator("es_ES", newMockProvider(),
+		WithProcessor(&mockHTMLProcessor{}),
+		WithHTMLPostProcessors(failing),
+	)
+
+	_, err := translator.Process(context.Background(), "<p>Hello</p>", "html")
+	var procErr *ProcessorError
+	if !errors.As(err, &procErr) {
+		t.Errorf("Expected ProcessorError, got %v", err)
+	}
+}
@@ -0,0 +1,591 @@
+package processor
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ZaguanLabs/gotlai"
+)
+
+// androidPairedTags contains styling tags whose content is translatable in
+// Android string resources. Other elements, such as <xliff:g>, are kept whole.
+var androidPairedTags = map[string]bool{
+	"a":          true,
+	"annotation": true,
+	"b":          true,
+	"big":        true,
+	"font":       true,
+	"i":          true,
+	"small":      true,
+	"strike":     true,
+	"sub":        true,
+	"sup":        true,
+	"tt":         true,
+	"u":          true,
+}
+
+// AndroidStringsProcessor extracts and applies translations to Android string
+// resource files (res/values/strings.xml). Values of <string>, <string-array>
+// items and <plurals> items are translated; the preceding comment becomes node
+// context. Android escapes (\', \", \n, \uXXXX), quoted values and CDATA are
+// decoded for translation and written back in the same form, and styling tags
+// become placeholders. Resources marked translatable="false" are removed from
+// the output, as Android lint rejects them in translated resource files. With a
+// target language, <plurals> get one item per quantity the language uses, and
+// quantities the source lacks, such as "few", are translated on their own.
+type AndroidStringsProcessor struct{}
+
+// NewAndroidStringsProcessor creates a new Android string resource processor.
+func NewAndroidStringsProcessor() *AndroidStringsProcessor {
+	return &AndroidStringsProcessor{}
+}
+
+// androidValue is the content of a <string> or <item> element.
+type androidValue struct {
+	seg               *codeSegment
+	leading, trailing string // Whitespace around the value
+	quoted            bool   // Value is wrapped in double quotes
+	cdata             bool   // Value is a CDATA section
+	hash              string
+}
+
+// androidItem is a <string> element or an <item> of an array or plurals.
+type androidItem struct {
+	start, openEnd int // Element start and end of its start tag
+	closeStart     int // Start of the end tag
+	end            int
+	quantity       string
+	value          *androidValue // Nil when the item is not translated
+}
+
+// androidResource is a <string>, <string-array> or <plurals> element.
+type androidResource struct {
+	kind         string
+	name         string
+	comment      string
+	start, end   int
+	translatable bool
+	items        []*androidItem
+}
+
+// parsedAndroid holds the resource file source and its string resources.
+type parsedAndroid struct {
+	src       string
+	resources []*androidResource
+}
+
+// Extract parses Android string resources and extracts their values.
+func (p *AndroidStringsProcessor) Extract(content string) (interface{}, []gotlai.TextNode, error) {
+	pa, err := parseAndroidStrings(content)
+	if err != nil {
+		return nil, nil, &gotlai.ProcessorError{
+			Message:     "failed to parse Android string resources",
+			Cause:       err,
+			ContentType: "android",
+		}
+	}
+
+	var nodes []gotlai.TextNode
+	seenHashes := make(map[string]bool)
+	for _, res := range pa.resources {
+		for i, item := range res.items {
+			if item.value == nil || seenHashes[item.value.hash] {
+				continue
+			}
+			seenHashes[item.value.hash] = true
+
+			node := gotlai.TextNode{
+				ID:       fmt.Sprintf("node-%d", len(nodes)),
+				Text:     item.value.seg.text,
+				Hash:     item.value.hash,
+				NodeType: "android_string",
+				Context:  androidContext(res, item, i),
+				Metadata: map[string]string{
+					"name":         res.name,
+					"placeholders": encodePlaceholders(item.value.seg.paired),
+				},
+			}
+			switch res.kind {
+			case "string-array":
+				node.Metadata["index"] = strconv.Itoa(i)
+			case "plurals":
+				node.Metadata["quantity"] = item.quantity
+			}
+			nodes = append(nodes, node)
+		}
+	}
+
+	return pa, nodes, nil
+}
+
+// androidContext describes a resource value for the AI.
+func androidContext(res *androidResource, item *androidItem, index int) string {
+	parts := []string{fmt.Sprintf("Android string resource %q", res.name)}
+	switch res.kind {
+	case "string-array":
+		parts[0] = fmt.Sprintf("Android string array %q, item %d", res.name, index)
+	case "plurals":
+		parts[0] = fmt.Sprintf("Android plurals %q, quantity %q", res.name, item.quantity)
+	}
+	if res.comment != "" {
+		parts = append(parts, res.comment)
+	}
+	parts = append(parts, "keep format arguments such as %1$s and %d unchanged")
+	if len(item.value.seg.paired) > 0 {
+		parts = append(parts, "contains markup placeholders")
+	}
+	return strings.Join(parts, " | ")
+}
+
+// parseAndroidStrings scans a resource file for string resources.
+func parseAndroidStrings(src string) (*parsedAndroid, error) {
+	pa := &parsedAndroid{src: src}
+	dec := xml.NewDecoder(strings.NewReader(src))
+
+	var res *androidResource
+	var comment string
+	depth := 0
+
+	for {
+		start := int(dec.InputOffset())
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		end := int(dec.InputOffset())
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case depth == 0:
+				depth++
+			case depth == 1 && (t.Name.Local == "string" || t.Name.Local == "string-array" || t.Name.Local == "plurals"):
+				res = &androidResource{
+					kind:         t.Name.Local,
+					name:         xmlAttr(t.Attr, "name"),
+					comment:      comment,
+					start:        start,
+					translatable: xmlAttr(t.Attr, "translatable") != "false",
+				}
+				comment = ""
+				if res.kind != "string" {
+					depth++
+					continue
+				}
+
+				item, err := parseAndroidItem(dec, src, start, end, res.translatable)
+				if err != nil {
+					return nil, err
+				}
+				res.items = append(res.items, item)
+				res.end = item.end
+				pa.resources = append(pa.resources, res)
+				res = nil
+			case depth == 2 && res != nil && t.Name.Local == "item":
+				item, err := parseAndroidItem(dec, src, start, end, res.translatable)
+				if err != nil {
+					return nil, err
+				}
+				item.quantity = xmlAttr(t.Attr, "quantity")
+				res.items = append(res.items, item)
+			default:
+				comment = ""
+				if err := dec.Skip(); err != nil {
+					return nil, err
+				}
+			}
+		case xml.EndElement:
+			depth--
+			if depth == 1 && res != nil {
+				res.end = end
+				pa.resources = append(pa.resources, res)
+				res = nil
+			}
+		case xml.Comment:
+			if depth == 1 {
+				comment = strings.Join(strings.Fields(string(t)), " ")
+			}
+		case xml.CharData:
+			// A blank line detaches a comment from the next resource
+			if depth == 1 && strings.Count(string(t), "\n") > 1 {
+				comment = ""
+			}
+		}
+	}
+
+	return pa, nil
+}
+
+// parseAndroidItem reads an element whose start tag spans src[start:openEnd].
+func parseAndroidItem(dec *xml.Decoder, src string, start, openEnd int, translatable bool) (*androidItem, error) {
+	if err := dec.Skip(); err != nil {
+		return nil, err
+	}
+	item := &androidItem{start: start, openEnd: openEnd, end: int(dec.InputOffset())}
+	item.closeStart = strings.LastIndex(src[:item.end], "</")
+	if item.closeStart < openEnd {
+		item.closeStart = item.end // Self-closing
+		return item, nil
+	}
+	if !translatable {
+		return item, nil
+	}
+
+	value, err := parseAndroidValue(src[openEnd:item.closeStart])
+	if err != nil {
+		return nil, err
+	}
+	item.value = value
+	return item, nil
+}
+
+// parseAndroidValue decodes an element's content. It returns nil for empty
+// values and references such as @string/name.
+func parseAndroidValue(inner string) (*androidValue, error) {
+	trimmed := strings.TrimSpace(inner)
+	if trimmed == "" || trimmed[0] == '@' || trimmed[0] == '?' {
+		return nil, nil
+	}
+
+	v := &androidValue{}
+	v.leading = inner[:strings.Index(inner, trimmed)]
+	v.trailing = inner[len(v.leading)+len(trimmed):]
+
+	var err error
+	switch {
+	case strings.HasPrefix(trimmed, "<![CDATA[") && strings.HasSuffix(trimmed, "]]>") && strings.Count(trimmed, "<![CDATA[") == 1:
+		v.cdata = true
+		v.seg = buildAndroidHTMLSegment(trimmed[len("<![CDATA[") : len(trimmed)-len("]]>")])
+	case len(trimmed) >= 2 && trimmed[0] == '"' && trimmed[len(trimmed)-1] == '"' && !strings.Contains(trimmed, "<"):
+		v.quoted = true
+		v.seg, err = buildXMLSegment(trimmed[1:len(trimmed)-1], nil, androidUnescape)
+	default:
+		v.seg, err = buildXMLSegment(trimmed, androidPairedTags, androidUnescape)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasText(v.seg) {
+		return nil, nil
+	}
+	v.hash = gotlai.HashText(v.seg.text)
+	return v, nil
+}
+
+// buildAndroidHTMLSegment flattens CDATA content, which holds HTML for
+// Html.fromHtml, into text with placeholders for its tags.
+func buildAndroidHTMLSegment(s string) *codeSegment {
+	seg := &codeSegment{}
+	var b strings.Builder
+	for s != "" {
+		i := strings.IndexByte(s, '<')
+		if i < 0 {
+			i = len(s)
+		}
+		b.WriteString(escapeSegmentText(androidUnescape(s[:i])))
+		s = s[i:]
+		if s == "" {
+			break
+		}
+
+		n := len(markdownHTMLPattern.FindString(s))
+		if n == 0 {
+			b.WriteString("&lt;")
+			s = s[1:]
+			continue
+		}
+		seg.writeAtom(&b, s[:n])
+		s = s[n:]
+	}
+	seg.text = b.String()
+	return seg
+}
+
+// render rebuilds the element content from a translated segment.
+func (v *androidValue) render(items []segmentItem) string {
+	out := v.seg.render(items, func(s string) string {
+		return androidEscape(s, v.quoted, v.cdata)
+	})
+
+	switch {
+	case v.cdata:
+		out = "<![CDATA[" + strings.ReplaceAll(out, "]]>", "]]]]><![CDATA[>") + "]]>"
+	case v.quoted:
+		out = `"` + out + `"`
+	case strings.HasPrefix(out, "@") || strings.HasPrefix(out, "?"):
+		out = `\` + out
+	}
+	return v.leading + out + v.trailing
+}
+
+// androidUnescape decodes Android string escapes.
+func androidUnescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'u':
+			if r, err := strconv.ParseUint(s[i+1:min(i+5, len(s))], 16, 32); err == nil && i+5 <= len(s) {
+				b.WriteRune(rune(r))
+				i += 4
+				continue
+			}
+			b.WriteByte('u')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// androidEscape encodes text for a string resource. Apostrophes need no escape
+// inside quoted values, and CDATA content is not XML-escaped.
+func androidEscape(s string, quoted, cdata bool) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`).Replace(s)
+	if !quoted {
+		s = strings.ReplaceAll(s, "'", `\'`)
+	}
+	if !cdata {
+		s = escapeSegmentText(s)
+	}
+	return s
+}
+
+// Apply splices translations into the resource file without changing plural quantities.
+func (p *AndroidStringsProcessor) Apply(parsed interface{}, nodes []gotlai.TextNode, translations map[string]string) (string, error) {
+	return p.ApplyTarget(parsed, nodes, translations, "")
+}
+
+// ApplyTarget splices translations into the resource file. With a target
+// language, <plurals> are rewritten with the quantities that language uses.
+func (p *AndroidStringsProcessor) ApplyTarget(parsed interface{}, nodes []gotlai.TextNode, translations map[string]string, targetLang string) (string, error) {
+	pa, ok := parsed.(*parsedAndroid)
+	if !ok {
+		return "", &gotlai.ProcessorError{
+			Message:     "invalid parsed content type",
+			ContentType: "android",
+		}
+	}
+
+	type edit struct {
+		start, end int
+		text       string
+	}
+	var edits []edit
+
+	// translate renders the translation with the given hash of an item's value
+	translate := func(item *androidItem, hash string) (string, bool) {
+		if item.value == nil {
+			return "", false
+		}
+		translated, ok := translations[hash]
+		if !ok {
+			return "", false
+		}
+		items, err := parseSegment(translated, item.value.seg.paired)
+		if err != nil {
+			return "", false
+		}
+		return item.value.render(items), true
+	}
+
+	for _, res := range pa.resources {
+		if !res.translatable {
+			start, end := lineSpan(pa.src, res.start, res.end)
+			edits = append(edits, edit{start, end, ""})
+			continue
+		}
+
+		if res.kind == "plurals" && targetLang != "" {
+			if text, ok := p.rewritePlurals(pa.src, res, targetLang, translate); ok {
+				first, last := res.items[0], res.items[len(res.items)-1]
+				edits = append(edits, edit{first.start, last.end, text})
+			}
+			continue
+		}
+
+		for _, item := range res.items {
+			if item.value == nil {
+				continue
+			}
+			if text, ok := translate(item, item.value.hash); ok {
+				edits = append(edits, edit{item.openEnd, item.closeStart, text})
+			}
+		}
+	}
+
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+
+	var b strings.Builder
+	pos := 0
+	for _, e := range edits {
+		b.WriteString(pa.src[pos:e.start])
+		b.WriteString(e.text)
+		pos = e.end
+	}
+	b.WriteString(pa.src[pos:])
+
+	return b.String(), nil
+}
+
+// rewritePlurals renders the items of a <plurals> element for the target
+// language's quantities. Quantities missing from the source are translated
+// from the "other" item on their own (see TargetNodes); without that
+// translation they get the "other" translation and a comment asking for review.
+// It reports false when no item was translated.
+func (p *AndroidStringsProcessor) rewritePlurals(src string, res *androidResource, targetLang string, translate func(*androidItem, string) (string, bool)) (string, bool) {
+	if len(res.items) == 0 {
+		return "", false
+	}
+
+	forms := make(map[string]*androidItem)
+	for _, item := range res.items {
+		forms[item.quantity] = item
+	}
+
+	// Items are separated like the first two source items
+	sep := "\n" + lineIndent(src, res.items[0].start)
+	if len(res.items) > 1 {
+		if between := src[res.items[0].end:res.items[1].start]; strings.TrimSpace(between) == "" {
+			sep = between
+		}
+	}
+
+	var parts []string
+	translated := false
+	for _, category := range pluralCategories(targetLang) {
+		item, own, ok := pluralSource(forms, category)
+		if !ok {
+			continue
+		}
+		if item.value == nil {
+			parts = append(parts, setXMLAttr(src[item.start:item.openEnd], "quantity", category)+src[item.openEnd:item.end])
+			continue
+		}
+
+		content, ok := "", false
+		if !own {
+			content, ok = translate(item, pluralFormHash(item.value.seg.text, category))
+		}
+		if !ok {
+			content, ok = translate(item, item.value.hash)
+			if ok && !own {
+				parts = append(parts, pluralReviewComment(category))
+			}
+		}
+		if ok {
+			translated = true
+		} else {
+			content = src[item.openEnd:item.closeStart]
+		}
+		open := setXMLAttr(src[item.start:item.openEnd], "quantity", category)
+		parts = append(parts, open+content+src[item.closeStart:item.end])
+	}
+
+	return strings.Join(parts, sep), translated
+}
+
+// TargetNodes returns a node per <plurals> quantity the target language uses
+// beyond those of the source, such as "few" and "many" in Russian. Each is
+// the "other" item, described by the counts the quantity is used for.
+func (p *AndroidStringsProcessor) TargetNodes(parsed interface{}, nodes []gotlai.TextNode, targetLang string) []gotlai.TextNode {
+	pa, ok := parsed.(*parsedAndroid)
+	if !ok || targetLang == "" {
+		return nil
+	}
+
+	var extra []gotlai.TextNode
+	seenHashes := make(map[string]bool)
+	for _, res := range pa.resources {
+		if res.kind != "plurals" || !res.translatable {
+			continue
+		}
+		forms := make(map[string]*androidItem)
+		for _, item := range res.items {
+			forms[item.quantity] = item
+		}
+
+		for _, category := range pluralCategories(targetLang) {
+			item, own, ok := pluralSource(forms, category)
+			if !ok || own || item.value == nil {
+				continue
+			}
+			hash := pluralFormHash(item.value.seg.text, category)
+			if seenHashes[hash] {
+				continue
+			}
+			seenHashes[hash] = true
+
+			extra = append(extra, gotlai.TextNode{
+				ID:       fmt.Sprintf("node-%d", len(nodes)+len(extra)),
+				Text:     item.value.seg.text,
+				Hash:     hash,
+				NodeType: "android_string",
+				Context:  androidContext(res, item, 0) + " | " + pluralFormContext(targetLang, category),
+				Metadata: map[string]string{
+					"name":         res.name,
+					"placeholders": encodePlaceholders(item.value.seg.paired),
+					"quantity":     category,
+				},
+			})
+		}
+	}
+	return extra
+}
+
+// ValidateTranslation checks that a translation keeps every markup placeholder.
+func (p *AndroidStringsProcessor) ValidateTranslation(node gotlai.TextNode, translated string) error {
+	if node.NodeType != "android_string" {
+		return nil
+	}
+	_, err := parseSegment(translated, decodePlaceholders(node.Metadata["placeholders"]))
+	return err
+}
+
+// ContentType returns "android".
+func (p *AndroidStringsProcessor) ContentType() string {
+	return "android"
+}
+
+// lineSpan widens src[start:end] to whole lines when nothing else is on them.
+func lineSpan(src string, start, end int) (int, int) {
+	lineStart := strings.LastIndexByte(src[:start], '\n') + 1
+	lineEnd := strings.IndexByte(src[end:], '\n')
+	if lineEnd < 0 {
+		return start, end
+	}
+	lineEnd += end + 1
+	if strings.TrimSpace(src[lineStart:start]) != "" || strings.TrimSpace(src[end:lineEnd]) != "" {
+		return start, end
+	}
+	return lineStart, lineEnd
+}
+
+// Verify AndroidStringsProcessor implements ContentProcessor, TranslationValidator,
+// TargetApplier and TargetNodeProvider
+var (
+	_ ContentProcessor            = (*AndroidStringsProcessor)(nil)
+	_ gotlai.TranslationValidator = (*AndroidStringsProcessor)(nil)
+	_ gotlai.TargetApplier        = (*AndroidStringsProcessor)(nil)
+	_ gotlai.TargetNodeProvider   = (*AndroidStringsProcessor)(nil)
+)
@@ -0,0 +1,209 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+)
+
+const androidDoc = `<?xml version="1.0" encoding="utf-8"?>
+<resources xmlns:xliff="urn:oasis:names:tc:xliff:document:1.2">
+    <string name="app_name" translatable="false">Notes</string>
+    <!-- Greeting on the home screen -->
+    <string name="welcome">Welcome, %1$s! Don\'t forget to save.</string>
+    <string name="styled">Tap <b>Save</b> to keep <xliff:g id="count">%d</xliff:g> notes</string>
+    <string name="quoted">"It's done"</string>
+    <string name="html"><![CDATA[Read the <a href="%s">terms</a>]]></string>
+    <string name="alias">@string/welcome</string>
+
+    <string-array name="sorts">
+        <item>Newest</item>
+        <item>Oldest</item>
+    </string-array>
+    <plurals name="notes">
+        <item quantity="one">%d note</item>
+        <item quantity="other">%d notes</item>
+    </plurals>
+</resources>
+`
+
+func TestAndroidStringsProcessor_Extract(t *testing.T) {
+	p := NewAndroidStringsProcessor()
+
+	_, nodes, err := p.Extract(androidDoc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	var texts []string
+	for _, n := range nodes {
+		texts = append(texts, n.Text)
+	}
+
+	expected := []string{
+		"Welcome, %1$s! Don't forget to save.",
+		"Tap <x1>Save</x1> to keep <x2/> notes",
+		"It's done",
+		"Read the <x1/>terms<x2/>",
+		"Newest", "Oldest",
+		"%d note", "%d notes",
+	}
+	if strings.Join(texts, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, texts)
+	}
+
+	if !strings.HasPrefix(nodes[0].Context, `Android string resource "welcome" | Greeting on the home screen`) {
+		t.Errorf("Unexpected context %q", nodes[0].Context)
+	}
+	if nodes[5].Metadata["index"] != "1" || nodes[7].Metadata["quantity"] != "other" {
+		t.Errorf("Unexpected metadata %v %v", nodes[5].Metadata, nodes[7].Metadata)
+	}
+}
+
+func TestAndroidStringsProcessor_Apply(t *testing.T) {
+	p := NewAndroidStringsProcessor()
+
+	parsed, nodes, err := p.Extract(androidDoc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	translations := map[string]string{
+		nodes[0].Hash: "Bienvenue, %1$s ! N'oubliez pas d'enregistrer.",
+		nodes[1].Hash: "Touchez <x1>Enregistrer</x1> pour garder <x2/> notes",
+		nodes[2].Hash: "C'est fait",
+		nodes[3].Hash: "Lisez les <x1/>conditions<x2/> d'utilisation",
+		nodes[4].Hash: "@ récents",
+		nodes[6].Hash: "%d note",
+		nodes[7].Hash: "%d notes",
+	}
+
+	result, err := p.Apply(parsed, nodes, translations)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	for _, want := range []string{
+		`<string name="welcome">Bienvenue, %1$s ! N\'oubliez pas d\'enregistrer.</string>`,
+		`<string name="styled">Touchez <b>Enregistrer</b> pour garder <xliff:g id="count">%d</xliff:g> notes</string>`,
+		`<string name="quoted">"C'est fait"</string>`,
+		`<string name="html"><![CDATA[Lisez les <a href="%s">conditions</a> d\'utilisation]]></string>`,
+		`<string name="alias">@string/welcome</string>`,
+		`<item>\@ récents</item>`,
+		`<item>Oldest</item>`,
+		`<item quantity="one">%d note</item>`,
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %s in result, got:\n%s", want, result)
+		}
+	}
+
+	if strings.Contains(result, "app_name") {
+		t.Errorf("Expected untranslatable string to be removed, got:\n%s", result)
+	}
+	if !strings.HasPrefix(result, "<?xml version=\"1.0\" encoding=\"utf-8\"?>\n<resources xmlns:xliff=\"urn:oasis:names:tc:xliff:document:1.2\">\n    <!-- Greeting") {
+		t.Errorf("Expected header to be kept, got:\n%s", result)
+	}
+}
+
+func TestAndroidStringsProcessor_ApplyTargetPlurals(t *testing.T) {
+	p := NewAndroidStringsProcessor()
+
+	parsed, nodes, err := p.Extract(androidDoc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	extra := p.TargetNodes(parsed, nodes, "ru")
+	if len(extra) != 2 || extra[0].Metadata["quantity"] != "few" || extra[1].Metadata["quantity"] != "many" {
+		t.Fatalf("Expected few and many nodes, got %+v", extra)
+	}
+	if extra[0].Text != nodes[7].Text || !strings.Contains(extra[0].Context, "counts such as 2, 3, 4") {
+		t.Errorf("Unexpected few node %+v", extra[0])
+	}
+
+	translations := map[string]string{
+		nodes[6].Hash: "%d заметка",
+		nodes[7].Hash: "%d заметки",
+		extra[0].Hash: "%d заметки",
+		extra[1].Hash: "%d заметок",
+	}
+
+	result, err := p.ApplyTarget(parsed, append(nodes, extra...), translations, "ru")
+	if err != nil {
+		t.Fatalf("ApplyTarget failed: %v", err)
+	}
+
+	expected := `    <plurals name="notes">
+        <item quantity="one">%d заметка</item>
+        <item quantity="few">%d заметки</item>
+        <item quantity="many">%d заметок</item>
+        <item quantity="other">%d заметки</item>
+    </plurals>`
+	if !strings.Contains(result, expected) {
+		t.Errorf("Expected Russian quantities, got:\n%s", result)
+	}
+
+	// Quantities without their own translation are copied from "other" and marked
+	delete(translations, extra[1].Hash)
+	result, err = p.ApplyTarget(parsed, nodes, translations, "ru")
+	if err != nil {
+		t.Fatalf("ApplyTarget failed: %v", err)
+	}
+	if !strings.Contains(result, `        <!-- gotlai: "many" copied from "other", needs review -->
+        <item quantity="many">%d заметки</item>`) {
+		t.Errorf("Expected a review comment, got:\n%s", result)
+	}
+
+	result, err = p.ApplyTarget(parsed, nodes, translations, "ja")
+	if err != nil {
+		t.Fatalf("ApplyTarget failed: %v", err)
+	}
+	if !strings.Contains(result, "<plurals name=\"notes\">\n        <item quantity=\"other\">%d заметки</item>\n    </plurals>") {
+		t.Errorf("Expected a single other quantity, got:\n%s", result)
+	}
+}
+
+func TestAndroidUnescape(t *testing.T) {
+	tests := map[string]string{
+		`Don\'t`:        "Don't",
+		`Say \"hi\"`:    `Say "hi"`,
+		`a\nb\tc`:       "a\nb\tc",
+		`\u00e9t\u00e9`: "été",
+		`100\%`:         "100%",
+	}
+	for input, expected := range tests {
+		if got := androidUnescape(input); got != expected {
+			t.Errorf("androidUnescape(%q) = %q, want %q", input, got, expected)
+		}
+	}
+}
+
+func TestAndroidStringsProcessor_ValidateTranslation(t *testing.T) {
+	p := NewAndroidStringsProcessor()
+
+	_, nodes, err := p.Extract(androidDoc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if err := p.ValidateTranslation(nodes[1], "Touchez <x1>Enregistrer</x1> pour garder <x2/> notes"); err != nil {
+		t.Errorf("Expected valid translation, got %v", err)
+	}
+	if err := p.ValidateTranslation(nodes[1], "Touchez Enregistrer"); err == nil {
+		t.Error("Expected missing placeholders to be rejected")
+	}
+}
+
+func TestAndroidStringsProcessor_InvalidXML(t *testing.T) {
+	p := NewAndroidStringsProcessor()
+
+	if _, _, err := p.Extract("<resources><string name=\"a\">Hi</resources>"); err == nil {
+		t.Error("Expected error for malformed XML")
+	}
+}
+
+func TestAndroidStringsProcessor_ContentType(t *testing.T) {
+	if ct := NewAndroidStringsProcessor().ContentType(); ct != "android" {
+		t.Errorf("Expected 'android', got %q", ct)
+	}
+}
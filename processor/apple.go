@@ -0,0 +1,642 @@
+package processor
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ZaguanLabs/gotlai"
+)
+
+// appleFormatPattern matches format specifiers and .stringsdict variables such
+// as %@, %1$d, %lld and %#@count@.
+var appleFormatPattern = regexp.MustCompile(`%#@\w+@|%(?:\d+\$)?[-+# 0]*\d*(?:\.\d+)?(?:hh|h|ll|l|q|z|t|j|L)?[@dDuUxXoOfFeEgGcCsSpaA%]`)
+
+// applePluralCategories contains the plural category keys of .stringsdict files.
+var applePluralCategories = map[string]bool{
+	"zero":  true,
+	"one":   true,
+	"two":   true,
+	"few":   true,
+	"many":  true,
+	"other": true,
+}
+
+// AppleStringsProcessor extracts and applies translations to Apple localization
+// files. Values of .strings files ("key" = "value";) are translated, with the
+// comments before each entry as node context. Input that is a property list is
+// read as a .stringsdict file: the NSStringLocalizedFormatKey and plural
+// category strings of each entry are translated, and with a target language each
+// plural rule gets the categories that language uses, those the source lacks
+// being translated on their own. Keys, comments and formatting are kept, and
+// values are escaped the way Xcode expects.
+type AppleStringsProcessor struct{}
+
+// NewAppleStringsProcessor creates a new Apple strings processor.
+func NewAppleStringsProcessor() *AppleStringsProcessor {
+	return &AppleStringsProcessor{}
+}
+
+// appleString is a translatable value in a .strings or .stringsdict file.
+type appleString struct {
+	keyPath    string
+	comment    string
+	start, end int // Byte range of the escaped value
+	text       string
+	hash       string // Empty when the value is not translated
+	quantity   string // Plural category, in .stringsdict files
+
+	// Range of the <key> and <string> pair of a plural category
+	pairStart, pairEnd int
+}
+
+// applePlurals is a .stringsdict plural rule dictionary.
+type applePlurals struct {
+	forms      []*appleString
+	contiguous bool // No other keys between the categories
+}
+
+// parsedApple holds the source of a .strings or .stringsdict file and its values.
+type parsedApple struct {
+	src     string
+	plist   bool
+	strings []*appleString
+	plurals []*applePlurals
+}
+
+// Extract parses a .strings or .stringsdict file and extracts its values.
+func (p *AppleStringsProcessor) Extract(content string) (interface{}, []gotlai.TextNode, error) {
+	var pa *parsedApple
+	var err error
+	if isPropertyList(content) {
+		pa, err = parseStringsDict(content)
+	} else {
+		pa, err = parseAppleStrings(content)
+	}
+	if err != nil {
+		return nil, nil, &gotlai.ProcessorError{
+			Message:     "failed to parse Apple strings file",
+			Cause:       err,
+			ContentType: "apple",
+		}
+	}
+
+	var nodes []gotlai.TextNode
+	seenHashes := make(map[string]bool)
+	for _, s := range pa.strings {
+		if s.hash == "" || seenHashes[s.hash] {
+			continue
+		}
+		seenHashes[s.hash] = true
+
+		node := gotlai.TextNode{
+			ID:       fmt.Sprintf("node-%d", len(nodes)),
+			Text:     strings.TrimSpace(s.text),
+			Hash:     s.hash,
+			NodeType: "apple_string",
+			Context:  appleContext(s),
+			Metadata: map[string]string{
+				"key_path": s.keyPath,
+			},
+		}
+		if s.quantity != "" {
+			node.Metadata["quantity"] = s.quantity
+		}
+		nodes = append(nodes, node)
+	}
+
+	return pa, nodes, nil
+}
+
+// appleContext describes a value for the AI.
+func appleContext(s *appleString) string {
+	parts := []string{fmt.Sprintf("Apple localized string %q", s.keyPath)}
+	if s.quantity != "" {
+		parts = append(parts, fmt.Sprintf("plural form for quantity %q", s.quantity))
+	}
+	if s.comment != "" {
+		parts = append(parts, s.comment)
+	}
+	parts = append(parts, "keep format specifiers such as %@, %d, %1$@ and %#@name@ unchanged")
+	return strings.Join(parts, " | ")
+}
+
+// isPropertyList reports whether content is an XML property list.
+func isPropertyList(content string) bool {
+	trimmed := strings.TrimLeft(content, "\ufeff \t\r\n")
+	return strings.HasPrefix(trimmed, "<?xml") || strings.HasPrefix(trimmed, "<!DOCTYPE plist") || strings.HasPrefix(trimmed, "<plist")
+}
+
+// translatableAppleText reports whether a value has text besides format specifiers.
+func translatableAppleText(s string) bool {
+	return hasLetter(appleFormatPattern.ReplaceAllString(s, ""))
+}
+
+// parseAppleStrings scans "key" = "value"; entries of a .strings file.
+func parseAppleStrings(src string) (*parsedApple, error) {
+	pa := &parsedApple{src: src}
+	var comments []string
+
+	i := len(src) - len(strings.TrimPrefix(src, "\ufeff"))
+	for i < len(src) {
+		switch {
+		case strings.ContainsRune(" \t\r\n", rune(src[i])):
+			i++
+		case strings.HasPrefix(src[i:], "/*"), strings.HasPrefix(src[i:], "//"):
+			text, next, err := appleComment(src, i)
+			if err != nil {
+				return nil, err
+			}
+			if text != "" {
+				comments = append(comments, text)
+			}
+			i = next
+		default:
+			key, next, err := appleToken(src, i)
+			if err != nil {
+				return nil, err
+			}
+			i = skipAppleSpace(src, next)
+
+			// "key"; is shorthand for "key" = "key";
+			if i < len(src) && src[i] == ';' {
+				i++
+				comments = nil
+				continue
+			}
+			if i == len(src) || src[i] != '=' {
+				return nil, fmt.Errorf("expected '=' after key %q at offset %d", key, i)
+			}
+			i = skipAppleSpace(src, i+1)
+			if i == len(src) || src[i] != '"' {
+				return nil, fmt.Errorf("expected a quoted value for key %q at offset %d", key, i)
+			}
+			end, err := appleQuoteEnd(src, i)
+			if err != nil {
+				return nil, err
+			}
+
+			s := &appleString{
+				keyPath: key,
+				comment: strings.Join(comments, " "),
+				start:   i + 1,
+				end:     end,
+				text:    appleUnescape(src[i+1 : end]),
+			}
+			if translatableAppleText(s.text) {
+				s.hash = gotlai.HashText(strings.TrimSpace(s.text))
+			}
+			pa.strings = append(pa.strings, s)
+			comments = nil
+
+			i = skipAppleSpace(src, end+1)
+			if i == len(src) || src[i] != ';' {
+				return nil, fmt.Errorf("expected ';' after value of key %q at offset %d", key, i)
+			}
+			i++
+		}
+	}
+
+	return pa, nil
+}
+
+// appleComment reads the comment at src[i:], returning its normalized text and
+// the offset after it.
+func appleComment(src string, i int) (string, int, error) {
+	if strings.HasPrefix(src[i:], "//") {
+		end := strings.IndexByte(src[i:], '\n')
+		if end < 0 {
+			end = len(src) - i
+		}
+		return strings.Join(strings.Fields(src[i+2:i+end]), " "), i + end, nil
+	}
+
+	end := strings.Index(src[i+2:], "*/")
+	if end < 0 {
+		return "", 0, fmt.Errorf("unterminated comment at offset %d", i)
+	}
+	return strings.Join(strings.Fields(src[i+2:i+2+end]), " "), i + 2 + end + 2, nil
+}
+
+// skipAppleSpace returns the offset of the next character that is not
+// whitespace or part of a comment.
+func skipAppleSpace(src string, i int) int {
+	for i < len(src) {
+		switch {
+		case strings.ContainsRune(" \t\r\n", rune(src[i])):
+			i++
+		case strings.HasPrefix(src[i:], "/*"), strings.HasPrefix(src[i:], "//"):
+			_, next, err := appleComment(src, i)
+			if err != nil {
+				return len(src)
+			}
+			i = next
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// appleToken reads a quoted or unquoted key at src[i:].
+func appleToken(src string, i int) (string, int, error) {
+	if src[i] == '"' {
+		end, err := appleQuoteEnd(src, i)
+		if err != nil {
+			return "", 0, err
+		}
+		return appleUnescape(src[i+1 : end]), end + 1, nil
+	}
+
+	end := i
+	for end < len(src) && !strings.ContainsRune(" \t\r\n=;\"", rune(src[end])) {
+		end++
+	}
+	if end == i {
+		return "", 0, fmt.Errorf("unexpected %q at offset %d", src[i], i)
+	}
+	return src[i:end], end, nil
+}
+
+// appleQuoteEnd returns the offset of the quote closing the string at src[i].
+func appleQuoteEnd(src string, i int) (int, error) {
+	for j := i + 1; j < len(src); j++ {
+		switch src[j] {
+		case '\\':
+			j++
+		case '"':
+			return j, nil
+		}
+	}
+	return 0, fmt.Errorf("unterminated string at offset %d", i)
+}
+
+// appleUnescape decodes the escapes of a .strings value.
+func appleUnescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case 'u', 'U':
+			if i+5 <= len(s) {
+				if r, err := strconv.ParseUint(s[i+1:i+5], 16, 32); err == nil {
+					b.WriteRune(rune(r))
+					i += 4
+					continue
+				}
+			}
+			b.WriteByte(s[i])
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// appleEscape encodes text for a quoted .strings value.
+func appleEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`).Replace(s)
+}
+
+// parseStringsDict scans a .stringsdict property list for format and plural strings.
+func parseStringsDict(src string) (*parsedApple, error) {
+	pa := &parsedApple{src: src, plist: true}
+	dec := xml.NewDecoder(strings.NewReader(src))
+
+	type dictFrame struct {
+		key      string // Key of the dictionary in its parent
+		lastKey  string
+		keyStart int
+		pairs    int
+		plurals  *applePlurals
+		lastForm int // Pair index of the last plural category
+	}
+	var stack []*dictFrame
+
+	for {
+		start := int(dec.InputOffset())
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		end := int(dec.InputOffset())
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			var top *dictFrame
+			if len(stack) > 0 {
+				top = stack[len(stack)-1]
+			}
+
+			switch t.Name.Local {
+			case "plist":
+			case "dict":
+				frame := &dictFrame{}
+				if top != nil {
+					frame.key = top.lastKey
+					top.lastKey = ""
+					top.pairs++
+				}
+				stack = append(stack, frame)
+			case "key":
+				var key string
+				if err := dec.DecodeElement(&key, &t); err != nil {
+					return nil, err
+				}
+				if top != nil {
+					top.lastKey = strings.TrimSpace(key)
+					top.keyStart = start
+				}
+			case "string":
+				var value string
+				if err := dec.DecodeElement(&value, &t); err != nil {
+					return nil, err
+				}
+				if top == nil || top.lastKey == "" {
+					continue
+				}
+				elementEnd := int(dec.InputOffset())
+				closeStart := strings.LastIndex(src[:elementEnd], "</")
+				if closeStart < end {
+					closeStart = end // Self-closing
+				}
+
+				var path []string
+				for _, f := range stack[1:] {
+					path = append(path, f.key)
+				}
+				path = append(path, top.lastKey)
+
+				s := &appleString{
+					keyPath:   strings.Join(path, "."),
+					start:     end,
+					end:       closeStart,
+					text:      value,
+					pairStart: top.keyStart,
+					pairEnd:   elementEnd,
+				}
+
+				switch {
+				case top.lastKey == "NSStringLocalizedFormatKey":
+				case applePluralCategories[top.lastKey] && len(stack) > 2:
+					s.quantity = top.lastKey
+					if top.plurals == nil {
+						top.plurals = &applePlurals{contiguous: true}
+						pa.plurals = append(pa.plurals, top.plurals)
+					} else if top.lastForm != top.pairs-1 {
+						top.plurals.contiguous = false
+					}
+					top.plurals.forms = append(top.plurals.forms, s)
+					top.lastForm = top.pairs
+				default:
+					top.lastKey = ""
+					top.pairs++
+					continue
+				}
+
+				if translatableAppleText(value) {
+					s.hash = gotlai.HashText(strings.TrimSpace(value))
+				}
+				pa.strings = append(pa.strings, s)
+				top.lastKey = ""
+				top.pairs++
+			default:
+				if err := dec.Skip(); err != nil {
+					return nil, err
+				}
+				if top != nil {
+					top.lastKey = ""
+					top.pairs++
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "dict" && len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+
+	if len(stack) != 0 {
+		return nil, errors.New("unclosed dictionary")
+	}
+	return pa, nil
+}
+
+// Apply splices translations into the file without changing plural categories.
+func (p *AppleStringsProcessor) Apply(parsed interface{}, nodes []gotlai.TextNode, translations map[string]string) (string, error) {
+	return p.ApplyTarget(parsed, nodes, translations, "")
+}
+
+// ApplyTarget splices translations into the file. With a target language,
+// .stringsdict plural rules are rewritten with the categories that language
+// uses; a "zero" category in the source is kept, as it applies to any language.
+func (p *AppleStringsProcessor) ApplyTarget(parsed interface{}, nodes []gotlai.TextNode, translations map[string]string, targetLang string) (string, error) {
+	pa, ok := parsed.(*parsedApple)
+	if !ok {
+		return "", &gotlai.ProcessorError{
+			Message:     "invalid parsed content type",
+			ContentType: "apple",
+		}
+	}
+
+	type edit struct {
+		start, end int
+		text       string
+	}
+	var edits []edit
+
+	// translate renders the translation with the given hash of a value
+	translate := func(s *appleString, hash string) (string, bool) {
+		translated, ok := translations[hash]
+		if s.hash == "" || !ok {
+			return "", false
+		}
+		translated = preserveWhitespace(s.text, translated)
+		if pa.plist {
+			return escapeSegmentText(translated), true
+		}
+		return appleEscape(translated), true
+	}
+
+	rewritten := make(map[*appleString]bool)
+	if targetLang != "" {
+		for _, pl := range pa.plurals {
+			if text, ok := rewriteApplePlurals(pa.src, pl, targetLang, translate); ok {
+				edits = append(edits, edit{pl.forms[0].pairStart, pl.forms[len(pl.forms)-1].pairEnd, text})
+				for _, s := range pl.forms {
+					rewritten[s] = true
+				}
+			}
+		}
+	}
+
+	for _, s := range pa.strings {
+		if rewritten[s] {
+			continue
+		}
+		if text, ok := translate(s, s.hash); ok {
+			edits = append(edits, edit{s.start, s.end, text})
+		}
+	}
+
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+
+	var b strings.Builder
+	pos := 0
+	for _, e := range edits {
+		b.WriteString(pa.src[pos:e.start])
+		b.WriteString(e.text)
+		pos = e.end
+	}
+	b.WriteString(pa.src[pos:])
+
+	return b.String(), nil
+}
+
+// applePluralTargets returns the categories of a plural rule for the target
+// language, keeping a "zero" category of the source.
+func applePluralTargets(forms map[string]*appleString, targetLang string) []string {
+	categories := pluralCategories(targetLang)
+	if _, ok := forms["zero"]; ok && categories[0] != "zero" {
+		categories = append([]string{"zero"}, categories...)
+	}
+	return categories
+}
+
+// rewriteApplePlurals renders the category pairs of a plural rule for the
+// target language. Categories missing from the source are translated from the
+// "other" string on their own (see TargetNodes); without that translation they
+// get the "other" translation and a comment asking for review. It reports
+// false when the categories cannot be rewritten or none was translated.
+func rewriteApplePlurals(src string, pl *applePlurals, targetLang string, translate func(*appleString, string) (string, bool)) (string, bool) {
+	if !pl.contiguous {
+		return "", false
+	}
+
+	forms := make(map[string]*appleString)
+	for _, s := range pl.forms {
+		forms[s.quantity] = s
+	}
+
+	// Pairs are separated like the first two source pairs
+	sep := "\n" + lineIndent(src, pl.forms[0].pairStart)
+	if len(pl.forms) > 1 {
+		if between := src[pl.forms[0].pairEnd:pl.forms[1].pairStart]; strings.TrimSpace(between) == "" {
+			sep = between
+		}
+	}
+
+	var parts []string
+	translated := false
+	for _, category := range applePluralTargets(forms, targetLang) {
+		s, own, ok := pluralSource(forms, category)
+		if !ok {
+			continue
+		}
+		content, ok := "", false
+		if !own {
+			content, ok = translate(s, pluralFormHash(strings.TrimSpace(s.text), category))
+		}
+		if !ok {
+			content, ok = translate(s, s.hash)
+			if ok && !own {
+				parts = append(parts, pluralReviewComment(category))
+			}
+		}
+		if ok {
+			translated = true
+		} else {
+			content = src[s.start:s.end]
+		}
+
+		// The source pair with the category key replaced
+		keyOpen := s.pairStart + strings.IndexByte(src[s.pairStart:], '>') + 1
+		keyClose := s.pairStart + strings.Index(src[s.pairStart:], "</")
+		parts = append(parts, src[s.pairStart:keyOpen]+category+src[keyClose:s.start]+content+src[s.end:s.pairEnd])
+	}
+
+	return strings.Join(parts, sep), translated
+}
+
+// TargetNodes returns a node per .stringsdict plural category the target
+// language uses beyond those of the source, such as "few" and "many" in
+// Russian. Each is the "other" string, described by the counts the category
+// is used for.
+func (p *AppleStringsProcessor) TargetNodes(parsed interface{}, nodes []gotlai.TextNode, targetLang string) []gotlai.TextNode {
+	pa, ok := parsed.(*parsedApple)
+	if !ok || targetLang == "" {
+		return nil
+	}
+
+	var extra []gotlai.TextNode
+	seenHashes := make(map[string]bool)
+	for _, pl := range pa.plurals {
+		if !pl.contiguous {
+			continue
+		}
+		forms := make(map[string]*appleString)
+		for _, s := range pl.forms {
+			forms[s.quantity] = s
+		}
+
+		for _, category := range applePluralTargets(forms, targetLang) {
+			s, own, ok := pluralSource(forms, category)
+			if !ok || own || s.hash == "" {
+				continue
+			}
+			text := strings.TrimSpace(s.text)
+			hash := pluralFormHash(text, category)
+			if seenHashes[hash] {
+				continue
+			}
+			seenHashes[hash] = true
+
+			extra = append(extra, gotlai.TextNode{
+				ID:       fmt.Sprintf("node-%d", len(nodes)+len(extra)),
+				Text:     text,
+				Hash:     hash,
+				NodeType: "apple_string",
+				Context:  appleContext(s) + " | " + pluralFormContext(targetLang, category),
+				Metadata: map[string]string{
+					"key_path": s.keyPath,
+					"quantity": category,
+				},
+			})
+		}
+	}
+	return extra
+}
+
+// ContentType returns "apple".
+func (p *AppleStringsProcessor) ContentType() string {
+	return "apple"
+}
+
+// Verify AppleStringsProcessor implements ContentProcessor, TargetApplier and TargetNodeProvider
+var (
+	_ ContentProcessor          = (*AppleStringsProcessor)(nil)
+	_ gotlai.TargetApplier      = (*AppleStringsProcessor)(nil)
+	_ gotlai.TargetNodeProvider = (*AppleStringsProcessor)(nil)
+)
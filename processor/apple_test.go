@@ -0,0 +1,198 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+)
+
+const appleStringsDoc = `/* Title of the main window */
+"main.title" = "Welcome";
+
+// Shown when the user has unsaved changes
+"save.prompt" = "Save \"%@\" before closing?";
+count_format = "%d";
+"multiline" = "First line\nSecond line";
+`
+
+const appleStringsDictDoc = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>files_count</key>
+	<dict>
+		<key>NSStringLocalizedFormatKey</key>
+		<string>You have %#@files@</string>
+		<key>files</key>
+		<dict>
+			<key>NSStringFormatSpecTypeKey</key>
+			<string>NSStringPluralRuleType</string>
+			<key>NSStringFormatValueTypeKey</key>
+			<string>d</string>
+			<key>one</key>
+			<string>%d file</string>
+			<key>other</key>
+			<string>%d files &amp; folders</string>
+		</dict>
+	</dict>
+</dict>
+</plist>
+`
+
+func TestAppleStringsProcessor_Extract(t *testing.T) {
+	p := NewAppleStringsProcessor()
+
+	_, nodes, err := p.Extract(appleStringsDoc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	var texts []string
+	for _, n := range nodes {
+		texts = append(texts, n.Text)
+	}
+
+	expected := []string{"Welcome", `Save "%@" before closing?`, "First line\nSecond line"}
+	if strings.Join(texts, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, texts)
+	}
+
+	if !strings.HasPrefix(nodes[1].Context, `Apple localized string "save.prompt" | Shown when the user has unsaved changes`) {
+		t.Errorf("Unexpected context %q", nodes[1].Context)
+	}
+	if nodes[0].Metadata["key_path"] != "main.title" {
+		t.Errorf("Unexpected metadata %v", nodes[0].Metadata)
+	}
+}
+
+func TestAppleStringsProcessor_Apply(t *testing.T) {
+	p := NewAppleStringsProcessor()
+
+	parsed, nodes, err := p.Extract(appleStringsDoc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	translations := map[string]string{
+		nodes[0].Hash: "Bienvenue",
+		nodes[1].Hash: `Enregistrer « %@ » avant de fermer ? "Oui"`,
+		nodes[2].Hash: "Première ligne\nDeuxième ligne",
+	}
+
+	result, err := p.Apply(parsed, nodes, translations)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	expected := `/* Title of the main window */
+"main.title" = "Bienvenue";
+
+// Shown when the user has unsaved changes
+"save.prompt" = "Enregistrer « %@ » avant de fermer ? \"Oui\"";
+count_format = "%d";
+"multiline" = "Première ligne\nDeuxième ligne";
+`
+	if result != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, result)
+	}
+}
+
+func TestAppleStringsProcessor_StringsDict(t *testing.T) {
+	p := NewAppleStringsProcessor()
+
+	parsed, nodes, err := p.Extract(appleStringsDictDoc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	var texts []string
+	for _, n := range nodes {
+		texts = append(texts, n.Text)
+	}
+
+	expected := []string{"You have %#@files@", "%d file", "%d files & folders"}
+	if strings.Join(texts, "|") != strings.Join(expected, "|") {
+		t.Fatalf("Expected %q, got %q", expected, texts)
+	}
+	if nodes[2].Metadata["key_path"] != "files_count.files.other" || nodes[2].Metadata["quantity"] != "other" {
+		t.Errorf("Unexpected metadata %v", nodes[2].Metadata)
+	}
+
+	extra := p.TargetNodes(parsed, nodes, "ru")
+	if len(extra) != 2 || extra[0].Metadata["quantity"] != "few" || extra[1].Metadata["quantity"] != "many" || extra[1].ID != "node-4" {
+		t.Fatalf("Expected few and many nodes, got %+v", extra)
+	}
+
+	translations := map[string]string{
+		nodes[0].Hash: "У вас %#@files@",
+		nodes[1].Hash: "%d файл",
+		nodes[2].Hash: "%d файла и папки",
+		extra[0].Hash: "%d файла и папки",
+		extra[1].Hash: "%d файлов и папок",
+	}
+
+	result, err := p.ApplyTarget(parsed, append(nodes, extra...), translations, "ru")
+	if err != nil {
+		t.Fatalf("ApplyTarget failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"<string>У вас %#@files@</string>",
+		"<string>NSStringPluralRuleType</string>",
+		"\t\t\t<key>one</key>\n\t\t\t<string>%d файл</string>\n\t\t\t<key>few</key>\n\t\t\t<string>%d файла и папки</string>\n\t\t\t<key>many</key>\n\t\t\t<string>%d файлов и папок</string>\n\t\t\t<key>other</key>\n\t\t\t<string>%d файла и папки</string>\n\t\t</dict>",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in result, got:\n%s", want, result)
+		}
+	}
+
+	// Categories without their own translation are copied from "other" and marked
+	delete(translations, extra[1].Hash)
+	result, err = p.ApplyTarget(parsed, nodes, translations, "ru")
+	if err != nil {
+		t.Fatalf("ApplyTarget failed: %v", err)
+	}
+	if !strings.Contains(result, "\t\t\t<!-- gotlai: \"many\" copied from \"other\", needs review -->\n\t\t\t<key>many</key>\n\t\t\t<string>%d файла и папки</string>") {
+		t.Errorf("Expected a review comment, got:\n%s", result)
+	}
+
+	result, err = p.Apply(parsed, nodes, translations)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if strings.Contains(result, "<key>few</key>") || !strings.Contains(result, "<string>%d файл</string>") {
+		t.Errorf("Expected source categories to be kept, got:\n%s", result)
+	}
+}
+
+func TestAppleUnescape(t *testing.T) {
+	tests := map[string]string{
+		`Say \"hi\"`:    `Say "hi"`,
+		`a\nb\tc`:       "a\nb\tc",
+		`\U00e9t\U00e9`: "été",
+		`back\\slash`:   `back\slash`,
+	}
+	for input, expected := range tests {
+		if got := appleUnescape(input); got != expected {
+			t.Errorf("appleUnescape(%q) = %q, want %q", input, got, expected)
+		}
+		if input != `\U00e9t\U00e9` && appleEscape(expected) != input {
+			t.Errorf("appleEscape(%q) = %q, want %q", expected, appleEscape(expected), input)
+		}
+	}
+}
+
+func TestAppleStringsProcessor_InvalidInput(t *testing.T) {
+	p := NewAppleStringsProcessor()
+
+	for _, input := range []string{`"key" = "value"`, `"key" "value";`, `/* open`, `"key" = "open;`} {
+		if _, _, err := p.Extract(input); err == nil {
+			t.Errorf("Expected error for %q", input)
+		}
+	}
+}
+
+func TestAppleStringsProcessor_ContentType(t *testing.T) {
+	if ct := NewAppleStringsProcessor().ContentType(); ct != "apple" {
+		t.Errorf("Expected 'apple', got %q", ct)
+	}
+}
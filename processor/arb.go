@@ -0,0 +1,579 @@
+package processor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ZaguanLabs/gotlai"
+)
+
+// icuPluralKinds contains ICU argument types whose options hold translatable messages.
+var icuPluralKinds = map[string]bool{
+	"plural":        true,
+	"select":        true,
+	"selectordinal": true,
+}
+
+// ARBProcessor extracts and applies translations to Flutter ARB files
+// (Application Resource Bundle). Top-level messages are translated; the
+// description in each "@key" metadata object becomes node context. ICU
+// arguments ({name}, {count, number}) become placeholders, and plural and
+// select arguments are kept as paired placeholders around each translatable
+// option. With a target language, "@@locale" is set or added, and plural
+// arguments get the categories the language uses beyond those of the source,
+// such as "few" and "many" in Russian (selectordinal arguments are kept, as
+// they follow ordinal rules). Metadata, key order and formatting are kept.
+type ARBProcessor struct {
+	escaping bool
+}
+
+// ARBProcessorOption configures the ARB processor.
+type ARBProcessorOption func(*ARBProcessor)
+
+// WithICUEscaping reads and writes ICU apostrophe quoting (two apostrophes
+// for one, '{' for a literal brace), as Flutter does with use-escaping enabled
+// in l10n.yaml. It is enabled for files that contain two apostrophes in a row.
+func WithICUEscaping() ARBProcessorOption {
+	return func(p *ARBProcessor) {
+		p.escaping = true
+	}
+}
+
+// NewARBProcessor creates a new ARB processor.
+func NewARBProcessor(opts ...ARBProcessorOption) *ARBProcessor {
+	p := &ARBProcessor{}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// arbMessage is a translatable message of an ARB file.
+type arbMessage struct {
+	jsonString
+	seg         *codeSegment
+	description string
+}
+
+// parsedARB holds the ARB source, its messages and its "@@locale" value.
+type parsedARB struct {
+	src      string
+	messages []*arbMessage
+	locale   *jsonString
+	escaping bool // Messages use ICU apostrophe quoting
+}
+
+// Extract parses an ARB file and extracts its messages.
+func (p *ARBProcessor) Extract(content string) (interface{}, []gotlai.TextNode, error) {
+	values, err := (&JSONProcessor{}).jsonStrings(content)
+	if err != nil {
+		return nil, nil, &gotlai.ProcessorError{
+			Message:     "failed to parse ARB",
+			Cause:       err,
+			ContentType: "arb",
+		}
+	}
+
+	pa := &parsedARB{src: content, escaping: p.escaping}
+	for _, v := range values {
+		if !strings.HasPrefix(v.keyPath, "@") && strings.Contains(v.text, "''") {
+			pa.escaping = true
+		}
+	}
+
+	descriptions := make(map[string]string)
+	for i, v := range values {
+		switch {
+		case v.keyPath == "@@locale":
+			pa.locale = &values[i]
+		case strings.HasPrefix(v.keyPath, "@"):
+			if key, ok := strings.CutSuffix(v.keyPath[1:], ".description"); ok {
+				descriptions[key] = v.text
+			}
+		case !strings.Contains(v.keyPath, "."):
+			seg := buildICUSegment(v.text, pa.escaping)
+			if !hasText(seg) {
+				continue
+			}
+			v.hash = gotlai.HashText(seg.text)
+			pa.messages = append(pa.messages, &arbMessage{jsonString: v, seg: seg})
+		}
+	}
+
+	var nodes []gotlai.TextNode
+	seenHashes := make(map[string]bool)
+	for _, m := range pa.messages {
+		m.description = descriptions[m.keyPath]
+		if seenHashes[m.hash] {
+			continue
+		}
+		seenHashes[m.hash] = true
+
+		nodes = append(nodes, arbNode(m, m.seg, m.hash, len(nodes), ""))
+	}
+
+	return pa, nodes, nil
+}
+
+// arbNode creates the node of a message segment, with extra context if not empty.
+func arbNode(m *arbMessage, seg *codeSegment, hash string, index int, extra string) gotlai.TextNode {
+	parts := []string{fmt.Sprintf("Flutter ARB message %q", m.keyPath)}
+	if m.description != "" {
+		parts = append(parts, m.description)
+	}
+	if len(seg.paired) > 0 {
+		parts = append(parts, "contains ICU argument placeholders; plural and select options are paired placeholders")
+	}
+	if extra != "" {
+		parts = append(parts, extra)
+	}
+
+	return gotlai.TextNode{
+		ID:       fmt.Sprintf("node-%d", index),
+		Text:     seg.text,
+		Hash:     hash,
+		NodeType: "arb_message",
+		Context:  strings.Join(parts, " | "),
+		Metadata: map[string]string{
+			"key_path":     m.keyPath,
+			"placeholders": encodePlaceholders(seg.paired),
+		},
+	}
+}
+
+// TargetNodes returns a node per message whose plural arguments lack
+// categories the target language uses. The message has the missing options
+// added with the text of "other", so that each is translated for its counts.
+func (p *ARBProcessor) TargetNodes(parsed interface{}, nodes []gotlai.TextNode, targetLang string) []gotlai.TextNode {
+	pa, ok := parsed.(*parsedARB)
+	if !ok || targetLang == "" {
+		return nil
+	}
+
+	seenHashes := make(map[string]bool)
+	for _, n := range nodes {
+		seenHashes[n.Hash] = true
+	}
+
+	var extra []gotlai.TextNode
+	for _, m := range pa.messages {
+		seg, added := pa.targetSegment(m, targetLang)
+		if seg == nil || seenHashes[gotlai.HashText(seg.text)] {
+			continue
+		}
+		hash := gotlai.HashText(seg.text)
+		seenHashes[hash] = true
+
+		var counts []string
+		for _, category := range added {
+			counts = append(counts, fmt.Sprintf("%s: %s", category, pluralCounts(targetLang, category)))
+		}
+		context := fmt.Sprintf("plural options %s were added for the target language with the text of \"other\"; translate each for its counts (%s)",
+			strings.Join(added, ", "), strings.Join(counts, "; "))
+		extra = append(extra, arbNode(m, seg, hash, len(nodes)+len(extra), context))
+	}
+	return extra
+}
+
+// targetSegment returns the segment of a message with the plural categories
+// of targetLang added, and the added categories. It returns nil when the
+// message needs no new categories.
+func (pa *parsedARB) targetSegment(m *arbMessage, targetLang string) (*codeSegment, []string) {
+	if len(m.seg.paired) == 0 || !icuBalanced(m.text, pa.escaping) {
+		return nil, nil
+	}
+	msg, added := adaptICUPlurals(m.text, pluralCategories(targetLang), pa.escaping)
+	if len(added) == 0 {
+		return nil, nil
+	}
+	return buildICUSegment(msg, pa.escaping), added
+}
+
+// buildICUSegment flattens an ICU MessageFormat message into text with
+// placeholders. Simple arguments and "#" become self-closing placeholders;
+// plural and select arguments become paired placeholders wrapping one paired
+// placeholder per option. With escaping, ICU apostrophe quoting is decoded.
+// Messages with unbalanced braces are plain text.
+func buildICUSegment(msg string, escaping bool) *codeSegment {
+	seg := &codeSegment{}
+	if !icuBalanced(msg, escaping) {
+		seg.text = escapeSegmentText(msg)
+		return seg
+	}
+
+	var b strings.Builder
+	seg.icuMessage(&b, msg, 0, false, escaping)
+	seg.text = b.String()
+	return seg
+}
+
+// icuBalanced reports whether the braces of msg outside quoted text match.
+func icuBalanced(msg string, escaping bool) bool {
+	depth := 0
+	for i := 0; i < len(msg) && depth >= 0; i++ {
+		switch msg[i] {
+		case '\'':
+			if escaping {
+				_, end := icuQuote(msg, i, false)
+				i = end - 1
+			}
+		case '{':
+			depth++
+		case '}':
+			depth--
+		}
+	}
+	return depth == 0
+}
+
+// icuQuote decodes the apostrophe at msg[i] and returns the literal text and
+// the offset after it: two apostrophes are one, and an apostrophe before '{',
+// '}' or, in plural options, '#' quotes text up to the next single apostrophe.
+// Other apostrophes are literal.
+func icuQuote(msg string, i int, plural bool) (string, int) {
+	if i+1 >= len(msg) || !strings.ContainsRune("'{}", rune(msg[i+1])) && !(plural && msg[i+1] == '#') {
+		return "'", i + 1
+	}
+	if msg[i+1] == '\'' {
+		return "'", i + 2
+	}
+
+	var b strings.Builder
+	for j := i + 1; j < len(msg); j++ {
+		if msg[j] != '\'' {
+			b.WriteByte(msg[j])
+			continue
+		}
+		if j+1 < len(msg) && msg[j+1] == '\'' {
+			b.WriteByte('\'')
+			j++
+			continue
+		}
+		return b.String(), j + 1
+	}
+	return b.String(), len(msg)
+}
+
+// icuEscape quotes apostrophes and braces in translated text.
+func icuEscape(s string) string {
+	return strings.NewReplacer("'", "''", "{", "'{'", "}", "'}'").Replace(s)
+}
+
+// icuMessage writes the message at msg[i:] up to an unmatched '}' and returns
+// the offset where it stopped. In plural options, "#" is the formatted count.
+func (seg *codeSegment) icuMessage(b *strings.Builder, msg string, i int, plural, escaping bool) int {
+	start := i
+	for i < len(msg) {
+		switch msg[i] {
+		case '}':
+			b.WriteString(escapeSegmentText(msg[start:i]))
+			return i
+		case '\'':
+			if escaping {
+				b.WriteString(escapeSegmentText(msg[start:i]))
+				text, end := icuQuote(msg, i, plural)
+				b.WriteString(escapeSegmentText(text))
+				i, start = end, end
+				continue
+			}
+		case '#':
+			if plural {
+				b.WriteString(escapeSegmentText(msg[start:i]))
+				seg.writeAtom(b, "#")
+				i++
+				start = i
+				continue
+			}
+		case '{':
+			b.WriteString(escapeSegmentText(msg[start:i]))
+			i = seg.icuArgument(b, msg, i, escaping)
+			start = i
+			continue
+		}
+		i++
+	}
+	b.WriteString(escapeSegmentText(msg[start:]))
+	return i
+}
+
+// icuArgumentKind returns the type of the argument msg[i:end] ("plural",
+// "select", ...) and the offset after it, where the options of plural and
+// select arguments start. The kind is empty for simple arguments.
+func icuArgumentKind(msg string, i, end int) (string, int) {
+	parts := strings.SplitN(msg[i+1:end-1], ",", 3)
+	if len(parts) < 3 {
+		return "", end
+	}
+	return strings.TrimSpace(parts[1]), i + 1 + len(parts[0]) + 1 + len(parts[1]) + 1
+}
+
+// icuOption is an option of a plural or select argument.
+type icuOption struct {
+	selector string
+	start    int // Offset after the previous option, or of the first option
+	brace    int // Offset of the '{' starting the option's message
+	end      int // Offset after the option's message
+}
+
+// icuOptions parses the options in msg[j:limit], where limit is the offset of
+// the argument's closing brace. Tokens such as offset:1 are kept in the
+// following option's start.
+func icuOptions(msg string, j, limit int, escaping bool) []icuOption {
+	var options []icuOption
+	k := j
+	for {
+		for k < limit && isICUSpace(msg[k]) {
+			k++
+		}
+		selector := k
+		for k < limit && !isICUSpace(msg[k]) && msg[k] != '{' {
+			k++
+		}
+		name := msg[selector:k]
+		for k < limit && isICUSpace(msg[k]) {
+			k++
+		}
+		if k >= limit {
+			return options
+		}
+		if msg[k] != '{' {
+			continue // offset:n
+		}
+
+		end := matchICUBrace(msg, k, escaping)
+		options = append(options, icuOption{selector: name, start: j, brace: k, end: end})
+		j, k = end, end
+	}
+}
+
+// icuArgument writes the argument starting at msg[i] ('{') and returns the offset after it.
+func (seg *codeSegment) icuArgument(b *strings.Builder, msg string, i int, escaping bool) int {
+	end := matchICUBrace(msg, i, escaping)
+	kind, j := icuArgumentKind(msg, i, end)
+	if !icuPluralKinds[kind] {
+		seg.writeAtom(b, msg[i:end])
+		return end
+	}
+
+	// The argument name and type stay outside the options
+	id := seg.writeOpen(b, msg[i:j], "}")
+	plural := kind != "select"
+
+	for _, opt := range icuOptions(msg, j, end-1, escaping) {
+		option := seg.writeOpen(b, msg[opt.start:opt.brace+1], "}")
+		seg.icuMessage(b, msg, opt.brace+1, plural, escaping)
+		fmt.Fprintf(b, "</x%d>", option)
+		j = opt.end
+	}
+
+	seg.closes[id-1] = msg[j:end]
+	fmt.Fprintf(b, "</x%d>", id)
+	return end
+}
+
+// adaptICUPlurals adds the categories a language uses to the plural arguments
+// of a balanced message, with the message of the "other" option, and returns
+// the message and the added categories. Options the language does not use are
+// kept, as ICU ignores them.
+func adaptICUPlurals(msg string, categories []string, escaping bool) (string, []string) {
+	var b strings.Builder
+	var added []string
+	seen := make(map[string]bool)
+
+	var adapt func(start, end int)
+	adapt = func(start, end int) {
+		pos := start
+		for i := start; i < end; i++ {
+			switch msg[i] {
+			case '\'':
+				if escaping {
+					_, next := icuQuote(msg, i, false)
+					i = next - 1
+				}
+			case '{':
+				argEnd := matchICUBrace(msg, i, escaping)
+				kind, j := icuArgumentKind(msg, i, argEnd)
+				if !icuPluralKinds[kind] {
+					i = argEnd - 1
+					continue
+				}
+
+				b.WriteString(msg[pos:j])
+				options := icuOptions(msg, j, argEnd-1, escaping)
+				present := make(map[string]bool)
+				for _, opt := range options {
+					present[opt.selector] = true
+				}
+				for _, opt := range options {
+					if opt.selector == "other" && kind == "plural" {
+						open := msg[opt.start : opt.brace+1]
+						sep := open[:len(open)-len(strings.TrimLeft(open, " \t\r\n"))]
+						if sep == "" {
+							sep = " "
+						}
+						for _, category := range categories {
+							if present[category] || category == "other" {
+								continue
+							}
+							b.WriteString(sep + category + "{")
+							adapt(opt.brace+1, opt.end-1)
+							b.WriteString("}")
+							if !seen[category] {
+								seen[category] = true
+								added = append(added, category)
+							}
+						}
+					}
+					b.WriteString(msg[opt.start : opt.brace+1])
+					adapt(opt.brace+1, opt.end-1)
+					b.WriteString("}")
+					j = opt.end
+				}
+				b.WriteString(msg[j:argEnd])
+				i, pos = argEnd-1, argEnd
+			}
+		}
+		b.WriteString(msg[pos:end])
+	}
+	adapt(0, len(msg))
+
+	return b.String(), added
+}
+
+// matchICUBrace returns the offset after the '}' matching the '{' at msg[i].
+func matchICUBrace(msg string, i int, escaping bool) int {
+	depth := 0
+	for j := i; j < len(msg); j++ {
+		switch msg[j] {
+		case '\'':
+			if escaping {
+				_, end := icuQuote(msg, j, false)
+				j = end - 1
+			}
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return j + 1
+			}
+		}
+	}
+	return len(msg)
+}
+
+// isICUSpace reports whether c is ICU pattern whitespace.
+func isICUSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// Apply splices translated messages into the ARB source.
+func (p *ARBProcessor) Apply(parsed interface{}, nodes []gotlai.TextNode, translations map[string]string) (string, error) {
+	return p.ApplyTarget(parsed, nodes, translations, "")
+}
+
+// ApplyTarget splices translated messages into the ARB source and, with a
+// target language, sets "@@locale", adding it as the first key when missing.
+// Messages whose translation with the target language's plural categories is
+// missing keep the source categories; ICU uses "other" for the rest.
+func (p *ARBProcessor) ApplyTarget(parsed interface{}, nodes []gotlai.TextNode, translations map[string]string, targetLang string) (string, error) {
+	pa, ok := parsed.(*parsedARB)
+	if !ok {
+		return "", &gotlai.ProcessorError{
+			Message:     "invalid parsed content type",
+			ContentType: "arb",
+		}
+	}
+
+	var b strings.Builder
+	pos := 0
+
+	if targetLang != "" && pa.locale == nil {
+		if brace := strings.IndexByte(pa.src, '{'); brace >= 0 {
+			b.WriteString(pa.src[:brace+1])
+			pos = brace + 1
+			first := strings.IndexByte(pa.src[pos:], '"')
+			switch {
+			case first >= 0 && strings.Contains(pa.src[pos:pos+first], "\n"):
+				fmt.Fprintf(&b, "\n%s\"@@locale\": %q,", lineIndent(pa.src, pos+first), targetLang)
+			case first >= 0:
+				fmt.Fprintf(&b, "\"@@locale\": %q, ", targetLang)
+			default:
+				fmt.Fprintf(&b, "\"@@locale\": %q", targetLang)
+			}
+		}
+	}
+
+	type edit struct {
+		start, end int
+		text       string
+	}
+	var edits []edit
+	if targetLang != "" && pa.locale != nil {
+		edits = append(edits, edit{pa.locale.start, pa.locale.end, fmt.Sprintf("%q", targetLang)})
+	}
+	var escape func(string) string
+	if pa.escaping {
+		escape = icuEscape
+	}
+	for _, m := range pa.messages {
+		// Messages with the target language's plural categories take precedence
+		seg, hash := m.seg, m.hash
+		if targetLang != "" {
+			if target, _ := pa.targetSegment(m, targetLang); target != nil {
+				if _, ok := translations[gotlai.HashText(target.text)]; ok {
+					seg, hash = target, gotlai.HashText(target.text)
+				}
+			}
+		}
+
+		translated, ok := translations[hash]
+		if !ok {
+			continue
+		}
+		items, err := parseSegment(translated, seg.paired)
+		if err != nil {
+			continue
+		}
+		literal, err := marshalJSONString(preserveWhitespace(m.text, seg.render(items, escape)))
+		if err != nil {
+			continue
+		}
+		edits = append(edits, edit{m.start, m.end, literal})
+	}
+
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+
+	for _, e := range edits {
+		b.WriteString(pa.src[pos:e.start])
+		b.WriteString(e.text)
+		pos = e.end
+	}
+	b.WriteString(pa.src[pos:])
+
+	return b.String(), nil
+}
+
+// ValidateTranslation checks that a translation keeps every ICU placeholder.
+func (p *ARBProcessor) ValidateTranslation(node gotlai.TextNode, translated string) error {
+	if node.NodeType != "arb_message" {
+		return nil
+	}
+	_, err := parseSegment(translated, decodePlaceholders(node.Metadata["placeholders"]))
+	return err
+}
+
+// ContentType returns "arb".
+func (p *ARBProcessor) ContentType() string {
+	return "arb"
+}
+
+// Verify ARBProcessor implements ContentProcessor, TranslationValidator,
+// TargetApplier and TargetNodeProvider
+var (
+	_ ContentProcessor            = (*ARBProcessor)(nil)
+	_ gotlai.TranslationValidator = (*ARBProcessor)(nil)
+	_ gotlai.TargetApplier        = (*ARBProcessor)(nil)
+	_ gotlai.TargetNodeProvider   = (*ARBProcessor)(nil)
+)
@@ -0,0 +1,255 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+)
+
+const arbDoc = `{
+  "@@locale": "en",
+  "appTitle": "Notes",
+  "@appTitle": {
+    "description": "Title shown in the app bar"
+  },
+  "greeting": "Hello {name}!",
+  "@greeting": {
+    "description": "Greeting on the home screen",
+    "placeholders": {
+      "name": {"type": "String"}
+    }
+  },
+  "itemCount": "{count, plural, =0{No notes} one{One note} other{{count} notes}}",
+  "version": "{version}"
+}
+`
+
+func TestARBProcessor_Extract(t *testing.T) {
+	p := NewARBProcessor()
+
+	_, nodes, err := p.Extract(arbDoc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	var texts []string
+	for _, n := range nodes {
+		texts = append(texts, n.Text)
+	}
+
+	expected := []string{"Notes", "Hello <x1/>!", "<x1><x2>No notes</x2><x3>One note</x3><x4><x5/> notes</x4></x1>"}
+	if strings.Join(texts, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, texts)
+	}
+
+	if nodes[1].Context != `Flutter ARB message "greeting" | Greeting on the home screen | contains ICU argument placeholders; plural and select options are paired placeholders` {
+		t.Errorf("Unexpected context %q", nodes[1].Context)
+	}
+	if nodes[0].Metadata["key_path"] != "appTitle" {
+		t.Errorf("Unexpected metadata %v", nodes[0].Metadata)
+	}
+}
+
+func TestARBProcessor_ApplyTarget(t *testing.T) {
+	p := NewARBProcessor()
+
+	parsed, nodes, err := p.Extract(arbDoc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	translations := map[string]string{
+		nodes[0].Hash: "Notizen",
+		nodes[1].Hash: "Hallo <x1/>!",
+		nodes[2].Hash: "<x1><x2>Keine Notizen</x2><x3>Eine Notiz</x3><x4><x5/> Notizen</x4></x1>",
+	}
+
+	result, err := p.ApplyTarget(parsed, nodes, translations, "de")
+	if err != nil {
+		t.Fatalf("ApplyTarget failed: %v", err)
+	}
+
+	for _, want := range []string{
+		`"@@locale": "de",`,
+		`"appTitle": "Notizen",`,
+		`"description": "Title shown in the app bar"`,
+		`"greeting": "Hallo {name}!",`,
+		`"itemCount": "{count, plural, =0{Keine Notizen} one{Eine Notiz} other{{count} Notizen}}",`,
+		`"version": "{version}"`,
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %s in result, got:\n%s", want, result)
+		}
+	}
+}
+
+func TestARBProcessor_AddsLocale(t *testing.T) {
+	p := NewARBProcessor()
+
+	tests := map[string]string{
+		"{\n  \"hello\": \"Hello\"\n}": "{\n  \"@@locale\": \"es\",\n  \"hello\": \"Hola\"\n}",
+		`{"hello": "Hello"}`:           `{"@@locale": "es", "hello": "Hola"}`,
+	}
+	for input, expected := range tests {
+		parsed, nodes, err := p.Extract(input)
+		if err != nil {
+			t.Fatalf("Extract failed: %v", err)
+		}
+		result, err := p.ApplyTarget(parsed, nodes, map[string]string{nodes[0].Hash: "Hola"}, "es")
+		if err != nil {
+			t.Fatalf("ApplyTarget failed: %v", err)
+		}
+		if result != expected {
+			t.Errorf("Expected %q, got %q", expected, result)
+		}
+	}
+}
+
+func TestBuildICUSegment(t *testing.T) {
+	tests := map[string]string{
+		"{gender, select, male{He} other{They}} replied":         "<x1><x2>He</x2><x3>They</x3></x1> replied",
+		"{n, plural, offset:1 one{You} other{You and # others}}": "<x1><x2>You</x2><x3>You and <x4/> others</x3></x1>",
+		"Sent {date, date, short}":                               "Sent <x1/>",
+		"Unbalanced {brace":                                      "Unbalanced {brace",
+	}
+	for input, expected := range tests {
+		seg := buildICUSegment(input, false)
+		if seg.text != expected {
+			t.Errorf("buildICUSegment(%q) = %q, want %q", input, seg.text, expected)
+		}
+
+		items, err := parseSegment(seg.text, seg.paired)
+		if err != nil {
+			t.Fatalf("parseSegment(%q) failed: %v", seg.text, err)
+		}
+		if got := seg.render(items, nil); got != input {
+			t.Errorf("render = %q, want %q", got, input)
+		}
+	}
+}
+
+func TestBuildICUSegment_Escaping(t *testing.T) {
+	input := "It''s {n, plural, one{'{'#'}' item} other{# items}} for '{name}'"
+	seg := buildICUSegment(input, true)
+
+	expected := "It's <x1><x2>{<x3/>} item</x2><x4><x5/> items</x4></x1> for {name}"
+	if seg.text != expected {
+		t.Errorf("buildICUSegment = %q, want %q", seg.text, expected)
+	}
+
+	items, err := parseSegment(seg.text, seg.paired)
+	if err != nil {
+		t.Fatalf("parseSegment failed: %v", err)
+	}
+	if got := seg.render(items, icuEscape); got != "It''s {n, plural, one{'{'#'}' item} other{# items}} for '{'name'}'" {
+		t.Errorf("Unexpected render %q", got)
+	}
+
+	// Without escaping, apostrophes are literal and braces are arguments
+	if seg := buildICUSegment("Don't delete '{name}'", false); seg.text != "Don't delete '<x1/>'" {
+		t.Errorf("Unexpected text %q", seg.text)
+	}
+}
+
+func TestARBProcessor_TargetPlurals(t *testing.T) {
+	p := NewARBProcessor()
+
+	parsed, nodes, err := p.Extract(arbDoc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if extra := p.TargetNodes(parsed, nodes, "de"); extra != nil {
+		t.Errorf("Expected no extra nodes for German, got %+v", extra)
+	}
+
+	extra := p.TargetNodes(parsed, nodes, "ru")
+	if len(extra) != 1 {
+		t.Fatalf("Expected one extra node, got %+v", extra)
+	}
+	if extra[0].Text != "<x1><x2>No notes</x2><x3>One note</x3><x4><x5/> notes</x4><x6><x7/> notes</x6><x8><x9/> notes</x8></x1>" {
+		t.Errorf("Unexpected text %q", extra[0].Text)
+	}
+	if extra[0].ID != "node-3" || !strings.Contains(extra[0].Context, "plural options few, many were added") ||
+		!strings.Contains(extra[0].Context, "few: counts such as 2, 3, 4, 22, 23, 24") {
+		t.Errorf("Unexpected node %+v", extra[0])
+	}
+
+	translations := map[string]string{
+		nodes[2].Hash: "<x1><x2>Нет заметок</x2><x3>Одна заметка</x3><x4><x5/> заметки</x4></x1>",
+		extra[0].Hash: "<x1><x2>Нет заметок</x2><x3>Одна заметка</x3><x4><x5/> заметки</x4><x6><x7/> заметок</x6><x8><x9/> заметки</x8></x1>",
+	}
+
+	result, err := p.ApplyTarget(parsed, append(nodes, extra...), translations, "ru")
+	if err != nil {
+		t.Fatalf("ApplyTarget failed: %v", err)
+	}
+	want := `"itemCount": "{count, plural, =0{Нет заметок} one{Одна заметка} few{{count} заметки} many{{count} заметок} other{{count} заметки}}",`
+	if !strings.Contains(result, want) {
+		t.Errorf("Expected %s in result, got:\n%s", want, result)
+	}
+
+	// Without the adapted translation the source categories are kept
+	delete(translations, extra[0].Hash)
+	result, err = p.ApplyTarget(parsed, nodes, translations, "ru")
+	if err != nil {
+		t.Fatalf("ApplyTarget failed: %v", err)
+	}
+	want = `"itemCount": "{count, plural, =0{Нет заметок} one{Одна заметка} other{{count} заметки}}",`
+	if !strings.Contains(result, want) {
+		t.Errorf("Expected %s in result, got:\n%s", want, result)
+	}
+}
+
+func TestARBProcessor_ICUEscaping(t *testing.T) {
+	src := `{"title": "Don''t panic", "hint": "Use '{name}' in {place}"}`
+
+	for _, p := range []*ARBProcessor{NewARBProcessor(), NewARBProcessor(WithICUEscaping())} {
+		parsed, nodes, err := p.Extract(src)
+		if err != nil {
+			t.Fatalf("Extract failed: %v", err)
+		}
+		if len(nodes) != 2 || nodes[0].Text != "Don't panic" || nodes[1].Text != "Use {name} in <x1/>" {
+			t.Fatalf("Unexpected nodes %+v", nodes)
+		}
+
+		translations := map[string]string{
+			nodes[0].Hash: "N'ayez pas peur",
+			nodes[1].Hash: "Utilisez {name} dans <x1/>",
+		}
+		result, err := p.Apply(parsed, nodes, translations)
+		if err != nil {
+			t.Fatalf("Apply failed: %v", err)
+		}
+		expected := `{"title": "N''ayez pas peur", "hint": "Utilisez '{'name'}' dans {place}"}`
+		if result != expected {
+			t.Errorf("Expected %q, got %q", expected, result)
+		}
+	}
+}
+
+func TestARBProcessor_ValidateTranslation(t *testing.T) {
+	p := NewARBProcessor()
+
+	_, nodes, err := p.Extract(arbDoc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if err := p.ValidateTranslation(nodes[1], "Hallo!"); err == nil {
+		t.Error("Expected missing placeholder to be rejected")
+	}
+	if err := p.ValidateTranslation(nodes[2], "<x1><x4><x5/> Notizen</x4><x2>Keine</x2><x3>Eine</x3></x1>"); err != nil {
+		t.Errorf("Expected reordered options to be valid, got %v", err)
+	}
+}
+
+func TestARBProcessor_InvalidJSON(t *testing.T) {
+	if _, _, err := NewARBProcessor().Extract(`{"a": `); err == nil {
+		t.Error("Expected error for malformed ARB")
+	}
+}
+
+func TestARBProcessor_ContentType(t *testing.T) {
+	if ct := NewARBProcessor().ContentType(); ct != "arb" {
+		t.Errorf("Expected 'arb', got %q", ct)
+	}
+}
@@ -0,0 +1,162 @@
+package processor
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ZaguanLabs/gotlai"
+)
+
+// localeKeyPattern matches keys that name a locale, such as "en", "pt-BR" or "zh_Hant".
+var localeKeyPattern = regexp.MustCompile(`^[a-z]{2,3}(?:[-_][A-Za-z0-9]{2,8})*$`)
+
+// configValue is a translatable string in a YAML or TOML file.
+type configValue struct {
+	keyPath    string // Dotted path, without the root locale key
+	text       string // Decoded value
+	hash       string
+	start, end int    // Byte range of the encoded value
+	quote      string // Encoding: "", "'" or `"`; "|" or ">" for YAML block scalars; `"""` or "'''" for TOML multi-line strings
+	indent     string // Indentation of YAML block scalar lines
+}
+
+// parsedConfig holds a YAML or TOML source and its translatable strings.
+type parsedConfig struct {
+	src        string
+	toml       bool
+	values     []*configValue
+	localeRoot string   // Root locale key, when the file is a Rails-style locale bundle
+	localeKeys [][2]int // Byte ranges of the root locale key, without quotes
+}
+
+// configNodes builds text nodes for the values that pass the key filter.
+func configNodes(pc *parsedConfig, keys KeyFilter, format string) []gotlai.TextNode {
+	var nodes []gotlai.TextNode
+	seenHashes := make(map[string]bool)
+	kept := pc.values[:0]
+	for _, v := range pc.values {
+		if !keys.Match(v.keyPath) {
+			continue
+		}
+		kept = append(kept, v)
+		if seenHashes[v.hash] {
+			continue
+		}
+		seenHashes[v.hash] = true
+
+		node := gotlai.TextNode{
+			ID:       fmt.Sprintf("node-%d", len(nodes)),
+			Text:     strings.TrimSpace(v.text),
+			Hash:     v.hash,
+			NodeType: "config_string",
+			Context:  catalogContext(v.keyPath, format),
+			Metadata: map[string]string{
+				"key_path": v.keyPath,
+			},
+		}
+		if pc.localeRoot != "" {
+			node.Metadata["locale_root"] = pc.localeRoot
+		}
+		nodes = append(nodes, node)
+	}
+	pc.values = kept
+
+	return nodes
+}
+
+// isLocaleKey reports whether a root key names a locale the library knows,
+// so that a data file with a single "faq:" key is not taken for a bundle.
+func isLocaleKey(key string) bool {
+	if !localeKeyPattern.MatchString(key) {
+		return false
+	}
+	base, _, _ := strings.Cut(gotlai.NormalizeLocale(key), "_")
+	_, ok := gotlai.ShortCodeToLocale[base]
+	return ok
+}
+
+// stripLocaleRoot removes the root locale key from the key paths of a locale bundle.
+func (pc *parsedConfig) stripLocaleRoot() {
+	for _, v := range pc.values {
+		if v.keyPath == pc.localeRoot {
+			v.keyPath = ""
+		} else {
+			v.keyPath = strings.TrimPrefix(v.keyPath, pc.localeRoot+".")
+		}
+	}
+}
+
+// localeKey formats the target language in the style of the source root key:
+// with an underscore when the source uses one, with a hyphen otherwise.
+func localeKey(root, targetLang string) string {
+	lang := gotlai.ToHTMLLang(targetLang)
+	if strings.Contains(root, "_") {
+		lang = strings.ReplaceAll(lang, "-", "_")
+	}
+	return lang
+}
+
+// applyConfig splices translated values into the source. With a target
+// language, the root locale key is renamed.
+func applyConfig(parsed interface{}, translations map[string]string, targetLang, contentType string) (string, error) {
+	pc, ok := parsed.(*parsedConfig)
+	if !ok {
+		return "", &gotlai.ProcessorError{
+			Message:     "invalid parsed content type",
+			ContentType: contentType,
+		}
+	}
+
+	type edit struct {
+		start, end int
+		text       string
+	}
+	var edits []edit
+
+	if targetLang != "" && pc.localeRoot != "" {
+		for _, span := range pc.localeKeys {
+			edits = append(edits, edit{span[0], span[1], localeKey(pc.localeRoot, targetLang)})
+		}
+	}
+
+	for _, v := range pc.values {
+		translated, ok := translations[v.hash]
+		if !ok {
+			continue
+		}
+		translated = preserveWhitespace(v.text, translated)
+
+		var text string
+		switch v.quote {
+		case "|", ">":
+			text = yamlBlockScalar(translated, v.quote, v.indent)
+		case `"""`, "'''":
+			text = tomlMultiline(translated, v.quote, strings.HasPrefix(pc.src[v.start:], v.quote+"\n"))
+		case "":
+			// A plain translation reading as a boolean or null is quoted
+			quote := ""
+			if yamlNonStringPattern.MatchString(translated) {
+				quote = `"`
+			}
+			text = quoteFrontMatter(translated, quote, pc.toml)
+		default:
+			text = quoteFrontMatter(translated, v.quote, pc.toml)
+		}
+		edits = append(edits, edit{v.start, v.end, text})
+	}
+
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+
+	var b strings.Builder
+	pos := 0
+	for _, e := range edits {
+		b.WriteString(pc.src[pos:e.start])
+		b.WriteString(e.text)
+		pos = e.end
+	}
+	b.WriteString(pc.src[pos:])
+
+	return b.String(), nil
+}
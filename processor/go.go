@@ -141,6 +141,7 @@ func (p *GoProcessor) Extract(content string) (interface{}, []gotlai.TextNode, e
 }
 
 // Apply applies translations back to the Go source.
+// The parsed AST is restored afterwards, so it can be applied again.
 func (p *GoProcessor) Apply(parsed interface{}, nodes []gotlai.TextNode, translations map[string]string) (string, error) {
 	pg, ok := parsed.(*parsedGo)
 	if !ok {
@@ -163,11 +164,24 @@ func (p *GoProcessor) Apply(parsed interface{}, nodes []gotlai.TextNode, transla
 		}
 	}
 
+	// Remember original text so the AST can be reused for another language
+	commentOriginals := make(map[*ast.Comment]string)
+	litOriginals := make(map[*ast.BasicLit]string)
+	defer func() {
+		for c, text := range commentOriginals {
+			c.Text = text
+		}
+		for lit, value := range litOriginals {
+			lit.Value = value
+		}
+	}()
+
 	// Apply translations to comments
 	if p.translateComments {
 		for _, cg := range pg.file.Comments {
 			for _, c := range cg.List {
 				if translated, ok := posToTranslation[c.Pos()]; ok {
+					commentOriginals[c] = c.Text
 					if strings.HasPrefix(c.Text, "//") {
 						c.Text = "// " + translated
 					} else if strings.HasPrefix(c.Text, "/*") {
@@ -187,6 +201,7 @@ func (p *GoProcessor) Apply(parsed interface{}, nodes []gotlai.TextNode, transla
 			}
 
 			if translated, ok := posToTranslation[lit.Pos()]; ok {
+				litOriginals[lit] = lit.Value
 				quote := string(lit.Value[0])
 				if quote == "`" {
 					lit.Value = "`" + translated + "`"
@@ -132,6 +132,44 @@ func main() {
 	}
 }
 
+func TestGoProcessor_Apply_Reusable(t *testing.T) {
+	p := NewGoProcessor()
+
+	src := `package main
+
+// Say hello
+func main() {
+	msg := "Hello"
+}
+`
+	parsed, nodes, err := p.Extract(src)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	spanish := make(map[string]string)
+	french := make(map[string]string)
+	for _, n := range nodes {
+		spanish[n.Hash] = "es " + n.Text
+		french[n.Hash] = "fr " + n.Text
+	}
+
+	if _, err := p.Apply(parsed, nodes, spanish); err != nil {
+		t.Fatalf("First Apply failed: %v", err)
+	}
+	result, err := p.Apply(parsed, nodes, french)
+	if err != nil {
+		t.Fatalf("Second Apply failed: %v", err)
+	}
+
+	if !strings.Contains(result, `"fr Hello"`) || !strings.Contains(result, "// fr Say hello") {
+		t.Errorf("Expected French translations, got:\n%s", result)
+	}
+	if strings.Contains(result, "es ") {
+		t.Errorf("Spanish translations should not leak into second result, got:\n%s", result)
+	}
+}
+
 func TestGoProcessor_Apply_Comments(t *testing.T) {
 	p := NewGoProcessor()
 
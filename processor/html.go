@@ -6,76 +6,188 @@ import (
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/ZaguanLabs/gotlai"
+	"github.com/andybalholm/cascadia"
 	"golang.org/x/net/html"
 )
 
 // HTMLProcessor extracts and applies translations to HTML content.
 type HTMLProcessor struct {
-	ignoredTags map[string]bool
+	ignoredTags     map[string]bool
+	contextKeys     bool
+	streamWindow    int
+	attrRules       []AttributeRule
+	inlineSegments  bool
+	skipSelectors   []cascadia.Selector
+	selectorErr     error
+	fragmentContext string
+	minimalDiff     bool
+	seoMetadata     bool
+	jsonLDKeys      map[string]bool
+}
+
+// HTMLProcessorOption configures the HTML processor.
+type HTMLProcessorOption func(*HTMLProcessor)
+
+// WithContextKeys makes identical text in different contexts translate independently.
+// Text is deduplicated and cached by its content plus a normalized context (the parent
+// tag and the nearest ARIA role), so "Close" on a dialog button and "Close" in an
+// address get separate translations.
+func WithContextKeys() HTMLProcessorOption {
+	return func(p *HTMLProcessor) {
+		p.contextKeys = true
+	}
 }
 
 // NewHTMLProcessor creates a new HTML processor with default ignored tags.
-func NewHTMLProcessor() *HTMLProcessor {
-	return &HTMLProcessor{
+func NewHTMLProcessor(opts ...HTMLProcessorOption) *HTMLProcessor {
+	p := &HTMLProcessor{
 		ignoredTags: gotlai.IgnoredTags,
 	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
 }
 
-// NewHTMLProcessorWithIgnoredTags creates a new HTML processor with custom ignored tags.
-func NewHTMLProcessorWithIgnoredTags(tags []string) *HTMLProcessor {
-	ignored := make(map[string]bool)
-	for _, tag := range tags {
-		ignored[strings.ToLower(tag)] = true
-	}
-	return &HTMLProcessor{
-		ignoredTags: ignored,
+// WithIgnoredTags replaces the default ignored tags, whose text is never
+// translated, with tags.
+func WithIgnoredTags(tags ...string) HTMLProcessorOption {
+	return func(p *HTMLProcessor) {
+		ignored := make(map[string]bool)
+		for _, tag := range tags {
+			ignored[strings.ToLower(tag)] = true
+		}
+		p.ignoredTags = ignored
 	}
 }
 
+// NewHTMLProcessorWithIgnoredTags creates a new HTML processor with custom
+// ignored tags. It is NewHTMLProcessor with WithIgnoredTags(tags...) applied
+// before opts.
+func NewHTMLProcessorWithIgnoredTags(tags []string, opts ...HTMLProcessorOption) *HTMLProcessor {
+	return NewHTMLProcessor(append([]HTMLProcessorOption{WithIgnoredTags(tags...)}, opts...)...)
+}
+
 // parsedHTML holds the parsed document and node mappings.
 type parsedHTML struct {
 	doc     *goquery.Document
 	nodeMap map[string]*html.Node // Maps node ID to HTML node for mutation
+	root    *html.Node            // Fragment context element, nil for full documents
+	source  *sourceMap            // Set when translations are spliced into the source
 }
 
 // Extract parses HTML and extracts translatable text nodes.
 func (p *HTMLProcessor) Extract(content string) (interface{}, []gotlai.TextNode, error) {
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
-	if err != nil {
+	if p.selectorErr != nil {
 		return nil, nil, &gotlai.ProcessorError{
-			Message:     "failed to parse HTML",
-			Cause:       err,
+			Message:     "invalid processor configuration",
+			Cause:       p.selectorErr,
 			ContentType: "html",
 		}
 	}
 
+	var doc *goquery.Document
+	var root *html.Node
+	var source *sourceMap
+	if p.fragmentContext != "" {
+		var err error
+		root, err = p.parseFragment(content)
+		if err != nil {
+			return nil, nil, &gotlai.ProcessorError{
+				Message:     "failed to parse HTML fragment",
+				Cause:       err,
+				ContentType: "html",
+			}
+		}
+		doc = goquery.NewDocumentFromNode(root)
+		source = p.newSourceMap(content, root, p.fragmentContext)
+	} else {
+		var err error
+		doc, err = goquery.NewDocumentFromReader(strings.NewReader(content))
+		if err != nil {
+			return nil, nil, &gotlai.ProcessorError{
+				Message:     "failed to parse HTML",
+				Cause:       err,
+				ContentType: "html",
+			}
+		}
+		if p.minimalDiff {
+			source = p.newSourceMap(content, doc.Nodes[0], "")
+		}
+	}
+
 	var nodes []gotlai.TextNode
 	nodeMap := make(map[string]*html.Node)
 	seenHashes := make(map[string]bool)
 
+	// Extract translatable attributes of an element
+	extractAttrs := func(el *html.Node) {
+		for _, i := range p.translatableAttrs(el.Data, el.Attr) {
+			attr := el.Attr[i]
+			trimmed := strings.TrimSpace(attr.Val)
+			hash := p.hashAttr(el.Data, attr.Key, trimmed)
+			if !seenHashes[hash] {
+				seenHashes[hash] = true
+				nodes = append(nodes, p.attrTextNode(fmt.Sprintf("node-%d", len(nodes)), el.Data, el.Attr, i, trimmed, hash))
+			}
+		}
+	}
+
 	// Walk the DOM tree
-	var walk func(*html.Node, *goquery.Selection)
-	walk = func(n *html.Node, parentSel *goquery.Selection) {
+	var walk func(*html.Node, *goquery.Selection, bool)
+	walk = func(n *html.Node, parentSel *goquery.Selection, translate bool) {
 		if n.Type == html.ElementNode {
-			// Skip ignored tags
-			if p.ignoredTags[strings.ToLower(n.Data)] {
+			// Extract JSON-LD values from structured data scripts
+			if p.jsonLDScript(n) {
+				if p.translates(n, translate) && n.FirstChild != nil && n.FirstChild.Type == html.TextNode {
+					values, err := p.jsonLDValues(n.FirstChild.Data)
+					if err == nil {
+						for _, v := range values {
+							node := p.jsonLDTextNode(fmt.Sprintf("node-%d", len(nodes)), v)
+							if !seenHashes[node.Hash] {
+								seenHashes[node.Hash] = true
+								nodes = append(nodes, node)
+							}
+						}
+					}
+				}
 				return
 			}
 
-			// Skip elements with data-no-translate attribute
-			for _, attr := range n.Attr {
-				if attr.Key == "data-no-translate" {
-					return
+			// Skip ignored tags and elements with data-no-translate attribute
+			if p.skipped(n) {
+				return
+			}
+
+			// Honor translate="no", the notranslate class and skip selectors
+			translate = p.translates(n, translate)
+
+			if translate {
+				extractAttrs(n)
+			}
+
+			// Extract blocks with inline markup as a single segment
+			if translate && p.segmentable(n) {
+				seg := p.buildSegment(n)
+				node := p.segmentTextNode(fmt.Sprintf("node-%d", len(nodes)), n, seg)
+				if !seenHashes[node.Hash] {
+					seenHashes[node.Hash] = true
+					nodes = append(nodes, node)
 				}
+				p.forEachInline(n, extractAttrs)
+				return
 			}
 		}
 
-		if n.Type == html.TextNode {
+		// Inline SVG only renders text inside text content elements
+		inSVG, svgTranslatable := svgText(n)
+
+		if n.Type == html.TextNode && translate && (!inSVG || svgTranslatable) {
 			text := n.Data
 			trimmed := strings.TrimSpace(text)
 
 			if trimmed != "" {
-				hash := gotlai.HashText(trimmed)
+				hash := p.hashNode(n, trimmed)
 
 				// Deduplicate by hash
 				if !seenHashes[hash] {
@@ -96,6 +208,15 @@ func (p *HTMLProcessor) Extract(content string) (interface{}, []gotlai.TextNode,
 					if n.Parent != nil {
 						node.Metadata["parent_tag"] = n.Parent.Data
 					}
+					if p.contextKeys {
+						node.Metadata["context_key"] = contextKey(n)
+					}
+					if p.isSEOTitle(n) {
+						node = seoTextNode(node, seoTitle)
+					}
+					if inSVG {
+						node = svgTextNode(node, n.Parent.Data)
+					}
 
 					nodes = append(nodes, node)
 				}
@@ -112,21 +233,22 @@ func (p *HTMLProcessor) Extract(content string) (interface{}, []gotlai.TextNode,
 			if c.Type == html.ElementNode {
 				childSel = parentSel.Find(c.Data).First()
 			}
-			walk(c, childSel)
+			walk(c, childSel, translate)
 		}
 	}
 
 	// Start walking from the root
 	doc.Each(func(i int, s *goquery.Selection) {
 		for _, n := range s.Nodes {
-			walk(n, s)
+			walk(n, s, true)
 		}
 	})
 
-	return &parsedHTML{doc: doc, nodeMap: nodeMap}, nodes, nil
+	return &parsedHTML{doc: doc, nodeMap: nodeMap, root: root, source: source}, nodes, nil
 }
 
 // Apply applies translations back to the HTML document.
+// The parsed document is restored afterwards, so it can be applied again.
 func (p *HTMLProcessor) Apply(parsed interface{}, nodes []gotlai.TextNode, translations map[string]string) (string, error) {
 	ph, ok := parsed.(*parsedHTML)
 	if !ok {
@@ -139,31 +261,106 @@ func (p *HTMLProcessor) Apply(parsed interface{}, nodes []gotlai.TextNode, trans
 	// Build a map of hash to translation
 	hashToTranslation := translations
 
+	// Remember original text so the document can be reused for another language
+	originals := make(map[*html.Node]string)
+	attrOriginals := make(map[*html.Node][]html.Attribute)
+	segmentOriginals := make(map[*html.Node][]*html.Node)
+	defer func() {
+		for n, data := range originals {
+			n.Data = data
+		}
+		for n, attrs := range attrOriginals {
+			n.Attr = attrs
+		}
+		for block, children := range segmentOriginals {
+			for c := block.FirstChild; c != nil; c = block.FirstChild {
+				block.RemoveChild(c)
+			}
+			for _, c := range children {
+				block.AppendChild(c)
+			}
+		}
+	}()
+
+	// Apply translated attributes to a copy so the original can be restored
+	applyAttrs := func(el *html.Node) {
+		indexes := p.translatableAttrs(el.Data, el.Attr)
+		if len(indexes) == 0 {
+			return
+		}
+		attrs := append([]html.Attribute(nil), el.Attr...)
+		for _, i := range indexes {
+			hash := p.hashAttr(el.Data, attrs[i].Key, strings.TrimSpace(attrs[i].Val))
+			if translated, ok := hashToTranslation[hash]; ok {
+				attrs[i].Val = preserveWhitespace(attrs[i].Val, translated)
+			}
+		}
+		if _, saved := attrOriginals[el]; !saved {
+			attrOriginals[el] = el.Attr
+		}
+		el.Attr = attrs
+	}
+
 	// Walk the DOM and apply translations
-	var walk func(*html.Node)
-	walk = func(n *html.Node) {
+	var walk func(*html.Node, bool)
+	walk = func(n *html.Node, translate bool) {
 		if n.Type == html.ElementNode {
-			// Skip ignored tags
-			if p.ignoredTags[strings.ToLower(n.Data)] {
+			// Rewrite translated JSON-LD values in structured data scripts
+			if p.jsonLDScript(n) {
+				if c := n.FirstChild; p.translates(n, translate) && c != nil && c.Type == html.TextNode {
+					if values, err := p.jsonLDValues(c.Data); err == nil {
+						if rewritten := p.rewriteJSONLD(c.Data, values, hashToTranslation); rewritten != c.Data {
+							originals[c] = c.Data
+							c.Data = rewritten
+						}
+					}
+				}
+				return
+			}
+
+			// Skip ignored tags and elements with data-no-translate attribute
+			if p.skipped(n) {
 				return
 			}
 
-			// Skip elements with data-no-translate attribute
-			for _, attr := range n.Attr {
-				if attr.Key == "data-no-translate" {
-					return
+			// Honor translate="no", the notranslate class and skip selectors
+			translate = p.translates(n, translate)
+
+			if translate {
+				applyAttrs(n)
+			}
+
+			// Rebuild blocks with inline markup in the translated order
+			if translate && p.segmentable(n) {
+				seg := p.buildSegment(n)
+				if translated, ok := hashToTranslation[p.hashSegment(n, seg.text)]; ok {
+					if items, err := parseSegment(translated, seg.paired); err == nil {
+						var children []*html.Node
+						for c := n.FirstChild; c != nil; c = c.NextSibling {
+							children = append(children, c)
+						}
+						segmentOriginals[n] = children
+						p.rebuildSegment(n, seg, items)
+					}
 				}
+				p.forEachInline(n, applyAttrs)
+				return
 			}
 		}
 
-		if n.Type == html.TextNode {
+		if inSVG, ok := svgText(n); inSVG && !ok {
+			return
+		}
+
+		if n.Type == html.TextNode && translate {
 			text := n.Data
 			trimmed := strings.TrimSpace(text)
 
 			if trimmed != "" {
-				hash := gotlai.HashText(trimmed)
+				hash := p.hashNode(n, trimmed)
 				if translated, ok := hashToTranslation[hash]; ok {
 					// Preserve original whitespace
+					originals[n] = text
 					n.Data = preserveWhitespace(text, translated)
 				}
 			}
@@ -171,16 +368,24 @@ func (p *HTMLProcessor) Apply(parsed interface{}, nodes []gotlai.TextNode, trans
 
 		// Recurse into children
 		for c := n.FirstChild; c != nil; c = c.NextSibling {
-			walk(c)
+			walk(c, translate)
 		}
 	}
 
 	ph.doc.Each(func(i int, s *goquery.Selection) {
 		for _, n := range s.Nodes {
-			walk(n)
+			walk(n, true)
 		}
 	})
 
+	// Splice into the original source when possible, leaving other bytes untouched
+	if ph.source != nil {
+		if result, ok := ph.source.splice(ph.root, originals, attrOriginals, segmentOriginals); ok {
+			return result, nil
+		}
+	}
+
+	// Fragments render the context element's children only
 	html, err := ph.doc.Html()
 	if err != nil {
 		return "", &gotlai.ProcessorError{
@@ -198,31 +403,47 @@ func (p *HTMLProcessor) ContentType() string {
 	return "html"
 }
 
+// hashNode computes the dedupe and cache hash for a text node.
+func (p *HTMLProcessor) hashNode(n *html.Node, trimmed string) string {
+	if p.contextKeys {
+		return gotlai.HashTextWithContext(trimmed, contextKey(n))
+	}
+	return gotlai.HashText(trimmed)
+}
+
+// contextKey builds a normalized context for a text node: the parent tag,
+// followed by the nearest ancestor's ARIA role if any (e.g. "button|dialog").
+func contextKey(n *html.Node) string {
+	if n.Parent == nil {
+		return ""
+	}
+	return elementContextKey(n.Parent)
+}
+
+// elementContextKey builds a normalized context for content directly inside el.
+func elementContextKey(el *html.Node) string {
+	key := strings.ToLower(el.Data)
+	for a := el; a != nil; a = a.Parent {
+		if a.Type != html.ElementNode {
+			continue
+		}
+		for _, attr := range a.Attr {
+			if attr.Key == "role" && attr.Val != "" {
+				return key + "|" + strings.ToLower(strings.TrimSpace(attr.Val))
+			}
+		}
+	}
+
+	return key
+}
+
 // buildContext creates a disambiguation context string for a text node.
 func (p *HTMLProcessor) buildContext(n *html.Node, parentSel *goquery.Selection) string {
 	var parts []string
 
 	if n.Parent != nil {
 		parent := n.Parent
-		tag := parent.Data
-
-		// Get class or id if available
-		var classAttr, idAttr string
-		for _, attr := range parent.Attr {
-			if attr.Key == "class" {
-				classAttr = attr.Val
-			} else if attr.Key == "id" {
-				idAttr = attr.Val
-			}
-		}
-
-		if classAttr != "" {
-			parts = append(parts, fmt.Sprintf("in <%s class=\"%s\">", tag, classAttr))
-		} else if idAttr != "" {
-			parts = append(parts, fmt.Sprintf("in <%s id=\"%s\">", tag, idAttr))
-		} else {
-			parts = append(parts, fmt.Sprintf("in <%s>", tag))
-		}
+		parts = append(parts, "in "+describeElement(parent))
 
 		// Get sibling text (up to 3 items)
 		var siblings []string
@@ -244,28 +465,53 @@ func (p *HTMLProcessor) buildContext(n *html.Node, parentSel *goquery.Selection)
 			parts = append(parts, fmt.Sprintf("with: %s", strings.Join(siblings, ", ")))
 		}
 
-		// Get ancestor path (up to 3 levels)
-		var ancestors []string
-		ancestor := parent.Parent
-		for i := 0; i < 3 && ancestor != nil; i++ {
-			if ancestor.Type == html.ElementNode {
-				name := ancestor.Data
-				if name != "html" && name != "body" {
-					ancestors = append(ancestors, name)
-				}
-			}
-			ancestor = ancestor.Parent
+		if ancestors := ancestorPath(parent); ancestors != "" {
+			parts = append(parts, "inside: "+ancestors)
+		}
+	}
+
+	return strings.Join(parts, " | ")
+}
+
+// describeElement renders an element's start tag with its class or id, e.g. <li class="menu">.
+func describeElement(el *html.Node) string {
+	var classAttr, idAttr string
+	for _, attr := range el.Attr {
+		if attr.Key == "class" {
+			classAttr = attr.Val
+		} else if attr.Key == "id" {
+			idAttr = attr.Val
 		}
-		if len(ancestors) > 0 {
-			// Reverse to show outer to inner
-			for i, j := 0, len(ancestors)-1; i < j; i, j = i+1, j-1 {
-				ancestors[i], ancestors[j] = ancestors[j], ancestors[i]
+	}
+
+	if classAttr != "" {
+		return fmt.Sprintf("<%s class=\"%s\">", el.Data, classAttr)
+	} else if idAttr != "" {
+		return fmt.Sprintf("<%s id=\"%s\">", el.Data, idAttr)
+	}
+	return fmt.Sprintf("<%s>", el.Data)
+}
+
+// ancestorPath returns up to 3 ancestors of el, outer to inner, excluding html and body.
+func ancestorPath(el *html.Node) string {
+	var ancestors []string
+	ancestor := el.Parent
+	for i := 0; i < 3 && ancestor != nil; i++ {
+		if ancestor.Type == html.ElementNode {
+			name := ancestor.Data
+			if name != "html" && name != "body" {
+				ancestors = append(ancestors, name)
 			}
-			parts = append(parts, fmt.Sprintf("inside: %s", strings.Join(ancestors, " > ")))
 		}
+		ancestor = ancestor.Parent
 	}
 
-	return strings.Join(parts, " | ")
+	// Reverse to show outer to inner
+	for i, j := 0, len(ancestors)-1; i < j; i, j = i+1, j-1 {
+		ancestors[i], ancestors[j] = ancestors[j], ancestors[i]
+	}
+
+	return strings.Join(ancestors, " > ")
 }
 
 // preserveWhitespace preserves the original leading/trailing whitespace.
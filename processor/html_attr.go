@@ -0,0 +1,172 @@
+package processor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ZaguanLabs/gotlai"
+	"golang.org/x/net/html"
+)
+
+// AttributeRule selects a user-visible attribute to translate.
+type AttributeRule struct {
+	Tag   string            // Element name, or "*" for any element
+	Attr  string            // Attribute to translate
+	Match map[string]string // Other attributes that must have these values (case-insensitive)
+}
+
+// DefaultAttributeRules lists the attributes translated by WithAttributes when no rules are given.
+var DefaultAttributeRules = []AttributeRule{
+	{Tag: "img", Attr: "alt"},
+	{Tag: "area", Attr: "alt"},
+	{Tag: "input", Attr: "alt", Match: map[string]string{"type": "image"}},
+	{Tag: "*", Attr: "title"},
+	{Tag: "*", Attr: "aria-label"},
+	{Tag: "input", Attr: "placeholder"},
+	{Tag: "textarea", Attr: "placeholder"},
+	{Tag: "input", Attr: "value", Match: map[string]string{"type": "submit"}},
+	{Tag: "input", Attr: "value", Match: map[string]string{"type": "button"}},
+	{Tag: "input", Attr: "value", Match: map[string]string{"type": "reset"}},
+	{Tag: "meta", Attr: "content", Match: map[string]string{"name": "description"}},
+}
+
+// WithAttributes enables translation of attribute values selected by rules.
+// With no rules, DefaultAttributeRules are used.
+func WithAttributes(rules ...AttributeRule) HTMLProcessorOption {
+	return func(p *HTMLProcessor) {
+		if len(rules) == 0 {
+			rules = DefaultAttributeRules
+		}
+		p.attrRules = rules
+	}
+}
+
+// ParseAttributeRule parses a rule written as tag[attr=value]...@attr,
+// e.g. "img@alt", "*@title" or "input[type=submit]@value".
+func ParseAttributeRule(s string) (AttributeRule, error) {
+	at := strings.LastIndex(s, "@")
+	if at <= 0 || at == len(s)-1 {
+		return AttributeRule{}, fmt.Errorf("invalid attribute rule %q: expected tag@attr", s)
+	}
+
+	rule := AttributeRule{Attr: strings.ToLower(s[at+1:])}
+	selector := s[:at]
+
+	if i := strings.Index(selector, "["); i >= 0 {
+		conditions := selector[i:]
+		selector = selector[:i]
+		rule.Match = make(map[string]string)
+
+		for conditions != "" {
+			end := strings.Index(conditions, "]")
+			if conditions[0] != '[' || end < 0 {
+				return AttributeRule{}, fmt.Errorf("invalid attribute rule %q: malformed condition", s)
+			}
+			key, val, ok := strings.Cut(conditions[1:end], "=")
+			if !ok || key == "" {
+				return AttributeRule{}, fmt.Errorf("invalid attribute rule %q: expected [attr=value]", s)
+			}
+			rule.Match[strings.ToLower(key)] = strings.Trim(val, `"'`)
+			conditions = conditions[end+1:]
+		}
+	}
+
+	rule.Tag = strings.ToLower(selector)
+	if rule.Tag == "" {
+		return AttributeRule{}, fmt.Errorf("invalid attribute rule %q: missing tag", s)
+	}
+
+	return rule, nil
+}
+
+// matches reports whether the rule selects attr on an element with the given tag and attributes.
+func (r AttributeRule) matches(tag, attr string, attrs []html.Attribute) bool {
+	if r.Attr != attr || (r.Tag != "*" && r.Tag != tag) {
+		return false
+	}
+
+	for key, want := range r.Match {
+		found := false
+		for _, a := range attrs {
+			if a.Key == key && strings.EqualFold(strings.TrimSpace(a.Val), want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// translatableAttrs returns the indexes of attributes selected by the configured rules.
+// SEO <meta> content is included when WithSEOMetadata is set.
+func (p *HTMLProcessor) translatableAttrs(tag string, attrs []html.Attribute) []int {
+	seo, hasSEO := p.seoContentAttr(tag, attrs)
+	if len(p.attrRules) == 0 && !hasSEO {
+		return nil
+	}
+
+	tag = strings.ToLower(tag)
+	var indexes []int
+	for i, attr := range attrs {
+		if strings.TrimSpace(attr.Val) == "" {
+			continue
+		}
+		if hasSEO && i == seo {
+			indexes = append(indexes, i)
+			continue
+		}
+		for _, rule := range p.attrRules {
+			if rule.matches(tag, attr.Key, attrs) {
+				indexes = append(indexes, i)
+				break
+			}
+		}
+	}
+
+	return indexes
+}
+
+// hashAttr computes the dedupe and cache hash for an attribute value.
+func (p *HTMLProcessor) hashAttr(tag, attr, trimmed string) string {
+	if p.contextKeys {
+		return gotlai.HashTextWithContext(trimmed, attrContextKey(tag, attr))
+	}
+	return gotlai.HashText(trimmed)
+}
+
+// attrContextKey builds a normalized context for an attribute value (e.g. "img@alt").
+func attrContextKey(tag, attr string) string {
+	return strings.ToLower(tag) + "@" + attr
+}
+
+// attrContext describes an attribute value for the AI.
+func attrContext(tag, attr string) string {
+	return fmt.Sprintf("%s attribute of <%s>", attr, strings.ToLower(tag))
+}
+
+// attrTextNode builds a TextNode for the value of attrs[i].
+func (p *HTMLProcessor) attrTextNode(id, tag string, attrs []html.Attribute, i int, trimmed, hash string) gotlai.TextNode {
+	attr := attrs[i].Key
+	node := gotlai.TextNode{
+		ID:       id,
+		Text:     trimmed,
+		Hash:     hash,
+		NodeType: "html_attr",
+		Context:  attrContext(tag, attr),
+		Metadata: map[string]string{
+			"tag":  strings.ToLower(tag),
+			"attr": attr,
+		},
+	}
+
+	if seo, ok := p.seoContentAttr(tag, attrs); ok && seo == i {
+		field, _ := p.seoMeta(tag, attrs)
+		node = seoTextNode(node, field)
+	}
+
+	return node
+}
@@ -0,0 +1,155 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParseAttributeRule(t *testing.T) {
+	tests := []struct {
+		input string
+		tag   string
+		attr  string
+		match map[string]string
+	}{
+		{"img@alt", "img", "alt", nil},
+		{"*@title", "*", "title", nil},
+		{"input[type=submit]@value", "input", "value", map[string]string{"type": "submit"}},
+		{`meta[name="description"]@content`, "meta", "content", map[string]string{"name": "description"}},
+		{"INPUT[Type=image][name=go]@ALT", "input", "alt", map[string]string{"type": "image", "name": "go"}},
+	}
+
+	for _, tt := range tests {
+		rule, err := ParseAttributeRule(tt.input)
+		if err != nil {
+			t.Errorf("ParseAttributeRule(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if rule.Tag != tt.tag || rule.Attr != tt.attr {
+			t.Errorf("ParseAttributeRule(%q) = %s@%s, want %s@%s", tt.input, rule.Tag, rule.Attr, tt.tag, tt.attr)
+		}
+		if len(rule.Match) != len(tt.match) {
+			t.Errorf("ParseAttributeRule(%q) match = %v, want %v", tt.input, rule.Match, tt.match)
+		}
+		for k, v := range tt.match {
+			if rule.Match[k] != v {
+				t.Errorf("ParseAttributeRule(%q) match[%q] = %q, want %q", tt.input, k, rule.Match[k], v)
+			}
+		}
+	}
+
+	for _, invalid := range []string{"", "img", "@alt", "img@", "input[type@value", "input[]@value"} {
+		if _, err := ParseAttributeRule(invalid); err == nil {
+			t.Errorf("ParseAttributeRule(%q) should fail", invalid)
+		}
+	}
+}
+
+func TestHTMLProcessor_Extract_Attributes(t *testing.T) {
+	p := NewHTMLProcessor(WithAttributes())
+
+	html := `<html><head><meta name="description" content="Our store"></head><body>
+		<img src="a.png" alt="A cat">
+		<input type="text" placeholder="Your name" value="prefilled">
+		<input type="submit" value="Send">
+		<a href="/x" title="More info" aria-label="Read more">Link</a>
+		<div data-no-translate><img alt="Logo"></div>
+		<img alt="">
+	</body></html>`
+
+	_, nodes, err := p.Extract(html)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	attrs := make(map[string]string)
+	for _, n := range nodes {
+		if n.NodeType == "html_attr" {
+			attrs[n.Text] = n.Metadata["tag"] + "@" + n.Metadata["attr"]
+		}
+	}
+
+	expected := map[string]string{
+		"Our store": "meta@content",
+		"A cat":     "img@alt",
+		"Your name": "input@placeholder",
+		"Send":      "input@value",
+		"More info": "a@title",
+		"Read more": "a@aria-label",
+	}
+	for text, where := range expected {
+		if attrs[text] != where {
+			t.Errorf("Expected %q from %s, got %q", text, where, attrs[text])
+		}
+	}
+	if len(attrs) != len(expected) {
+		t.Errorf("Expected %d attribute nodes, got %d: %v", len(expected), len(attrs), attrs)
+	}
+}
+
+func TestHTMLProcessor_Extract_AttributesDisabled(t *testing.T) {
+	_, nodes, err := NewHTMLProcessor().Extract(`<img alt="A cat"><p>Hello</p>`)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(nodes) != 1 {
+		t.Errorf("Attributes should not be extracted by default, got %d nodes", len(nodes))
+	}
+}
+
+func TestHTMLProcessor_Apply_Attributes(t *testing.T) {
+	rule, _ := ParseAttributeRule("img@alt")
+	p := NewHTMLProcessor(WithAttributes(rule))
+
+	parsed, nodes, err := p.Extract(`<p title="Tip">Hello</p><img src="a.png" alt="A cat">`)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(nodes) != 2 {
+		t.Fatalf("Expected 2 nodes (custom rules only), got %d", len(nodes))
+	}
+
+	translations := make(map[string]string)
+	for _, n := range nodes {
+		translations[n.Hash] = "[" + n.Text + "]"
+	}
+
+	result, err := p.Apply(parsed, nodes, translations)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if !strings.Contains(result, `alt="[A cat]"`) {
+		t.Errorf("Result should contain translated alt, got: %s", result)
+	}
+	if !strings.Contains(result, `title="Tip"`) {
+		t.Errorf("Unselected attributes should be untouched, got: %s", result)
+	}
+
+	// The document is restored for reuse
+	result, err = p.Apply(parsed, nodes, map[string]string{})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if !strings.Contains(result, `alt="A cat"`) {
+		t.Errorf("Attributes should be restored after Apply, got: %s", result)
+	}
+}
+
+func TestHTMLProcessor_Stream_Attributes(t *testing.T) {
+	p := NewHTMLProcessor(WithAttributes())
+	tr := &fakeStreamTranslator{}
+
+	input := `<p>Hi <img src='a.png' alt="A cat"> <input type="text" value="keep"></p>`
+
+	var out bytes.Buffer
+	if err := p.Stream(context.Background(), strings.NewReader(input), &out, tr); err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+
+	expected := `<p>[Hi] <img src="a.png" alt="[A cat]"> <input type="text" value="keep"></p>`
+	if out.String() != expected {
+		t.Errorf("Stream output = %q, want %q", out.String(), expected)
+	}
+}
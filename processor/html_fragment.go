@@ -0,0 +1,43 @@
+package processor
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// WithFragment treats content as an HTML fragment, such as a CMS snippet, parsed
+// as the children of the given context element (default "body"). Apply returns
+// the fragment without <html>, <head> or <body> wrappers and splices translations
+// into the original source, so every byte outside translated text is kept. If
+// the parser restructured the markup too much to locate a change (e.g. text
+// moved out of a table), the fragment is re-serialized instead.
+func WithFragment(contextTag string) HTMLProcessorOption {
+	return func(p *HTMLProcessor) {
+		if contextTag == "" {
+			contextTag = "body"
+		}
+		p.fragmentContext = strings.ToLower(contextTag)
+	}
+}
+
+// parseFragment parses content as children of a detached context element and
+// returns that element.
+func (p *HTMLProcessor) parseFragment(content string) (*html.Node, error) {
+	root := &html.Node{
+		Type:     html.ElementNode,
+		DataAtom: atom.Lookup([]byte(p.fragmentContext)),
+		Data:     p.fragmentContext,
+	}
+
+	children, err := html.ParseFragment(strings.NewReader(content), root)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range children {
+		root.AppendChild(c)
+	}
+
+	return root, nil
+}
@@ -0,0 +1,159 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ZaguanLabs/gotlai"
+)
+
+// bracketAll translates every node by bracketing its text.
+func bracketAll(nodes []gotlai.TextNode) map[string]string {
+	translations := make(map[string]string)
+	for _, n := range nodes {
+		translations[n.Hash] = "[" + n.Text + "]"
+	}
+	return translations
+}
+
+func TestHTMLProcessor_Fragment(t *testing.T) {
+	p := NewHTMLProcessor(WithFragment(""))
+
+	input := "<p class='intro'>Hello &amp; welcome<br>\n  <img src=logo.png></p>\n<!-- note --><ul><li>One</li><li>Two</ul>"
+
+	parsed, nodes, err := p.Extract(input)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(nodes) != 3 {
+		t.Fatalf("Expected 3 nodes, got %d", len(nodes))
+	}
+
+	// Untranslated fragments round-trip byte for byte
+	result, err := p.Apply(parsed, nodes, map[string]string{})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if result != input {
+		t.Errorf("Round trip = %q, want %q", result, input)
+	}
+
+	result, err = p.Apply(parsed, nodes, bracketAll(nodes))
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	expected := "<p class='intro'>[Hello &amp; welcome]<br>\n  <img src=logo.png></p>\n<!-- note --><ul><li>[One]</li><li>[Two]</ul>"
+	if result != expected {
+		t.Errorf("Apply = %q, want %q", result, expected)
+	}
+}
+
+func TestHTMLProcessor_FragmentContext(t *testing.T) {
+	// In a <tr> context, cells parse as table content instead of being dropped
+	p := NewHTMLProcessor(WithFragment("tr"))
+
+	input := "<td>Name</td><td>Price</td>"
+	parsed, nodes, err := p.Extract(input)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(nodes) != 2 {
+		t.Fatalf("Expected 2 nodes, got %d", len(nodes))
+	}
+	if !strings.Contains(nodes[0].Context, "<td>") {
+		t.Errorf("Expected <td> context, got %q", nodes[0].Context)
+	}
+
+	result, err := p.Apply(parsed, nodes, bracketAll(nodes))
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if result != "<td>[Name]</td><td>[Price]</td>" {
+		t.Errorf("Unexpected result: %q", result)
+	}
+}
+
+func TestHTMLProcessor_FragmentAttributes(t *testing.T) {
+	p := NewHTMLProcessor(WithFragment(""), WithAttributes())
+
+	input := `<img alt='Logo' src="a.png" ><input type=submit value=Send>`
+	parsed, nodes, err := p.Extract(input)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	result, err := p.Apply(parsed, nodes, map[string]string{
+		gotlai.HashText("Logo"): "L'logo",
+		gotlai.HashText("Send"): "Envoyer",
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	expected := `<img alt='L&#39;logo' src="a.png" ><input type=submit value="Envoyer">`
+	if result != expected {
+		t.Errorf("Apply = %q, want %q", result, expected)
+	}
+}
+
+func TestHTMLProcessor_FragmentSegments(t *testing.T) {
+	p := NewHTMLProcessor(WithFragment(""), WithInlineSegments())
+
+	input := "<div class=x><p>Click <a href='/x'>here</a> now</p>\n<p>Plain</p></div>"
+	parsed, nodes, err := p.Extract(input)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	result, err := p.Apply(parsed, nodes, map[string]string{
+		nodes[0].Hash: "<x1>Ici</x1> cliquer",
+		nodes[1].Hash: "Simple",
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	expected := "<div class=x><p><a href=\"/x\">Ici</a> cliquer</p>\n<p>Simple</p></div>"
+	if result != expected {
+		t.Errorf("Apply = %q, want %q", result, expected)
+	}
+}
+
+func TestHTMLProcessor_FragmentFallback(t *testing.T) {
+	p := NewHTMLProcessor(WithFragment(""))
+
+	// Text inside a table is moved before it by the parser
+	input := "<table>Stray<tr><td>Cell</td></tr></table>"
+	parsed, nodes, err := p.Extract(input)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	result, err := p.Apply(parsed, nodes, bracketAll(nodes))
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if strings.Contains(result, "<body>") || strings.Contains(result, "<html>") {
+		t.Errorf("Fragment should not gain wrappers, got %q", result)
+	}
+	if !strings.Contains(result, "[Stray]") || !strings.Contains(result, "[Cell]") {
+		t.Errorf("Expected translated text, got %q", result)
+	}
+}
+
+func TestHTMLProcessor_FragmentStream(t *testing.T) {
+	p := NewHTMLProcessor(WithFragment("textarea"))
+
+	input := "Hello <b>there</b>"
+	var out bytes.Buffer
+	if err := p.Stream(context.Background(), strings.NewReader(input), &out, &fakeStreamTranslator{}); err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+
+	// Textarea content is raw text, so the markup is part of the text
+	if out.String() != "[Hello &lt;b&gt;there&lt;/b&gt;]" {
+		t.Errorf("Unexpected stream output: %q", out.String())
+	}
+}
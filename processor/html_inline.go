@@ -0,0 +1,373 @@
+package processor
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ZaguanLabs/gotlai"
+	"golang.org/x/net/html"
+)
+
+// inlineElements contains elements that flow within a sentence.
+var inlineElements = map[string]bool{
+	"a":      true,
+	"abbr":   true,
+	"b":      true,
+	"bdi":    true,
+	"bdo":    true,
+	"br":     true,
+	"cite":   true,
+	"code":   true,
+	"data":   true,
+	"del":    true,
+	"dfn":    true,
+	"em":     true,
+	"i":      true,
+	"img":    true,
+	"ins":    true,
+	"kbd":    true,
+	"mark":   true,
+	"q":      true,
+	"s":      true,
+	"samp":   true,
+	"small":  true,
+	"span":   true,
+	"strong": true,
+	"sub":    true,
+	"sup":    true,
+	"time":   true,
+	"u":      true,
+	"var":    true,
+	"wbr":    true,
+}
+
+// placeholderPattern matches inline placeholders: <x1>, </x1> and <x1/>.
+var placeholderPattern = regexp.MustCompile(`<(/?)x(\d+)\s*(/?)>`)
+
+// WithInlineSegments translates blocks with inline children as single segments.
+// In <p>Click <a href="/x">here</a> to continue</p>, the AI receives
+// "Click <x1>here</x1> to continue" and may reorder the placeholders; Apply
+// rebuilds the block in the translated order. Ignored inline elements such as
+// <code> become self-closing placeholders and are kept untouched. Translations
+// with missing, duplicated or misnested placeholders are rejected. Streaming
+// still translates text runs individually.
+func WithInlineSegments() HTMLProcessorOption {
+	return func(p *HTMLProcessor) {
+		p.inlineSegments = true
+	}
+}
+
+// inlineSegment is a block's content flattened into text with placeholders.
+type inlineSegment struct {
+	text     string       // Trimmed text with placeholders and collapsed whitespace
+	leading  string       // Leading whitespace of the block content
+	trailing string       // Trailing whitespace of the block content
+	elements []*html.Node // Placeholder N refers to elements[N-1]
+	paired   []bool       // Whether placeholder N wraps translatable content
+}
+
+// segmentItem is a parsed piece of a translated segment: text or a placeholder.
+type segmentItem struct {
+	text     string // Escaped text, when id is 0
+	id       int
+	children []segmentItem
+}
+
+// opaque reports whether an element's content must be kept untouched.
+func (p *HTMLProcessor) opaque(n *html.Node) bool {
+	return p.skipped(n) || !p.translates(n, true)
+}
+
+// segmentable reports whether n is a block whose content is text mixed with
+// inline elements only, so it can be translated as one segment.
+func (p *HTMLProcessor) segmentable(n *html.Node) bool {
+	if !p.inlineSegments || n.Type != html.ElementNode || inlineElements[strings.ToLower(n.Data)] {
+		return false
+	}
+
+	hasInline, hasText := false, false
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch c.Type {
+		case html.TextNode:
+			if strings.TrimSpace(c.Data) != "" {
+				hasText = true
+			}
+		case html.ElementNode:
+			hasInline = true
+		}
+	}
+	if !hasInline || !hasText {
+		return false
+	}
+
+	var inlineOnly func(*html.Node) bool
+	inlineOnly = func(parent *html.Node) bool {
+		for c := parent.FirstChild; c != nil; c = c.NextSibling {
+			switch c.Type {
+			case html.TextNode:
+			case html.ElementNode:
+				if !inlineElements[strings.ToLower(c.Data)] {
+					return false
+				}
+				if !p.opaque(c) && !inlineOnly(c) {
+					return false
+				}
+			default:
+				return false
+			}
+		}
+		return true
+	}
+
+	return inlineOnly(n)
+}
+
+// buildSegment flattens a segmentable block into text with placeholders.
+func (p *HTMLProcessor) buildSegment(n *html.Node) *inlineSegment {
+	seg := &inlineSegment{}
+
+	var b strings.Builder
+	var build func(*html.Node)
+	build = func(parent *html.Node) {
+		for c := parent.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.TextNode {
+				b.WriteString(escapeSegmentText(c.Data))
+				continue
+			}
+
+			seg.elements = append(seg.elements, c)
+			id := len(seg.elements)
+			if p.opaque(c) || c.FirstChild == nil {
+				seg.paired = append(seg.paired, false)
+				fmt.Fprintf(&b, "<x%d/>", id)
+				continue
+			}
+
+			seg.paired = append(seg.paired, true)
+			fmt.Fprintf(&b, "<x%d>", id)
+			build(c)
+			fmt.Fprintf(&b, "</x%d>", id)
+		}
+	}
+	build(n)
+
+	raw := b.String()
+	trimmed := strings.TrimSpace(raw)
+	seg.leading = raw[:strings.Index(raw, trimmed)]
+	seg.trailing = raw[len(seg.leading)+len(trimmed):]
+	seg.text = strings.Join(strings.Fields(trimmed), " ")
+
+	return seg
+}
+
+// hashSegment computes the dedupe and cache hash for a segment.
+func (p *HTMLProcessor) hashSegment(block *html.Node, text string) string {
+	if p.contextKeys {
+		return gotlai.HashTextWithContext(text, elementContextKey(block))
+	}
+	return gotlai.HashText(text)
+}
+
+// segmentTextNode builds a TextNode for a segment.
+func (p *HTMLProcessor) segmentTextNode(id string, block *html.Node, seg *inlineSegment) gotlai.TextNode {
+	parts := []string{"in " + describeElement(block)}
+	if ancestors := ancestorPath(block); ancestors != "" {
+		parts = append(parts, "inside: "+ancestors)
+	}
+	parts = append(parts, "contains inline markup placeholders")
+
+	node := gotlai.TextNode{
+		ID:       id,
+		Text:     seg.text,
+		Hash:     p.hashSegment(block, seg.text),
+		NodeType: "html_segment",
+		Context:  strings.Join(parts, " | "),
+		Metadata: map[string]string{
+			"parent_tag":   block.Data,
+			"placeholders": encodePlaceholders(seg.paired),
+		},
+	}
+	if p.contextKeys {
+		node.Metadata["context_key"] = elementContextKey(block)
+	}
+
+	return node
+}
+
+// rebuildSegment replaces the block's children with the translated items.
+func (p *HTMLProcessor) rebuildSegment(block *html.Node, seg *inlineSegment, items []segmentItem) {
+	for c := block.FirstChild; c != nil; c = block.FirstChild {
+		block.RemoveChild(c)
+	}
+
+	if seg.leading != "" {
+		block.AppendChild(&html.Node{Type: html.TextNode, Data: seg.leading})
+	}
+
+	var appendItems func(*html.Node, []segmentItem)
+	appendItems = func(parent *html.Node, items []segmentItem) {
+		for _, item := range items {
+			if item.id == 0 {
+				parent.AppendChild(&html.Node{Type: html.TextNode, Data: html.UnescapeString(item.text)})
+				continue
+			}
+
+			original := seg.elements[item.id-1]
+			if !seg.paired[item.id-1] {
+				parent.AppendChild(cloneNode(original, true))
+				continue
+			}
+
+			el := cloneNode(original, false)
+			parent.AppendChild(el)
+			appendItems(el, item.children)
+		}
+	}
+	appendItems(block, items)
+
+	if seg.trailing != "" {
+		block.AppendChild(&html.Node{Type: html.TextNode, Data: seg.trailing})
+	}
+}
+
+// ValidateTranslation checks that a segment translation keeps every placeholder
+// exactly once, properly nested, in the same paired or self-closing form.
+func (p *HTMLProcessor) ValidateTranslation(node gotlai.TextNode, translated string) error {
+	if node.NodeType != "html_segment" {
+		return nil
+	}
+	_, err := parseSegment(translated, decodePlaceholders(node.Metadata["placeholders"]))
+	return err
+}
+
+// parseSegment parses a translated segment into items, validating its placeholders.
+func parseSegment(s string, paired []bool) ([]segmentItem, error) {
+	type frame struct {
+		id    int
+		items []segmentItem
+	}
+
+	stack := []frame{{}}
+	seen := make(map[int]bool)
+	pos := 0
+
+	for _, m := range placeholderPattern.FindAllStringSubmatchIndex(s, -1) {
+		top := &stack[len(stack)-1]
+		if m[0] > pos {
+			top.items = append(top.items, segmentItem{text: s[pos:m[0]]})
+		}
+		pos = m[1]
+
+		closing := m[3] > m[2]
+		selfClosing := m[7] > m[6]
+		id, err := strconv.Atoi(s[m[4]:m[5]])
+		if err != nil || id < 1 || id > len(paired) {
+			return nil, fmt.Errorf("unknown placeholder %q", s[m[0]:m[1]])
+		}
+
+		switch {
+		case closing && selfClosing:
+			return nil, fmt.Errorf("malformed placeholder %q", s[m[0]:m[1]])
+		case closing:
+			if len(stack) == 1 || top.id != id {
+				return nil, fmt.Errorf("misnested placeholder </x%d>", id)
+			}
+			item := segmentItem{id: id, children: top.items}
+			stack = stack[:len(stack)-1]
+			parent := &stack[len(stack)-1]
+			parent.items = append(parent.items, item)
+		default:
+			if seen[id] {
+				return nil, fmt.Errorf("duplicate placeholder x%d", id)
+			}
+			seen[id] = true
+			if selfClosing != !paired[id-1] {
+				return nil, fmt.Errorf("placeholder x%d has the wrong form", id)
+			}
+			if selfClosing {
+				top.items = append(top.items, segmentItem{id: id})
+			} else {
+				stack = append(stack, frame{id: id})
+			}
+		}
+	}
+
+	if pos < len(s) {
+		top := &stack[len(stack)-1]
+		top.items = append(top.items, segmentItem{text: s[pos:]})
+	}
+	if len(stack) != 1 {
+		return nil, fmt.Errorf("unclosed placeholder <x%d>", stack[len(stack)-1].id)
+	}
+	if len(seen) != len(paired) {
+		return nil, fmt.Errorf("expected %d placeholders, got %d", len(paired), len(seen))
+	}
+
+	return stack[0].items, nil
+}
+
+// escapeSegmentText escapes text so it cannot be confused with placeholders.
+func escapeSegmentText(s string) string {
+	s = strings.ReplaceAll(s, "&", "&amp;")
+	s = strings.ReplaceAll(s, "<", "&lt;")
+	s = strings.ReplaceAll(s, ">", "&gt;")
+	return s
+}
+
+// encodePlaceholders encodes placeholder forms as "p" (paired) or "s" (self-closing) per placeholder.
+func encodePlaceholders(paired []bool) string {
+	var b strings.Builder
+	for _, isPaired := range paired {
+		if isPaired {
+			b.WriteByte('p')
+		} else {
+			b.WriteByte('s')
+		}
+	}
+	return b.String()
+}
+
+// decodePlaceholders reverses encodePlaceholders.
+func decodePlaceholders(s string) []bool {
+	paired := make([]bool, len(s))
+	for i := range s {
+		paired[i] = s[i] == 'p'
+	}
+	return paired
+}
+
+// cloneNode copies an HTML node, including its descendants when deep is true.
+func cloneNode(n *html.Node, deep bool) *html.Node {
+	clone := &html.Node{
+		Type:      n.Type,
+		DataAtom:  n.DataAtom,
+		Data:      n.Data,
+		Namespace: n.Namespace,
+		Attr:      append([]html.Attribute(nil), n.Attr...),
+	}
+	if deep {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			clone.AppendChild(cloneNode(c, true))
+		}
+	}
+	return clone
+}
+
+// forEachInline calls fn for each element inside a segment, skipping opaque content.
+func (p *HTMLProcessor) forEachInline(block *html.Node, fn func(*html.Node)) {
+	for c := block.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		if p.opaque(c) {
+			continue
+		}
+		fn(c)
+		p.forEachInline(c, fn)
+	}
+}
+
+// Verify HTMLProcessor implements TranslationValidator
+var _ gotlai.TranslationValidator = (*HTMLProcessor)(nil)
@@ -0,0 +1,161 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ZaguanLabs/gotlai"
+)
+
+func TestHTMLProcessor_Extract_InlineSegments(t *testing.T) {
+	p := NewHTMLProcessor(WithInlineSegments())
+
+	html := `<div>
+		<p>Click <a href="/x">here</a> to continue</p>
+		<p>Run <code>make test</code> before you <b>push <em>changes</em></b>.<br></p>
+		<p><a href="/">Home</a></p>
+		<p>Plain text</p>
+	</div>`
+
+	_, nodes, err := p.Extract(html)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	var texts []string
+	for _, n := range nodes {
+		texts = append(texts, n.Text)
+	}
+
+	expected := []string{
+		"Click <x1>here</x1> to continue",
+		"Run <x1/> before you <x2>push <x3>changes</x3></x2>.<x4/>",
+		"Home",
+		"Plain text",
+	}
+	if len(texts) != len(expected) {
+		t.Fatalf("Expected %d nodes, got %d: %q", len(expected), len(texts), texts)
+	}
+	for i := range expected {
+		if texts[i] != expected[i] {
+			t.Errorf("Node %d = %q, want %q", i, texts[i], expected[i])
+		}
+	}
+
+	if nodes[0].NodeType != "html_segment" {
+		t.Errorf("Expected node type 'html_segment', got %q", nodes[0].NodeType)
+	}
+	if nodes[2].NodeType != "html_text" {
+		t.Errorf("Block without surrounding text should not be a segment, got %q", nodes[2].NodeType)
+	}
+}
+
+func TestHTMLProcessor_Extract_InlineSegmentsNestedBlocks(t *testing.T) {
+	p := NewHTMLProcessor(WithInlineSegments())
+
+	_, nodes, err := p.Extract(`<div>Intro <b>bold</b><p>Inner <i>para</i></p></div>`)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	var texts []string
+	for _, n := range nodes {
+		texts = append(texts, n.Text)
+	}
+
+	// The div contains a block, so only the inner paragraph is a segment
+	expected := []string{"Intro", "bold", "Inner <x1>para</x1>"}
+	if strings.Join(texts, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, texts)
+	}
+}
+
+func TestHTMLProcessor_Apply_InlineSegments(t *testing.T) {
+	p := NewHTMLProcessor(WithInlineSegments())
+
+	parsed, nodes, err := p.Extract(`<p>Click <a href="/x">here</a> to <code>go()</code> continue</p>`)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	translations := map[string]string{
+		nodes[0].Hash: "Um <x2/> fortzufahren, <x1>hier</x1> klicken",
+	}
+
+	result, err := p.Apply(parsed, nodes, translations)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	expected := `<p>Um <code>go()</code> fortzufahren, <a href="/x">hier</a> klicken</p>`
+	if !strings.Contains(result, expected) {
+		t.Errorf("Expected %q in result, got: %s", expected, result)
+	}
+
+	// The document is restored for reuse
+	result, err = p.Apply(parsed, nodes, map[string]string{})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if !strings.Contains(result, `<p>Click <a href="/x">here</a> to <code>go()</code> continue</p>`) {
+		t.Errorf("Segment should be restored after Apply, got: %s", result)
+	}
+}
+
+func TestHTMLProcessor_Apply_InlineSegmentsInvalid(t *testing.T) {
+	p := NewHTMLProcessor(WithInlineSegments())
+
+	html := `<p>Click <a href="/x">here</a> now</p>`
+	parsed, nodes, err := p.Extract(html)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	// Invalid translations leave the source untouched
+	result, err := p.Apply(parsed, nodes, map[string]string{nodes[0].Hash: "Klicken Sie jetzt"})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if !strings.Contains(result, html) {
+		t.Errorf("Invalid translation should keep source, got: %s", result)
+	}
+}
+
+func TestHTMLProcessor_ValidateTranslation(t *testing.T) {
+	p := NewHTMLProcessor(WithInlineSegments())
+
+	_, nodes, err := p.Extract(`<p>Press <kbd>Enter</kbd> or <br> <b>click</b></p>`)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	node := nodes[0]
+
+	valid := []string{
+		"Drücken Sie <x1>Enter</x1> oder <x2/> <x3>klicken</x3>",
+		"<x3>Klicken</x3> <x2/> oder <x1>Enter</x1> drücken",
+	}
+	for _, s := range valid {
+		if err := p.ValidateTranslation(node, s); err != nil {
+			t.Errorf("ValidateTranslation(%q) failed: %v", s, err)
+		}
+	}
+
+	invalid := []string{
+		"Drücken Sie Enter oder klicken",                       // missing
+		"<x1>Enter</x1> <x1>Enter</x1> <x2/> <x3>klicken</x3>", // duplicate
+		"<x1>Enter <x3>klicken</x1></x3> <x2/>",                // misnested
+		"<x1>Enter</x1> <x2>oder</x2> <x3>klicken</x3>",        // wrong form
+		"<x1>Enter</x1> <x2/> <x3>klicken</x3> <x4/>",          // unknown
+		"<x1>Enter <x2/> <x3>klicken</x3>",                     // unclosed
+	}
+	for _, s := range invalid {
+		if err := p.ValidateTranslation(node, s); err == nil {
+			t.Errorf("ValidateTranslation(%q) should fail", s)
+		}
+	}
+
+	// Plain text nodes are always valid
+	if err := p.ValidateTranslation(gotlai.TextNode{NodeType: "html_text"}, "<x9>"); err != nil {
+		t.Errorf("Text nodes should not be validated, got %v", err)
+	}
+}
@@ -0,0 +1,224 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ZaguanLabs/gotlai"
+	"golang.org/x/net/html"
+)
+
+// DefaultJSONLDKeys lists the schema.org keys translated by WithJSONLD when no keys are given.
+var DefaultJSONLDKeys = []string{"name", "description", "headline"}
+
+// WithJSONLD translates string values of the given schema.org keys inside
+// <script type="application/ld+json"> blocks, which are otherwise ignored like
+// other scripts. With no keys, DefaultJSONLDKeys are used. Values may be plain
+// strings, arrays of strings or {"@value": ...} objects. Only the translated
+// string literals are rewritten, so the JSON stays valid and keeps its key order
+// and formatting; blocks that are not valid JSON are left untouched.
+func WithJSONLD(keys ...string) HTMLProcessorOption {
+	return func(p *HTMLProcessor) {
+		if len(keys) == 0 {
+			keys = DefaultJSONLDKeys
+		}
+		p.jsonLDKeys = make(map[string]bool)
+		for _, key := range keys {
+			p.jsonLDKeys[key] = true
+		}
+	}
+}
+
+// jsonLDValue is a translatable string literal in a JSON-LD block.
+type jsonLDValue struct {
+	key        string // schema.org key, e.g. "name"
+	schemaType string // @type of the enclosing object, if any
+	text       string // Decoded string value
+	start, end int    // Byte range of the string literal, including quotes
+}
+
+// jsonLDFrame is an open object or array while scanning JSON-LD.
+type jsonLDFrame struct {
+	object    bool
+	key       string // Current key in an object, or the key holding an array
+	parentKey string // Key holding this container
+	expectKey bool
+	obj       int // Index of the enclosing object
+}
+
+// jsonLDScript reports whether n is a JSON-LD script to translate.
+func (p *HTMLProcessor) jsonLDScript(n *html.Node) bool {
+	if len(p.jsonLDKeys) == 0 || n.Type != html.ElementNode || n.Data != "script" {
+		return false
+	}
+	return isJSONLD(n.Attr)
+}
+
+// isJSONLD reports whether script attributes declare JSON-LD content.
+func isJSONLD(attrs []html.Attribute) bool {
+	noTranslate, jsonLD := false, false
+	for _, attr := range attrs {
+		switch attr.Key {
+		case "type":
+			jsonLD = strings.EqualFold(strings.TrimSpace(attr.Val), "application/ld+json")
+		case "data-no-translate":
+			noTranslate = true
+		}
+	}
+	return jsonLD && !noTranslate
+}
+
+// jsonLDValues scans a JSON-LD document for string values at the configured keys.
+func (p *HTMLProcessor) jsonLDValues(src string) ([]jsonLDValue, error) {
+	dec := json.NewDecoder(strings.NewReader(src))
+	dec.UseNumber()
+
+	var values []jsonLDValue
+	var valueObjs []int
+	var stack []jsonLDFrame
+	types := make(map[int]string)
+	objects := 0
+
+	// valueDone marks the end of a value inside the current container
+	valueDone := func() {
+		if len(stack) > 0 && stack[len(stack)-1].object {
+			stack[len(stack)-1].expectKey = true
+		}
+	}
+
+	for {
+		offset := dec.InputOffset()
+		tok, err := dec.Token()
+		if err == io.EOF && len(stack) == 0 {
+			break
+		}
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var top *jsonLDFrame
+		if len(stack) > 0 {
+			top = &stack[len(stack)-1]
+		}
+
+		switch v := tok.(type) {
+		case json.Delim:
+			switch v {
+			case '{', '[':
+				frame := jsonLDFrame{object: v == '{', expectKey: v == '{'}
+				if top != nil {
+					frame.parentKey = top.key
+					frame.obj = top.obj
+				}
+				if v == '{' {
+					objects++
+					frame.obj = objects
+				} else {
+					frame.key = frame.parentKey
+				}
+				stack = append(stack, frame)
+			default:
+				stack = stack[:len(stack)-1]
+				valueDone()
+			}
+		case string:
+			if top != nil && top.object && top.expectKey {
+				top.key = v
+				top.expectKey = false
+				continue
+			}
+
+			key := ""
+			if top != nil {
+				key = top.key
+				if key == "@value" && top.object {
+					key = top.parentKey
+				}
+			}
+			if top != nil && key == "@type" && types[top.obj] == "" {
+				types[top.obj] = v
+			}
+			if p.jsonLDKeys[key] && strings.TrimSpace(v) != "" {
+				start := offset + int64(strings.IndexByte(src[offset:], '"'))
+				values = append(values, jsonLDValue{
+					key:   key,
+					text:  v,
+					start: int(start),
+					end:   int(dec.InputOffset()),
+				})
+				valueObjs = append(valueObjs, top.obj)
+			}
+			valueDone()
+		default:
+			valueDone()
+		}
+	}
+
+	// @type may follow the translated keys, so resolve it last
+	for i := range values {
+		values[i].schemaType = types[valueObjs[i]]
+	}
+
+	return values, nil
+}
+
+// hashJSONLD computes the dedupe and cache hash for a JSON-LD value.
+func (p *HTMLProcessor) hashJSONLD(v jsonLDValue) string {
+	trimmed := strings.TrimSpace(v.text)
+	if p.contextKeys {
+		return gotlai.HashTextWithContext(trimmed, "jsonld|"+v.key)
+	}
+	return gotlai.HashText(trimmed)
+}
+
+// jsonLDTextNode builds a TextNode for a JSON-LD value.
+func (p *HTMLProcessor) jsonLDTextNode(id string, v jsonLDValue) gotlai.TextNode {
+	subject := v.key
+	if v.schemaType != "" {
+		subject = fmt.Sprintf("schema.org %s %s", v.schemaType, v.key)
+	}
+
+	node := gotlai.TextNode{
+		ID:       id,
+		Text:     strings.TrimSpace(v.text),
+		Hash:     p.hashJSONLD(v),
+		NodeType: "html_jsonld",
+		Context:  subject + " in JSON-LD structured data for search engines",
+		Metadata: map[string]string{
+			"key": v.key,
+		},
+	}
+	if v.schemaType != "" {
+		node.Metadata["schema_type"] = v.schemaType
+	}
+
+	return node
+}
+
+// rewriteJSONLD replaces translated string literals in a JSON-LD document.
+func (p *HTMLProcessor) rewriteJSONLD(src string, values []jsonLDValue, translations map[string]string) string {
+	var b strings.Builder
+	pos := 0
+	for _, v := range values {
+		translated, ok := translations[p.hashJSONLD(v)]
+		if !ok {
+			continue
+		}
+		// json.Marshal escapes <, > and &, so the result cannot close the script
+		literal, err := json.Marshal(preserveWhitespace(v.text, translated))
+		if err != nil {
+			continue
+		}
+		b.WriteString(src[pos:v.start])
+		b.Write(literal)
+		pos = v.end
+	}
+	b.WriteString(src[pos:])
+
+	return b.String()
+}
@@ -0,0 +1,168 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const jsonLDHTML = `<html><head>
+<script type="application/ld+json">
+{
+  "@context": "https://schema.org",
+  "name": "Trail Shoe",
+  "@type": "Product",
+  "url": "https://example.com/?a=1&b=2",
+  "description": {"@value": "Light & fast", "@language": "en"},
+  "review": [{"@type": "Review", "headline": "Great", "name": "<\/script> test"}],
+  "sku": "TS-1",
+  "keywords": ["name", "other"]
+}
+</script>
+<script>var name = "Trail Shoe";</script>
+</head><body><p>Hello</p></body></html>`
+
+func TestHTMLProcessor_Extract_JSONLD(t *testing.T) {
+	p := NewHTMLProcessor(WithJSONLD())
+
+	_, nodes, err := p.Extract(jsonLDHTML)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	var texts []string
+	for _, n := range nodes {
+		if n.NodeType == "html_jsonld" {
+			texts = append(texts, n.Metadata["key"]+"="+n.Text)
+		}
+	}
+
+	expected := []string{"name=Trail Shoe", "description=Light & fast", "headline=Great", "name=</script> test"}
+	if strings.Join(texts, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, texts)
+	}
+
+	if nodes[0].Metadata["schema_type"] != "Product" || !strings.Contains(nodes[0].Context, "schema.org Product name") {
+		t.Errorf("Expected Product context, got %q %v", nodes[0].Context, nodes[0].Metadata)
+	}
+	if nodes[2].Metadata["schema_type"] != "Review" {
+		t.Errorf("Expected Review type for nested object, got %v", nodes[2].Metadata)
+	}
+}
+
+func TestHTMLProcessor_Extract_JSONLDDisabled(t *testing.T) {
+	p := NewHTMLProcessor()
+
+	_, nodes, err := p.Extract(jsonLDHTML)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].Text != "Hello" {
+		t.Errorf("JSON-LD should be ignored by default, got %v", nodes)
+	}
+}
+
+// scriptJSON returns the JSON-LD script body of an HTML document.
+func scriptJSON(t *testing.T, doc string) string {
+	t.Helper()
+	start := strings.Index(doc, `<script type="application/ld+json">`)
+	if start < 0 {
+		t.Fatalf("No JSON-LD script in %s", doc)
+	}
+	body := doc[start+len(`<script type="application/ld+json">`):]
+	return body[:strings.Index(body, "</script>")]
+}
+
+func TestHTMLProcessor_Apply_JSONLD(t *testing.T) {
+	for _, minimal := range []bool{false, true} {
+		opts := []HTMLProcessorOption{WithJSONLD("name", "headline")}
+		if minimal {
+			opts = append(opts, WithMinimalDiff())
+		}
+		p := NewHTMLProcessor(opts...)
+
+		parsed, nodes, err := p.Extract(jsonLDHTML)
+		if err != nil {
+			t.Fatalf("Extract failed: %v", err)
+		}
+
+		result, err := p.Apply(parsed, nodes, bracketAll(nodes))
+		if err != nil {
+			t.Fatalf("Apply failed: %v", err)
+		}
+
+		body := scriptJSON(t, result)
+		if !json.Valid([]byte(body)) {
+			t.Fatalf("JSON-LD should stay valid, got:\n%s", body)
+		}
+
+		var data map[string]interface{}
+		if err := json.Unmarshal([]byte(body), &data); err != nil {
+			t.Fatalf("Unmarshal failed: %v", err)
+		}
+		if data["name"] != "[Trail Shoe]" {
+			t.Errorf("Expected translated name, got %v", data["name"])
+		}
+		review := data["review"].([]interface{})[0].(map[string]interface{})
+		if review["headline"] != "[Great]" || review["name"] != "[</script> test]" {
+			t.Errorf("Expected translated review, got %v", review)
+		}
+		if data["url"] != "https://example.com/?a=1&b=2" {
+			t.Errorf("URL should be unchanged, got %v", data["url"])
+		}
+
+		// Formatting and key order are kept
+		if !strings.Contains(body, "\n  \"name\": \"[Trail Shoe]\",\n  \"@type\": \"Product\",\n") {
+			t.Errorf("Expected original layout, got:\n%s", body)
+		}
+		if strings.Count(result, "</script>") != 2 {
+			t.Errorf("Translated values must not close the script, got:\n%s", result)
+		}
+		if !strings.Contains(result, `var name = "Trail Shoe";`) {
+			t.Errorf("Other scripts should be unchanged, got:\n%s", result)
+		}
+	}
+}
+
+func TestHTMLProcessor_Stream_JSONLD(t *testing.T) {
+	p := NewHTMLProcessor(WithJSONLD())
+	tr := &fakeStreamTranslator{}
+
+	var out bytes.Buffer
+	if err := p.Stream(context.Background(), strings.NewReader(jsonLDHTML), &out, tr); err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+
+	body := scriptJSON(t, out.String())
+	if !json.Valid([]byte(body)) {
+		t.Fatalf("JSON-LD should stay valid, got:\n%s", body)
+	}
+	for _, s := range []string{`"name": "[Trail Shoe]"`, `"@value": "[Light \u0026 fast]"`, `"headline": "[Great]"`} {
+		if !strings.Contains(body, s) {
+			t.Errorf("Expected %s in JSON-LD, got:\n%s", s, body)
+		}
+	}
+}
+
+func TestHTMLProcessor_JSONLDInvalid(t *testing.T) {
+	p := NewHTMLProcessor(WithJSONLD())
+
+	html := `<script type="application/ld+json">{"name": "Broken",</script><p>Hello</p>`
+	parsed, nodes, err := p.Extract(html)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(nodes) != 1 {
+		t.Errorf("Invalid JSON-LD should be skipped, got %v", nodes)
+	}
+
+	result, err := p.Apply(parsed, nodes, bracketAll(nodes))
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if !strings.Contains(result, `{"name": "Broken",</script>`) {
+		t.Errorf("Invalid JSON-LD should be unchanged, got: %s", result)
+	}
+}
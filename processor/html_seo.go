@@ -0,0 +1,98 @@
+package processor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ZaguanLabs/gotlai"
+	"golang.org/x/net/html"
+)
+
+// seoField describes a translatable SEO string.
+type seoField struct {
+	name      string // e.g. "title", "description", "og:title"
+	label     string // Human-readable description for the AI
+	maxLength int    // Recommended maximum length in characters, 0 if none
+}
+
+// seoTitle is the field for the document <title>.
+var seoTitle = seoField{name: "title", label: "SEO page title shown in search results and browser tabs", maxLength: 60}
+
+// seoMetaFields maps meta names and properties to their fields.
+var seoMetaFields = map[string]seoField{
+	"description":         {label: "SEO meta description shown in search result snippets", maxLength: 160},
+	"keywords":            {label: "SEO meta keywords, a comma-separated list of short keywords"},
+	"og:title":            {label: "Open Graph title shown when the page is shared", maxLength: 60},
+	"og:description":      {label: "Open Graph description shown when the page is shared", maxLength: 200},
+	"og:site_name":        {label: "Open Graph site name shown when the page is shared"},
+	"og:image:alt":        {label: "Open Graph image alt text", maxLength: 420},
+	"twitter:title":       {label: "Twitter card title", maxLength: 70},
+	"twitter:description": {label: "Twitter card description", maxLength: 200},
+	"twitter:image:alt":   {label: "Twitter card image alt text", maxLength: 420},
+}
+
+// WithSEOMetadata translates the document <title> and SEO <meta> tags:
+// name=description and name=keywords, Open Graph (og:title, og:description,
+// og:site_name, og:image:alt) and Twitter card (twitter:title,
+// twitter:description, twitter:image:alt) tags. Non-text properties such as
+// og:url and og:image are left untouched. These become "html_seo" nodes whose
+// context gives the recommended length limit.
+func WithSEOMetadata() HTMLProcessorOption {
+	return func(p *HTMLProcessor) {
+		p.seoMetadata = true
+	}
+}
+
+// seoMeta returns the SEO field of a <meta> tag whose content should be translated.
+func (p *HTMLProcessor) seoMeta(tag string, attrs []html.Attribute) (seoField, bool) {
+	if !p.seoMetadata || !strings.EqualFold(tag, "meta") {
+		return seoField{}, false
+	}
+
+	// Open Graph uses property=, others use name=; accept either
+	for _, attr := range attrs {
+		if attr.Key != "name" && attr.Key != "property" {
+			continue
+		}
+		name := strings.ToLower(strings.TrimSpace(attr.Val))
+		if field, ok := seoMetaFields[name]; ok {
+			field.name = name
+			return field, true
+		}
+	}
+
+	return seoField{}, false
+}
+
+// seoContentAttr returns the index of a SEO <meta> tag's content attribute.
+func (p *HTMLProcessor) seoContentAttr(tag string, attrs []html.Attribute) (int, bool) {
+	if _, ok := p.seoMeta(tag, attrs); !ok {
+		return 0, false
+	}
+	for i, attr := range attrs {
+		if attr.Key == "content" && strings.TrimSpace(attr.Val) != "" {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// isSEOTitle reports whether a text node is the content of the document <title>.
+func (p *HTMLProcessor) isSEOTitle(n *html.Node) bool {
+	parent := n.Parent
+	return p.seoMetadata && parent != nil && parent.Type == html.ElementNode &&
+		parent.Data == "title" && parent.Namespace == ""
+}
+
+// seoTextNode marks node as an SEO string.
+func seoTextNode(node gotlai.TextNode, field seoField) gotlai.TextNode {
+	node.NodeType = "html_seo"
+	node.Context = field.label
+	if field.maxLength > 0 {
+		node.Context += fmt.Sprintf("; keep it under %d characters", field.maxLength)
+		node.Metadata["max_length"] = strconv.Itoa(field.maxLength)
+	}
+	node.Metadata["seo_field"] = field.name
+	return node
+}
@@ -0,0 +1,133 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+const seoHTML = `<html><head>
+<title>Welcome</title>
+<meta name="description" content="Best shoes online">
+<meta name="keywords" content="shoes, boots">
+<meta property="og:title" content="Shoe Shop">
+<meta property="og:url" content="https://example.com/">
+<meta property="og:image" content="https://example.com/a.png">
+<meta name="twitter:description" content="Shoes for everyone">
+<meta name="twitter:card" content="summary">
+</head><body><p>Hello</p><svg><title>Chart</title></svg></body></html>`
+
+func TestHTMLProcessor_Extract_SEOMetadata(t *testing.T) {
+	p := NewHTMLProcessor(WithSEOMetadata())
+
+	_, nodes, err := p.Extract(seoHTML)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	fields := make(map[string]string)
+	for _, n := range nodes {
+		if n.NodeType == "html_seo" {
+			fields[n.Metadata["seo_field"]] = n.Text
+		}
+	}
+
+	expected := map[string]string{
+		"title":               "Welcome",
+		"description":         "Best shoes online",
+		"keywords":            "shoes, boots",
+		"og:title":            "Shoe Shop",
+		"twitter:description": "Shoes for everyone",
+	}
+	if len(fields) != len(expected) {
+		t.Errorf("Expected %d SEO nodes, got %v", len(expected), fields)
+	}
+	for field, text := range expected {
+		if fields[field] != text {
+			t.Errorf("SEO field %q = %q, want %q", field, fields[field], text)
+		}
+	}
+
+	for _, n := range nodes {
+		switch n.Text {
+		case "Welcome":
+			if !strings.Contains(n.Context, "under 60 characters") || n.Metadata["max_length"] != "60" {
+				t.Errorf("Title should carry its length limit, got %q %v", n.Context, n.Metadata)
+			}
+		case "Best shoes online":
+			if !strings.Contains(n.Context, "under 160 characters") {
+				t.Errorf("Description should carry its length limit, got %q", n.Context)
+			}
+		case "Chart":
+			if n.NodeType == "html_seo" {
+				t.Errorf("SVG title should not be an SEO node, got %q", n.NodeType)
+			}
+		}
+	}
+}
+
+func TestHTMLProcessor_Apply_SEOMetadata(t *testing.T) {
+	p := NewHTMLProcessor(WithSEOMetadata(), WithAttributes())
+
+	parsed, nodes, err := p.Extract(seoHTML)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	// WithAttributes also selects meta description; it must be extracted once
+	count := 0
+	for _, n := range nodes {
+		if n.Text == "Best shoes online" {
+			count++
+			if n.NodeType != "html_seo" {
+				t.Errorf("Expected html_seo node, got %q", n.NodeType)
+			}
+		}
+	}
+	if count != 1 {
+		t.Errorf("Expected meta description once, got %d", count)
+	}
+
+	result, err := p.Apply(parsed, nodes, bracketAll(nodes))
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	for _, s := range []string{
+		`<title>[Welcome]</title>`,
+		`content="[Best shoes online]"`,
+		`content="[Shoe Shop]"`,
+		`content="https://example.com/"`,
+		`content="summary"`,
+	} {
+		if !strings.Contains(result, s) {
+			t.Errorf("Expected %q in result, got: %s", s, result)
+		}
+	}
+}
+
+func TestHTMLProcessor_Stream_SEOMetadata(t *testing.T) {
+	p := NewHTMLProcessor(WithSEOMetadata())
+	tr := &fakeStreamTranslator{}
+
+	var out bytes.Buffer
+	if err := p.Stream(context.Background(), strings.NewReader(seoHTML), &out, tr); err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+
+	seo := 0
+	for _, window := range tr.windows {
+		for _, n := range window {
+			if n.NodeType == "html_seo" {
+				seo++
+			}
+		}
+	}
+	if seo != 5 {
+		t.Errorf("Expected 5 SEO nodes while streaming, got %d", seo)
+	}
+	if !strings.Contains(out.String(), `content="[Shoe Shop]"`) || !strings.Contains(out.String(), `<title>[Welcome]</title>`) {
+		t.Errorf("Expected translated SEO metadata, got: %s", out.String())
+	}
+}
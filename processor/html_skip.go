@@ -0,0 +1,83 @@
+package processor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+)
+
+// notranslateClass is the conventional class marking content that must not be translated.
+const notranslateClass = "notranslate"
+
+// WithSkipSelectors leaves elements matching any of the CSS selectors untranslated,
+// as if they had translate="no", so existing templates can opt out without markup
+// changes. Descendants with translate="yes" are still translated. An invalid
+// selector makes Extract and Stream fail. While streaming only the open element
+// path is known, so sibling combinators and structural pseudo-classes such as
+// :first-child do not match.
+func WithSkipSelectors(selectors ...string) HTMLProcessorOption {
+	return func(p *HTMLProcessor) {
+		for _, s := range selectors {
+			sel, err := cascadia.Compile(s)
+			if err != nil {
+				p.selectorErr = fmt.Errorf("invalid skip selector %q: %w", s, err)
+				continue
+			}
+			p.skipSelectors = append(p.skipSelectors, sel)
+		}
+	}
+}
+
+// skipped reports whether an element and its whole subtree are left untouched:
+// ignored tags and elements with a data-no-translate attribute.
+func (p *HTMLProcessor) skipped(n *html.Node) bool {
+	if p.ignoredTags[strings.ToLower(n.Data)] {
+		return true
+	}
+	for _, attr := range n.Attr {
+		if attr.Key == "data-no-translate" {
+			return true
+		}
+	}
+	return false
+}
+
+// translates reports whether content directly inside an element is translated,
+// given whether its parent's content is. Following the HTML translate attribute,
+// translate="no" turns translation off and translate="yes" (or an empty value)
+// turns it back on for the subtree; other values inherit. Without the attribute,
+// the notranslate class and the skip selectors turn translation off.
+func (p *HTMLProcessor) translates(n *html.Node, inherited bool) bool {
+	for _, attr := range n.Attr {
+		if attr.Key != "translate" {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(attr.Val)) {
+		case "no":
+			return false
+		case "", "yes":
+			return true
+		}
+	}
+
+	for _, attr := range n.Attr {
+		if attr.Key != "class" {
+			continue
+		}
+		for _, class := range strings.Fields(attr.Val) {
+			if class == notranslateClass {
+				return false
+			}
+		}
+	}
+
+	for _, sel := range p.skipSelectors {
+		if sel.Match(n) {
+			return false
+		}
+	}
+
+	return inherited
+}
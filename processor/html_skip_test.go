@@ -0,0 +1,117 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ZaguanLabs/gotlai"
+)
+
+const skipHTML = `<div>
+	<p>Translate me</p>
+	<p translate="no">Brand Name <span translate="yes">Tagline</span></p>
+	<p class="lead notranslate">Product <img src="x.png" alt="Logo"></p>
+	<p class="sku">SKU-123</p>
+	<nav id="breadcrumbs"><a href="/">Home page</a></nav>
+	<p translate="yes" class="notranslate">Explicit yes</p>
+</div>`
+
+func TestHTMLProcessor_Extract_TranslateAttribute(t *testing.T) {
+	p := NewHTMLProcessor(WithAttributes(), WithSkipSelectors(".sku", "#breadcrumbs a"))
+
+	_, nodes, err := p.Extract(skipHTML)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	var texts []string
+	for _, n := range nodes {
+		texts = append(texts, n.Text)
+	}
+
+	expected := []string{"Translate me", "Tagline", "Explicit yes"}
+	if strings.Join(texts, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, texts)
+	}
+}
+
+func TestHTMLProcessor_Apply_TranslateAttribute(t *testing.T) {
+	p := NewHTMLProcessor(WithAttributes(), WithSkipSelectors(".sku", "#breadcrumbs a"))
+
+	parsed, nodes, err := p.Extract(skipHTML)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	// Translations for skipped text must not leak into it, even when hashes match
+	translations := make(map[string]string)
+	for _, text := range []string{"Translate me", "Tagline", "Brand Name", "Product", "Logo", "SKU-123", "Home page"} {
+		translations[gotlai.HashText(text)] = "[" + text + "]"
+	}
+
+	result, err := p.Apply(parsed, nodes, translations)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	for _, s := range []string{"[Translate me]", "[Tagline]"} {
+		if !strings.Contains(result, s) {
+			t.Errorf("Expected %q in result, got: %s", s, result)
+		}
+	}
+	for _, s := range []string{"Brand Name", "Product", `alt="Logo"`, "SKU-123", "Home page"} {
+		if !strings.Contains(result, s) {
+			t.Errorf("Skipped content %q should be unchanged, got: %s", s, result)
+		}
+	}
+}
+
+func TestHTMLProcessor_TranslateAttributeInlineSegments(t *testing.T) {
+	p := NewHTMLProcessor(WithInlineSegments())
+
+	_, nodes, err := p.Extract(`<p>Welcome to <span class="notranslate">Acme</span> online</p>`)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if len(nodes) != 1 || nodes[0].Text != "Welcome to <x1/> online" {
+		t.Errorf("Untranslated inline element should be a self-closing placeholder, got %v", nodes)
+	}
+}
+
+func TestHTMLProcessor_Stream_TranslateAttribute(t *testing.T) {
+	p := NewHTMLProcessor(WithAttributes(), WithSkipSelectors(".sku", "#breadcrumbs a"))
+	tr := &fakeStreamTranslator{}
+
+	var out bytes.Buffer
+	if err := p.Stream(context.Background(), strings.NewReader(skipHTML), &out, tr); err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+	result := out.String()
+
+	for _, s := range []string{"[Translate me]", "[Tagline]", "[Explicit yes]"} {
+		if !strings.Contains(result, s) {
+			t.Errorf("Expected %q in result, got: %s", s, result)
+		}
+	}
+	for _, s := range []string{"[Brand Name]", "[Product]", "[Logo]", "[SKU-123]", "[Home page]"} {
+		if strings.Contains(result, s) {
+			t.Errorf("Skipped content %q should not be translated, got: %s", s, result)
+		}
+	}
+}
+
+func TestHTMLProcessor_InvalidSkipSelector(t *testing.T) {
+	p := NewHTMLProcessor(WithSkipSelectors("p[", ".ok"))
+
+	if _, _, err := p.Extract("<p>Hello</p>"); err == nil {
+		t.Error("Extract should fail with an invalid selector")
+	}
+
+	var out bytes.Buffer
+	if err := p.Stream(context.Background(), strings.NewReader("<p>Hello</p>"), &out, &fakeStreamTranslator{}); err == nil {
+		t.Error("Stream should fail with an invalid selector")
+	}
+}
@@ -0,0 +1,513 @@
+package processor
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// WithMinimalDiff makes Apply splice translations into the original document
+// instead of re-serializing it, so entities, attribute quoting and order,
+// void-element syntax and whitespace outside translated text are kept exactly.
+// Byte offsets of text nodes and translatable attributes are recorded during
+// Extract. If the parser restructured the markup too much to locate a change
+// (e.g. text moved out of a table), the document is re-serialized instead.
+// Fragments (WithFragment) always use this mode.
+func WithMinimalDiff() HTMLProcessorOption {
+	return func(p *HTMLProcessor) {
+		p.minimalDiff = true
+	}
+}
+
+// rawTextElements contains elements whose text is written without escaping.
+var rawTextElements = map[string]bool{
+	"script": true,
+	"style":  true,
+}
+
+// sourceToken is a token of the original source with its byte range.
+type sourceToken struct {
+	tokenType  html.TokenType
+	name       string           // Tag name for tag tokens
+	text       string           // Unescaped text for text tokens
+	attrs      []html.Attribute // Attributes for start tags
+	start, end int
+}
+
+// sourceSpan is a byte range of the original source.
+type sourceSpan struct {
+	start, end int
+}
+
+// sourceMap ties parsed nodes to their bytes in the original source, so Apply
+// can splice translations into it instead of re-serializing the document.
+type sourceMap struct {
+	src        string
+	tokens     []sourceToken
+	textTokens map[*html.Node]int        // Non-whitespace text node to its token index
+	textSpans  map[*html.Node]sourceSpan // Non-whitespace text node to its raw text
+	tags       map[*html.Node]int        // Element with translatable attributes to its start tag token index
+}
+
+// newSourceMap tokenizes src and aligns its tokens with the parsed tree under root.
+// contextTag is the fragment context element, or empty for full documents. It
+// returns nil when the parser restructured the source in a way that cannot be
+// aligned (e.g. merged text or foster-parented table content).
+func (p *HTMLProcessor) newSourceMap(src string, root *html.Node, contextTag string) *sourceMap {
+	tokens, ok := tokenizeSource(src, contextTag)
+	if !ok {
+		return nil
+	}
+
+	var texts, tags []int
+	for i, tok := range tokens {
+		switch tok.tokenType {
+		case html.TextToken:
+			if strings.TrimSpace(tok.text) != "" {
+				texts = append(texts, i)
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if len(p.translatableAttrs(tok.name, tok.attrs)) > 0 {
+				tags = append(tags, i)
+			}
+		}
+	}
+
+	m := &sourceMap{
+		src:        src,
+		tokens:     tokens,
+		textTokens: make(map[*html.Node]int),
+		textSpans:  make(map[*html.Node]sourceSpan),
+		tags:       make(map[*html.Node]int),
+	}
+
+	// Text nodes and elements with translatable attributes must match tokens one to one
+	nextText, nextTag := 0, 0
+	aligned := true
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			if strings.TrimSpace(n.Data) == "" {
+				break
+			}
+			if nextText >= len(texts) {
+				aligned = false
+				return
+			}
+			i := texts[nextText]
+			span, ok := textSpan(src, tokens[i], n.Data)
+			if !ok {
+				aligned = false
+				return
+			}
+			m.textTokens[n] = i
+			m.textSpans[n] = span
+			nextText++
+		case html.ElementNode:
+			if len(p.translatableAttrs(n.Data, n.Attr)) == 0 {
+				break
+			}
+			if nextTag >= len(tags) {
+				aligned = false
+				return
+			}
+			tok := tokens[tags[nextTag]]
+			if tok.name != n.Data || !equalAttrs(tok.attrs, n.Attr) {
+				aligned = false
+				return
+			}
+			m.tags[n] = tags[nextTag]
+			nextTag++
+		}
+
+		for c := n.FirstChild; c != nil && aligned; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(root)
+
+	if !aligned || nextText != len(texts) || nextTag != len(tags) {
+		return nil
+	}
+	return m
+}
+
+// tokenizeSource splits src into tokens covering every byte.
+func tokenizeSource(src, contextTag string) ([]sourceToken, bool) {
+	var z *html.Tokenizer
+	if contextTag != "" {
+		z = html.NewTokenizerFragment(strings.NewReader(src), contextTag)
+	} else {
+		z = html.NewTokenizer(strings.NewReader(src))
+	}
+
+	var tokens []sourceToken
+	offset := 0
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if z.Err() != io.EOF {
+				return nil, false
+			}
+			break
+		}
+
+		tok := sourceToken{tokenType: tt, start: offset}
+		offset += len(z.Raw())
+		tok.end = offset
+
+		switch tt {
+		case html.TextToken:
+			tok.text = string(z.Text())
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			tok.name = string(name)
+			for hasAttr {
+				var key, val []byte
+				key, val, hasAttr = z.TagAttr()
+				tok.attrs = append(tok.attrs, html.Attribute{Key: string(key), Val: string(val)})
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			tok.name = string(name)
+		}
+
+		tokens = append(tokens, tok)
+	}
+
+	return tokens, offset == len(src)
+}
+
+// textSpan returns the raw bytes of tok that hold the text node data.
+func textSpan(src string, tok sourceToken, data string) (sourceSpan, bool) {
+	span := sourceSpan{tok.start, tok.end}
+	if tok.text == data {
+		return span, true
+	}
+
+	// The parser drops a newline right after <pre>, <listing> and <textarea>
+	if tok.text == "\n"+data {
+		raw := src[tok.start:tok.end]
+		switch {
+		case strings.HasPrefix(raw, "\r\n"):
+			span.start += 2
+			return span, true
+		case strings.HasPrefix(raw, "\n"), strings.HasPrefix(raw, "\r"):
+			span.start++
+			return span, true
+		}
+	}
+
+	return sourceSpan{}, false
+}
+
+// equalAttrs reports whether two attribute lists have the same keys and values in order.
+func equalAttrs(a, b []html.Attribute) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Key != b[i].Key || a[i].Val != b[i].Val {
+			return false
+		}
+	}
+	return true
+}
+
+// blockSpan returns the raw content of a segment block, between its start and
+// end tags. children are the block's original children, since Apply may have
+// replaced them. isRoot marks the fragment context element, whose content is
+// the whole source.
+func (m *sourceMap) blockSpan(block *html.Node, children []*html.Node, isRoot bool) (sourceSpan, bool) {
+	// Find the first and last translatable text tokens inside the block
+	first, last := -1, -1
+	var text strings.Builder
+	var collect func(*html.Node)
+	collect = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			text.WriteString(n.Data)
+			if i, ok := m.textTokens[n]; ok {
+				if first < 0 {
+					first = i
+				}
+				last = i
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			collect(c)
+		}
+	}
+	for _, c := range children {
+		collect(c)
+	}
+	if first < 0 {
+		return sourceSpan{}, false
+	}
+
+	// Walk back to the block's start tag
+	startTag := -1
+	if !isRoot {
+		for k := first - 1; k >= 0 && startTag < 0; k-- {
+			tok := m.tokens[k]
+			switch tok.tokenType {
+			case html.StartTagToken:
+				if tok.name == block.Data && equalAttrs(tok.attrs, block.Attr) {
+					startTag = k
+				}
+			case html.SelfClosingTagToken, html.EndTagToken:
+			case html.TextToken:
+				if strings.TrimSpace(tok.text) != "" {
+					return sourceSpan{}, false
+				}
+			default:
+				return sourceSpan{}, false
+			}
+		}
+		if startTag < 0 {
+			return sourceSpan{}, false
+		}
+	}
+
+	// Walk forward to the block's end, tracking elements opened inside it
+	var open []string
+	end := len(m.tokens)
+scan:
+	for k := startTag + 1; k < len(m.tokens); k++ {
+		tok := m.tokens[k]
+		after := k > last
+		switch tok.tokenType {
+		case html.StartTagToken:
+			if after && !inlineElements[tok.name] {
+				end = k
+				break scan
+			}
+			if !voidElements[tok.name] {
+				open = append(open, tok.name)
+			}
+		case html.SelfClosingTagToken:
+			if after && !inlineElements[tok.name] {
+				end = k
+				break scan
+			}
+		case html.EndTagToken:
+			i := len(open) - 1
+			for i >= 0 && open[i] != tok.name {
+				i--
+			}
+			if i >= 0 {
+				open = open[:i]
+			} else if after || tok.name == block.Data {
+				end = k
+				break scan
+			}
+		case html.TextToken:
+			if after && strings.TrimSpace(tok.text) != "" {
+				end = k
+				break scan
+			}
+		default:
+			if after {
+				end = k
+				break scan
+			}
+		}
+	}
+
+	span := sourceSpan{start: 0, end: len(m.src)}
+	if startTag >= 0 {
+		span.start = m.tokens[startTag].end
+	}
+	if end < len(m.tokens) {
+		span.end = m.tokens[end].start
+	}
+
+	// The span must hold exactly the block's text
+	var raw strings.Builder
+	for k := startTag + 1; k < end; k++ {
+		if m.tokens[k].tokenType == html.TextToken {
+			raw.WriteString(m.tokens[k].text)
+		}
+	}
+	if raw.String() != text.String() {
+		return sourceSpan{}, false
+	}
+
+	return span, true
+}
+
+// sourceEdit replaces a byte range of the original source.
+type sourceEdit struct {
+	sourceSpan
+	text string
+}
+
+// splice writes the changes made by Apply into the original source. Changed
+// text nodes and attribute values replace their raw bytes; rebuilt segment
+// blocks replace their content. It reports false when a change cannot be
+// located, so the caller can fall back to serializing the tree.
+func (m *sourceMap) splice(root *html.Node, texts map[*html.Node]string, attrs map[*html.Node][]html.Attribute, blocks map[*html.Node][]*html.Node) (string, bool) {
+	var edits []sourceEdit
+
+	for n := range texts {
+		span, ok := m.textSpans[n]
+		if !ok {
+			return "", false
+		}
+		text := n.Data
+		if n.Parent == nil || !rawTextElements[n.Parent.Data] {
+			text = escapeSegmentText(text)
+		}
+		edits = append(edits, sourceEdit{span, text})
+	}
+
+	for el, originals := range attrs {
+		if insideAny(el, blocks) {
+			continue // Rendered with its rebuilt block
+		}
+		i, ok := m.tags[el]
+		if !ok {
+			return "", false
+		}
+		tok := m.tokens[i]
+		values := attrValueSpans(m.src[tok.start:tok.end])
+		if len(values) != len(el.Attr) {
+			return "", false
+		}
+		for j, attr := range el.Attr {
+			if attr.Val == originals[j].Val {
+				continue
+			}
+			span := values[j]
+			raw := m.src[tok.start+span.start : tok.start+span.end]
+			span.start += tok.start
+			span.end += tok.start
+			edits = append(edits, sourceEdit{span, quoteAttrValue(raw, attr.Val)})
+		}
+	}
+
+	for block, children := range blocks {
+		span, ok := m.blockSpan(block, children, block == root)
+		if !ok {
+			return "", false
+		}
+		var buf bytes.Buffer
+		for c := block.FirstChild; c != nil; c = c.NextSibling {
+			if err := html.Render(&buf, c); err != nil {
+				return "", false
+			}
+		}
+		edits = append(edits, sourceEdit{span, buf.String()})
+	}
+
+	sort.Slice(edits, func(i, j int) bool {
+		return edits[i].start < edits[j].start
+	})
+
+	var b strings.Builder
+	pos := 0
+	for _, e := range edits {
+		if e.start < pos {
+			return "", false // Overlapping edits
+		}
+		b.WriteString(m.src[pos:e.start])
+		b.WriteString(e.text)
+		pos = e.end
+	}
+	b.WriteString(m.src[pos:])
+
+	return b.String(), true
+}
+
+// insideAny reports whether n is a descendant of one of the blocks.
+func insideAny(n *html.Node, blocks map[*html.Node][]*html.Node) bool {
+	for a := n.Parent; a != nil; a = a.Parent {
+		if _, ok := blocks[a]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// attrValueSpans returns, for each attribute of a raw start tag, the range of
+// its value including quotes (empty at the end of the name when it has none).
+func attrValueSpans(raw string) []sourceSpan {
+	isSpace := func(c byte) bool {
+		return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+	}
+
+	// Skip "<" and the tag name
+	i := 1
+	for i < len(raw) && !isSpace(raw[i]) && raw[i] != '/' && raw[i] != '>' {
+		i++
+	}
+
+	var spans []sourceSpan
+	for i < len(raw) {
+		for i < len(raw) && (isSpace(raw[i]) || raw[i] == '/') {
+			i++
+		}
+		if i >= len(raw) || raw[i] == '>' {
+			break
+		}
+
+		// Attribute name; a leading "=" belongs to the name
+		i++
+		for i < len(raw) && !isSpace(raw[i]) && raw[i] != '/' && raw[i] != '>' && raw[i] != '=' {
+			i++
+		}
+		nameEnd := i
+
+		j := i
+		for j < len(raw) && isSpace(raw[j]) {
+			j++
+		}
+		if j >= len(raw) || raw[j] != '=' {
+			spans = append(spans, sourceSpan{nameEnd, nameEnd})
+			continue
+		}
+		j++
+		for j < len(raw) && isSpace(raw[j]) {
+			j++
+		}
+
+		start := j
+		if j < len(raw) && (raw[j] == '"' || raw[j] == '\'') {
+			quote := raw[j]
+			j++
+			for j < len(raw) && raw[j] != quote {
+				j++
+			}
+			if j < len(raw) {
+				j++
+			}
+		} else {
+			for j < len(raw) && !isSpace(raw[j]) && raw[j] != '>' {
+				j++
+			}
+		}
+		spans = append(spans, sourceSpan{start, j})
+		i = j
+	}
+
+	return spans
+}
+
+// quoteAttrValue renders an attribute value, keeping the original quote style.
+// Unquoted values gain double quotes.
+func quoteAttrValue(original, val string) string {
+	quote := `"`
+	if strings.HasPrefix(original, "'") {
+		quote = "'"
+	}
+	val = strings.ReplaceAll(val, "&", "&amp;")
+	if quote == "'" {
+		val = strings.ReplaceAll(val, "'", "&#39;")
+	} else {
+		val = strings.ReplaceAll(val, `"`, "&#34;")
+	}
+	return quote + val + quote
+}
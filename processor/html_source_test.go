@@ -0,0 +1,117 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ZaguanLabs/gotlai"
+)
+
+const minimalDiffHTML = `<!doctype html>
+<html lang=en>
+<head>
+  <meta charset=utf-8>
+  <title>Hello</title>
+</head>
+<body class='home'   data-x="1">
+  <!-- header -->
+  <h1>Hello&nbsp;World &copy; 2024</h1>
+  <p>Line<br>break <img src="a.png" alt='Logo' /></p>
+  <pre>
+Keep   spacing</pre>
+  <textarea>
+Notes</textarea>
+  <listing>
+Legacy</listing>
+</body>
+</html>
+`
+
+func TestHTMLProcessor_MinimalDiff(t *testing.T) {
+	p := NewHTMLProcessor(WithMinimalDiff(), WithAttributes())
+
+	parsed, nodes, err := p.Extract(minimalDiffHTML)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	result, err := p.Apply(parsed, nodes, map[string]string{})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if result != minimalDiffHTML {
+		t.Errorf("Untranslated document should round-trip exactly, got:\n%s", result)
+	}
+
+	result, err = p.Apply(parsed, nodes, bracketAll(nodes))
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	expected := strings.NewReplacer(
+		"<title>Hello</title>", "<title>[Hello]</title>",
+		"<h1>Hello&nbsp;World &copy; 2024</h1>", "<h1>[Hello World © 2024]</h1>",
+		"<p>Line<br>break <img src=\"a.png\" alt='Logo' /></p>", "<p>[Line]<br>[break] <img src=\"a.png\" alt='[Logo]' /></p>",
+		"<listing>\nLegacy</listing>", "<listing>\n[Legacy]</listing>",
+	).Replace(minimalDiffHTML)
+	if result != expected {
+		t.Errorf("Apply = %q, want %q", result, expected)
+	}
+}
+
+func TestHTMLProcessor_MinimalDiffSegments(t *testing.T) {
+	p := NewHTMLProcessor(WithMinimalDiff(), WithInlineSegments())
+
+	input := "<html><body>\n<p class='a'>Click <a href='/x'>here</a>.</p>\n<p>Done</p>\n</body></html>"
+	parsed, nodes, err := p.Extract(input)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	result, err := p.Apply(parsed, nodes, map[string]string{
+		nodes[0].Hash:           "<x1>Ici</x1>.",
+		gotlai.HashText("Done"): "Fait",
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	expected := "<html><body>\n<p class='a'><a href=\"/x\">Ici</a>.</p>\n<p>Fait</p>\n</body></html>"
+	if result != expected {
+		t.Errorf("Apply = %q, want %q", result, expected)
+	}
+}
+
+func TestHTMLProcessor_MinimalDiffFallback(t *testing.T) {
+	p := NewHTMLProcessor(WithMinimalDiff())
+
+	// Merged text nodes cannot be located in the source
+	input := "<p>Hello</span>World</p>"
+	parsed, nodes, err := p.Extract(input)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	result, err := p.Apply(parsed, nodes, bracketAll(nodes))
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if !strings.Contains(result, "<p>[HelloWorld]</p>") {
+		t.Errorf("Expected re-serialized translation, got %q", result)
+	}
+}
+
+func TestAttrValueSpans(t *testing.T) {
+	raw := `<input type=submit  value = "Go" disabled data-x='a b'/>`
+	spans := attrValueSpans(raw)
+
+	var values []string
+	for _, s := range spans {
+		values = append(values, raw[s.start:s.end])
+	}
+
+	expected := []string{"submit", `"Go"`, "", "'a b'"}
+	if strings.Join(values, "|") != strings.Join(expected, "|") {
+		t.Errorf("attrValueSpans = %q, want %q", values, expected)
+	}
+}
@@ -0,0 +1,530 @@
+package processor
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ZaguanLabs/gotlai"
+	"golang.org/x/net/html"
+)
+
+// defaultStreamWindow is the number of unique text nodes translated per window.
+const defaultStreamWindow = 50
+
+// streamKnownWindows is how many windows' worth of translations are kept to
+// reuse for repeated text; older translations are dropped, so memory use does
+// not grow with the input. Text seen again after that is translated again,
+// usually from the translator's cache.
+const streamKnownWindows = 20
+
+// WithStreamWindow sets how many unique text nodes are translated per window
+// when streaming (default: 50). Smaller windows lower memory use and latency
+// to first output; larger windows make fewer provider requests.
+func WithStreamWindow(n int) HTMLProcessorOption {
+	return func(p *HTMLProcessor) {
+		p.streamWindow = n
+	}
+}
+
+// voidElements contains HTML elements that never have an end tag.
+var voidElements = map[string]bool{
+	"area":   true,
+	"base":   true,
+	"br":     true,
+	"col":    true,
+	"embed":  true,
+	"hr":     true,
+	"img":    true,
+	"input":  true,
+	"link":   true,
+	"meta":   true,
+	"param":  true,
+	"source": true,
+	"track":  true,
+	"wbr":    true,
+}
+
+// streamElement is an open element on the tokenizer's element stack.
+type streamElement struct {
+	tag       string
+	class     string
+	id        string
+	role      string
+	translate bool       // Whether content directly inside is translated
+	node      *html.Node // Detached node linked to its open ancestors, for selector matching
+}
+
+// streamToken is a buffered token awaiting translation of its window.
+type streamToken struct {
+	raw  []byte // Original bytes, written unchanged when not translated
+	text string // Unescaped text for translatable text tokens
+	hash string // Non-empty for translatable text tokens
+
+	// Start tags with translatable attributes
+	tagType    html.TokenType
+	tag        string
+	attrs      []html.Attribute
+	attrHashes map[int]string // Attribute index to hash
+
+	jsonLD []jsonLDValue // Translatable values of a JSON-LD script body
+}
+
+// htmlStream holds the state of a single streaming run.
+type htmlStream struct {
+	p          *HTMLProcessor
+	ctx        context.Context
+	translator gotlai.StreamTranslator
+	out        *bufio.Writer
+
+	pending    []streamToken
+	window     []gotlai.TextNode
+	inWindow   map[string]bool
+	known      map[string]string // Translations from recent windows
+	knownOrder []string          // Hashes of known, oldest first
+	knownLimit int
+
+	stack     []streamElement
+	skipTag   string
+	skipDepth int
+	nodeCount int
+	inJSONLD  bool
+}
+
+// Stream translates HTML read from r and writes it to w window by window, using
+// the html tokenizer instead of building a document tree. Bytes outside
+// translated text are written unchanged, and the <html> tag gets lang and dir
+// attributes for the target language.
+func (p *HTMLProcessor) Stream(ctx context.Context, r io.Reader, w io.Writer, translator gotlai.StreamTranslator) error {
+	if p.selectorErr != nil {
+		return &gotlai.ProcessorError{
+			Message:     "invalid processor configuration",
+			Cause:       p.selectorErr,
+			ContentType: "html",
+		}
+	}
+
+	return p.newStream(ctx, w, translator).run(r)
+}
+
+// newStream creates the state of a streaming run writing to w.
+func (p *HTMLProcessor) newStream(ctx context.Context, w io.Writer, translator gotlai.StreamTranslator) *htmlStream {
+	window := p.streamWindow
+	if window <= 0 {
+		window = defaultStreamWindow
+	}
+	return &htmlStream{
+		p:          p,
+		ctx:        ctx,
+		translator: translator,
+		out:        bufio.NewWriter(w),
+		inWindow:   make(map[string]bool),
+		known:      make(map[string]string),
+		knownLimit: window * streamKnownWindows,
+	}
+}
+
+// run tokenizes r and writes its translation window by window.
+func (s *htmlStream) run(r io.Reader) error {
+	z := html.NewTokenizer(r)
+	if s.p.fragmentContext != "" {
+		z = html.NewTokenizerFragment(r, s.p.fragmentContext)
+	}
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if z.Err() == io.EOF {
+				break
+			}
+			return &gotlai.ProcessorError{
+				Message:     "failed to tokenize HTML",
+				Cause:       z.Err(),
+				ContentType: "html",
+			}
+		}
+
+		// Copy raw bytes first: the tokenizer reuses its buffer and TagName lower-cases in place
+		tok := streamToken{raw: append([]byte(nil), z.Raw()...)}
+
+		switch tt {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok = s.startTag(z, tt, tok.raw)
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			s.endTag(string(name))
+		case html.TextToken:
+			tok = s.text(string(z.Text()), tok.raw)
+		}
+
+		if err := s.emit(tok); err != nil {
+			return err
+		}
+	}
+
+	if err := s.flush(); err != nil {
+		return err
+	}
+	return s.out.Flush()
+}
+
+// startTag updates the element stack and skip state, and extracts translatable attributes.
+func (s *htmlStream) startTag(z *html.Tokenizer, tt html.TokenType, raw []byte) streamToken {
+	name, hasAttr := z.TagName()
+	tag := string(name)
+
+	elem := streamElement{tag: tag}
+	var attrs []html.Attribute
+	for hasAttr {
+		var key, val []byte
+		key, val, hasAttr = z.TagAttr()
+		attr := html.Attribute{Key: string(key), Val: string(val)}
+		attrs = append(attrs, attr)
+
+		switch attr.Key {
+		case "class":
+			elem.class = attr.Val
+		case "id":
+			elem.id = attr.Val
+		case "role":
+			elem.role = attr.Val
+		}
+	}
+
+	// Link a detached node to the open ancestors so skip selectors can match
+	elem.node = &html.Node{Type: html.ElementNode, Data: tag, Attr: attrs}
+	if len(s.stack) > 0 {
+		elem.node.Parent = s.stack[len(s.stack)-1].node
+	}
+	elem.translate = s.p.translates(elem.node, s.translating())
+
+	tok := streamToken{raw: raw, tagType: tt, tag: tag}
+
+	if tag == "html" {
+		attrs = htmlLangAttrs(attrs, s.translator.TargetLang())
+		tok.raw = []byte(html.Token{Type: tt, Data: tag, Attr: attrs}.String())
+	}
+
+	skipped := s.skipDepth > 0 || s.p.skipped(elem.node)
+	if !skipped && elem.translate {
+		for _, i := range s.p.translatableAttrs(tag, attrs) {
+			trimmed := strings.TrimSpace(attrs[i].Val)
+			hash := s.p.hashAttr(tag, attrs[i].Key, trimmed)
+			if tok.attrHashes == nil {
+				tok.attrHashes = make(map[int]string)
+				tok.attrs = attrs
+			}
+			tok.attrHashes[i] = hash
+			s.addNode(s.p.attrTextNode(s.nextID(), tag, attrs, i, trimmed, hash))
+		}
+	}
+
+	if tag == "script" && len(s.p.jsonLDKeys) > 0 && s.skipDepth == 0 && elem.translate && isJSONLD(attrs) {
+		s.inJSONLD = tt == html.StartTagToken
+	}
+
+	if tt == html.SelfClosingTagToken || voidElements[tag] {
+		return tok
+	}
+
+	s.stack = append(s.stack, elem)
+
+	if s.skipDepth > 0 {
+		if tag == s.skipTag {
+			s.skipDepth++
+		}
+	} else if skipped {
+		s.skipTag = tag
+		s.skipDepth = 1
+	}
+
+	return tok
+}
+
+// endTag updates the element stack and skip state.
+func (s *htmlStream) endTag(tag string) {
+	if tag == "script" {
+		s.inJSONLD = false
+	}
+	if s.skipDepth > 0 && tag == s.skipTag {
+		s.skipDepth--
+	}
+
+	// Pop up to and including the matching element, tolerating misnested markup
+	for i := len(s.stack) - 1; i >= 0; i-- {
+		if s.stack[i].tag == tag {
+			s.stack = s.stack[:i]
+			break
+		}
+	}
+}
+
+// text turns a text token into a translatable token when it has content.
+func (s *htmlStream) text(text string, raw []byte) streamToken {
+	tok := streamToken{raw: raw}
+
+	if s.inJSONLD {
+		// Script bodies are raw text, so the raw bytes are the JSON
+		values, err := s.p.jsonLDValues(string(raw))
+		if err == nil && len(values) > 0 {
+			tok.jsonLD = values
+			for _, v := range values {
+				s.addNode(s.p.jsonLDTextNode(s.nextID(), v))
+			}
+		}
+		return tok
+	}
+
+	trimmed := strings.TrimSpace(text)
+	svgElement, inSVG := s.svgElement()
+	if trimmed == "" || s.skipDepth > 0 || !s.translating() || (inSVG && !svgTextElements[svgElement]) {
+		return tok
+	}
+
+	hash := gotlai.HashText(trimmed)
+	if s.p.contextKeys {
+		hash = gotlai.HashTextWithContext(trimmed, s.contextKey())
+	}
+	tok.text = text
+	tok.hash = hash
+
+	node := gotlai.TextNode{
+		ID:       s.nextID(),
+		Text:     trimmed,
+		Hash:     hash,
+		NodeType: "html_text",
+		Context:  s.context(),
+		Metadata: map[string]string{},
+	}
+	if len(s.stack) > 0 {
+		node.Metadata["parent_tag"] = s.stack[len(s.stack)-1].tag
+	}
+	if s.p.contextKeys {
+		node.Metadata["context_key"] = s.contextKey()
+	}
+	if s.inSEOTitle() {
+		node = seoTextNode(node, seoTitle)
+	}
+	if inSVG {
+		node = svgTextNode(node, svgElement)
+	}
+	s.addNode(node)
+
+	return tok
+}
+
+// translating reports whether text in the innermost open element is translated.
+func (s *htmlStream) translating() bool {
+	if len(s.stack) == 0 {
+		return true
+	}
+	return s.stack[len(s.stack)-1].translate
+}
+
+// inSEOTitle reports whether text is the content of the document <title>.
+func (s *htmlStream) inSEOTitle() bool {
+	if !s.p.seoMetadata || len(s.stack) == 0 || s.stack[len(s.stack)-1].tag != "title" {
+		return false
+	}
+	for _, elem := range s.stack {
+		if elem.tag == "svg" {
+			return false
+		}
+	}
+	return true
+}
+
+// svgElement returns the innermost open element when it is inside inline SVG.
+func (s *htmlStream) svgElement() (string, bool) {
+	inSVG := false
+	for _, elem := range s.stack {
+		switch elem.tag {
+		case "svg":
+			inSVG = true
+		case "foreignobject":
+			inSVG = false
+		}
+	}
+	if !inSVG {
+		return "", false
+	}
+	return s.stack[len(s.stack)-1].tag, true
+}
+
+// addNode adds a node to the current window unless it is already known or pending.
+func (s *htmlStream) addNode(node gotlai.TextNode) {
+	if _, ok := s.known[node.Hash]; ok || s.inWindow[node.Hash] {
+		return
+	}
+
+	s.nodeCount++
+	s.window = append(s.window, node)
+	s.inWindow[node.Hash] = true
+}
+
+// nextID returns the ID for the next extracted node.
+func (s *htmlStream) nextID() string {
+	return fmt.Sprintf("node-%d", s.nodeCount)
+}
+
+// emit writes a token directly when nothing is pending, or buffers it until
+// its window is translated.
+func (s *htmlStream) emit(tok streamToken) error {
+	if len(s.window) == 0 {
+		return s.write(tok)
+	}
+
+	s.pending = append(s.pending, tok)
+
+	window := s.p.streamWindow
+	if window <= 0 {
+		window = defaultStreamWindow
+	}
+	if len(s.window) >= window {
+		return s.flush()
+	}
+	return nil
+}
+
+// flush translates the current window and writes its buffered tokens.
+func (s *htmlStream) flush() error {
+	if len(s.window) > 0 {
+		translations, err := s.translator.Translate(s.ctx, s.window)
+		if err != nil {
+			return err
+		}
+		for hash, translated := range translations {
+			if _, ok := s.known[hash]; !ok {
+				s.knownOrder = append(s.knownOrder, hash)
+			}
+			s.known[hash] = translated
+		}
+	}
+
+	for _, tok := range s.pending {
+		if err := s.write(tok); err != nil {
+			return err
+		}
+	}
+
+	s.pending = s.pending[:0]
+	s.window = s.window[:0]
+	s.inWindow = make(map[string]bool)
+
+	// Nothing is pending, so the oldest translations can be dropped
+	if excess := len(s.knownOrder) - s.knownLimit; excess > 0 {
+		for _, hash := range s.knownOrder[:excess] {
+			delete(s.known, hash)
+		}
+		s.knownOrder = s.knownOrder[excess:]
+	}
+
+	return s.out.Flush()
+}
+
+// write writes a token, substituting its translation when known.
+func (s *htmlStream) write(tok streamToken) error {
+	if tok.jsonLD != nil {
+		_, err := s.out.WriteString(s.p.rewriteJSONLD(string(tok.raw), tok.jsonLD, s.known))
+		return err
+	}
+
+	if len(tok.attrHashes) > 0 {
+		attrs := append([]html.Attribute(nil), tok.attrs...)
+		translated := false
+		for i, hash := range tok.attrHashes {
+			if val, ok := s.known[hash]; ok {
+				attrs[i].Val = preserveWhitespace(attrs[i].Val, val)
+				translated = true
+			}
+		}
+		if translated {
+			_, err := s.out.WriteString(html.Token{Type: tok.tagType, Data: tok.tag, Attr: attrs}.String())
+			return err
+		}
+	}
+
+	if tok.hash != "" {
+		if translated, ok := s.known[tok.hash]; ok {
+			_, err := s.out.WriteString(html.EscapeString(preserveWhitespace(tok.text, translated)))
+			return err
+		}
+	}
+	_, err := s.out.Write(tok.raw)
+	return err
+}
+
+// context builds a disambiguation context from the open element stack.
+func (s *htmlStream) context() string {
+	if len(s.stack) == 0 {
+		return ""
+	}
+
+	var parts []string
+
+	parent := s.stack[len(s.stack)-1]
+	if parent.class != "" {
+		parts = append(parts, fmt.Sprintf("in <%s class=\"%s\">", parent.tag, parent.class))
+	} else if parent.id != "" {
+		parts = append(parts, fmt.Sprintf("in <%s id=\"%s\">", parent.tag, parent.id))
+	} else {
+		parts = append(parts, fmt.Sprintf("in <%s>", parent.tag))
+	}
+
+	// Get ancestor path (up to 3 levels)
+	var ancestors []string
+	for i := len(s.stack) - 2; i >= 0 && len(s.stack)-2-i < 3; i-- {
+		name := s.stack[i].tag
+		if name != "html" && name != "body" {
+			ancestors = append([]string{name}, ancestors...)
+		}
+	}
+	if len(ancestors) > 0 {
+		parts = append(parts, fmt.Sprintf("inside: %s", strings.Join(ancestors, " > ")))
+	}
+
+	return strings.Join(parts, " | ")
+}
+
+// contextKey mirrors contextKey for the open element stack.
+func (s *htmlStream) contextKey() string {
+	if len(s.stack) == 0 {
+		return ""
+	}
+
+	key := s.stack[len(s.stack)-1].tag
+	for i := len(s.stack) - 1; i >= 0; i-- {
+		if role := strings.TrimSpace(s.stack[i].role); role != "" {
+			return key + "|" + strings.ToLower(role)
+		}
+	}
+	return key
+}
+
+// htmlLangAttrs returns attrs with lang and dir set for lang.
+func htmlLangAttrs(attrs []html.Attribute, lang string) []html.Attribute {
+	values := map[string]string{
+		"lang": gotlai.ToHTMLLang(lang),
+		"dir":  gotlai.GetDirection(lang),
+	}
+
+	var out []html.Attribute
+	for _, attr := range attrs {
+		if val, ok := values[attr.Key]; ok {
+			attr.Val = val
+			delete(values, attr.Key)
+		}
+		out = append(out, attr)
+	}
+	for _, key := range []string{"lang", "dir"} {
+		if val, ok := values[key]; ok {
+			out = append(out, html.Attribute{Key: key, Val: val})
+		}
+	}
+
+	return out
+}
+
+// Verify HTMLProcessor implements StreamProcessor
+var _ gotlai.StreamProcessor = (*HTMLProcessor)(nil)
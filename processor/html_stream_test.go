@@ -0,0 +1,170 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ZaguanLabs/gotlai"
+)
+
+// fakeStreamTranslator brackets text and records each window it receives.
+type fakeStreamTranslator struct {
+	windows [][]gotlai.TextNode
+	err     error
+}
+
+func (f *fakeStreamTranslator) Translate(ctx context.Context, nodes []gotlai.TextNode) (map[string]string, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.windows = append(f.windows, append([]gotlai.TextNode(nil), nodes...))
+	translations := make(map[string]string)
+	for _, n := range nodes {
+		translations[n.Hash] = "[" + n.Text + "]"
+	}
+	return translations, nil
+}
+
+func (f *fakeStreamTranslator) TargetLang() string {
+	return "ar_SA"
+}
+
+func TestHTMLProcessor_Stream(t *testing.T) {
+	p := NewHTMLProcessor()
+	tr := &fakeStreamTranslator{}
+
+	input := `<!DOCTYPE html>
+<html><head><title>Hello</title></head>
+<body>
+  <p class='intro'>Hello &amp; welcome</p>
+  <script>var x = "Hello";</script>
+  <img src=logo.png alt="Logo">
+  <p data-no-translate>Keep me</p>
+  <p>Hello</p>
+</body></html>`
+
+	var out bytes.Buffer
+	if err := p.Stream(context.Background(), strings.NewReader(input), &out, tr); err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+	result := out.String()
+
+	expected := []string{
+		`<!DOCTYPE html>`,
+		`<html lang="ar-SA" dir="rtl">`,
+		`<title>[Hello]</title>`,
+		`<p class='intro'>[Hello &amp; welcome]</p>`,
+		`<script>var x = "Hello";</script>`,
+		`<img src=logo.png alt="Logo">`,
+		`<p data-no-translate>Keep me</p>`,
+		`<p>[Hello]</p>`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Result should contain %q, got:\n%s", want, result)
+		}
+	}
+
+	var texts []string
+	for _, w := range tr.windows {
+		for _, n := range w {
+			texts = append(texts, n.Text)
+		}
+	}
+	if len(texts) != 2 {
+		t.Errorf("Expected 2 unique texts to be translated, got %v", texts)
+	}
+}
+
+func TestHTMLProcessor_Stream_Windows(t *testing.T) {
+	p := NewHTMLProcessor(WithStreamWindow(2))
+	tr := &fakeStreamTranslator{}
+
+	input := `<ul><li>one</li><li>two</li><li>three</li><li>four</li><li>five</li></ul>`
+
+	var out bytes.Buffer
+	if err := p.Stream(context.Background(), strings.NewReader(input), &out, tr); err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+
+	if len(tr.windows) != 3 {
+		t.Fatalf("Expected 3 windows, got %d", len(tr.windows))
+	}
+	if len(tr.windows[0]) != 2 || len(tr.windows[2]) != 1 {
+		t.Errorf("Unexpected window sizes: %d, %d, %d", len(tr.windows[0]), len(tr.windows[1]), len(tr.windows[2]))
+	}
+
+	expected := `<ul><li>[one]</li><li>[two]</li><li>[three]</li><li>[four]</li><li>[five]</li></ul>`
+	if out.String() != expected {
+		t.Errorf("Stream output = %q, want %q", out.String(), expected)
+	}
+}
+
+func TestHTMLProcessor_Stream_BoundedKnown(t *testing.T) {
+	p := NewHTMLProcessor(WithStreamWindow(2))
+	tr := &fakeStreamTranslator{}
+
+	var input, expected strings.Builder
+	for i := 0; i < 500; i++ {
+		fmt.Fprintf(&input, "<p>Item %d</p><p>Item %d</p>", i, i/2)
+		fmt.Fprintf(&expected, "<p>[Item %d]</p><p>[Item %d]</p>", i, i/2)
+	}
+
+	var out bytes.Buffer
+	s := p.newStream(context.Background(), &out, tr)
+	if err := s.run(strings.NewReader(input.String())); err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+	if out.String() != expected.String() {
+		t.Errorf("Unexpected output:\n%s", out.String())
+	}
+
+	limit := 2 * streamKnownWindows
+	if len(s.known) > limit || len(s.knownOrder) != len(s.known) {
+		t.Errorf("Expected at most %d known translations, got %d (%d ordered)", limit, len(s.known), len(s.knownOrder))
+	}
+
+	// Recent repeats are reused; "Item i/2" fell out of the limit for large i
+	translated := 0
+	for _, w := range tr.windows {
+		translated += len(w)
+	}
+	if translated <= 500 || translated >= 1000 {
+		t.Errorf("Expected some repeats to be reused and old ones translated again, got %d translations", translated)
+	}
+}
+
+func TestHTMLProcessor_Stream_Context(t *testing.T) {
+	p := NewHTMLProcessor()
+	tr := &fakeStreamTranslator{}
+
+	input := `<nav><ul><li class="menu-item">Home</li></ul></nav>`
+
+	var out bytes.Buffer
+	if err := p.Stream(context.Background(), strings.NewReader(input), &out, tr); err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+
+	node := tr.windows[0][0]
+	if !strings.Contains(node.Context, `in <li class="menu-item">`) {
+		t.Errorf("Context should contain parent tag, got %q", node.Context)
+	}
+	if !strings.Contains(node.Context, "inside: nav > ul") {
+		t.Errorf("Context should contain ancestor path, got %q", node.Context)
+	}
+}
+
+func TestHTMLProcessor_Stream_TranslateError(t *testing.T) {
+	p := NewHTMLProcessor()
+	tr := &fakeStreamTranslator{err: errors.New("provider down")}
+
+	var out bytes.Buffer
+	err := p.Stream(context.Background(), strings.NewReader(`<p>Hello</p>`), &out, tr)
+	if err == nil {
+		t.Fatal("Expected error from translator")
+	}
+}
@@ -69,6 +69,23 @@ func TestHTMLProcessor_Extract_IgnoredTags(t *testing.T) {
 	}
 }
 
+func TestHTMLProcessor_CustomIgnoredTags(t *testing.T) {
+	p := NewHTMLProcessorWithIgnoredTags([]string{"ASIDE"}, WithAttributes())
+
+	_, nodes, err := p.Extract(`<aside>Skip me</aside><code>Translate me</code><img alt="A photo">`)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	var texts []string
+	for _, n := range nodes {
+		texts = append(texts, n.Text)
+	}
+	if strings.Join(texts, "|") != "Translate me|A photo" {
+		t.Errorf("Expected code text and alt attribute, got %q", texts)
+	}
+}
+
 func TestHTMLProcessor_Extract_DataNoTranslate(t *testing.T) {
 	p := NewHTMLProcessor()
 
@@ -135,6 +152,52 @@ func TestHTMLProcessor_Extract_Context(t *testing.T) {
 	}
 }
 
+func TestHTMLProcessor_Extract_ContextKeys(t *testing.T) {
+	html := `<div>
+		<div role="dialog"><button>Close</button></div>
+		<address>Close</address>
+		<address>Close</address>
+	</div>`
+
+	// Without context keys, identical text is deduplicated
+	_, nodes, err := NewHTMLProcessor().Extract(html)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(nodes) != 1 {
+		t.Fatalf("Expected 1 node without context keys, got %d", len(nodes))
+	}
+
+	p := NewHTMLProcessor(WithContextKeys())
+	parsed, nodes, err := p.Extract(html)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(nodes) != 2 {
+		t.Fatalf("Expected 2 nodes with context keys, got %d", len(nodes))
+	}
+	if nodes[0].Metadata["context_key"] != "button|dialog" {
+		t.Errorf("Expected context key 'button|dialog', got %q", nodes[0].Metadata["context_key"])
+	}
+	if nodes[1].Metadata["context_key"] != "address" {
+		t.Errorf("Expected context key 'address', got %q", nodes[1].Metadata["context_key"])
+	}
+
+	result, err := p.Apply(parsed, nodes, map[string]string{
+		nodes[0].Hash: "Cerrar",
+		nodes[1].Hash: "Cercano",
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if !strings.Contains(result, "<button>Cerrar</button>") {
+		t.Errorf("Button should use its own translation, got: %s", result)
+	}
+	if strings.Count(result, "<address>Cercano</address>") != 2 {
+		t.Errorf("Both addresses should share a translation, got: %s", result)
+	}
+}
+
 func TestHTMLProcessor_Apply(t *testing.T) {
 	p := NewHTMLProcessor()
 
@@ -169,6 +232,31 @@ func TestHTMLProcessor_Apply(t *testing.T) {
 	}
 }
 
+func TestHTMLProcessor_Apply_Reusable(t *testing.T) {
+	p := NewHTMLProcessor()
+
+	parsed, nodes, err := p.Extract(`<p>Hello</p>`)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	first, err := p.Apply(parsed, nodes, map[string]string{nodes[0].Hash: "Hola"})
+	if err != nil {
+		t.Fatalf("First Apply failed: %v", err)
+	}
+	second, err := p.Apply(parsed, nodes, map[string]string{nodes[0].Hash: "Bonjour"})
+	if err != nil {
+		t.Fatalf("Second Apply failed: %v", err)
+	}
+
+	if !strings.Contains(first, "Hola") {
+		t.Errorf("First result should contain 'Hola', got: %s", first)
+	}
+	if !strings.Contains(second, "Bonjour") || strings.Contains(second, "Hola") {
+		t.Errorf("Second result should contain only 'Bonjour', got: %s", second)
+	}
+}
+
 func TestHTMLProcessor_Apply_PreservesWhitespace(t *testing.T) {
 	p := NewHTMLProcessor()
 
@@ -0,0 +1,237 @@
+package processor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ZaguanLabs/gotlai"
+)
+
+// JSONProcessor extracts and applies translations to JSON message catalogs,
+// flat or nested (i18next and react-intl style). Each string leaf becomes a
+// TextNode carrying its dotted key path; numbers, booleans and null are kept.
+// Translations are spliced into the original source, so key order, indentation
+// and untranslated values are unchanged.
+type JSONProcessor struct {
+	keys KeyFilter
+}
+
+// JSONProcessorOption configures the JSON processor.
+type JSONProcessorOption func(*JSONProcessor)
+
+// WithJSONKeys restricts translation to string values whose key path passes filter,
+// e.g. KeyFilter{Exclude: []string{"**.url", "**.id"}}.
+func WithJSONKeys(filter KeyFilter) JSONProcessorOption {
+	return func(p *JSONProcessor) {
+		p.keys = filter
+	}
+}
+
+// NewJSONProcessor creates a new JSON catalog processor.
+func NewJSONProcessor(opts ...JSONProcessorOption) *JSONProcessor {
+	p := &JSONProcessor{}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// jsonString is a translatable string value in a JSON document.
+type jsonString struct {
+	keyPath    string
+	text       string // Decoded value
+	hash       string
+	start, end int // Byte range of the string literal, including quotes
+}
+
+// jsonFrame is an open object or array while walking JSON tokens.
+type jsonFrame struct {
+	object    bool
+	expectKey bool
+	key       string // Current key, or index for arrays
+	index     int
+}
+
+// parsedJSON holds the JSON source and its translatable strings.
+type parsedJSON struct {
+	src     string
+	strings []jsonString
+}
+
+// Extract parses JSON and extracts translatable text nodes.
+func (p *JSONProcessor) Extract(content string) (interface{}, []gotlai.TextNode, error) {
+	values, err := p.jsonStrings(content)
+	if err != nil {
+		return nil, nil, &gotlai.ProcessorError{
+			Message:     "failed to parse JSON",
+			Cause:       err,
+			ContentType: "json",
+		}
+	}
+
+	var nodes []gotlai.TextNode
+	seenHashes := make(map[string]bool)
+	for _, v := range values {
+		if seenHashes[v.hash] {
+			continue
+		}
+		seenHashes[v.hash] = true
+
+		nodes = append(nodes, gotlai.TextNode{
+			ID:       fmt.Sprintf("node-%d", len(nodes)),
+			Text:     strings.TrimSpace(v.text),
+			Hash:     v.hash,
+			NodeType: "json_string",
+			Context:  catalogContext(v.keyPath, "JSON"),
+			Metadata: map[string]string{
+				"key_path": v.keyPath,
+			},
+		})
+	}
+
+	return &parsedJSON{src: content, strings: values}, nodes, nil
+}
+
+// jsonStrings walks the JSON tokens of src and returns the string values selected by the key filter.
+func (p *JSONProcessor) jsonStrings(src string) ([]jsonString, error) {
+	dec := json.NewDecoder(strings.NewReader(src))
+	dec.UseNumber()
+
+	var values []jsonString
+	var stack []jsonFrame
+
+	// keyPath returns the dotted path of the current value
+	keyPath := func() string {
+		parts := make([]string, len(stack))
+		for i, f := range stack {
+			parts[i] = f.key
+		}
+		return strings.Join(parts, ".")
+	}
+
+	// valueDone marks the end of a value inside the current container
+	valueDone := func() {
+		if len(stack) == 0 {
+			return
+		}
+		top := &stack[len(stack)-1]
+		if top.object {
+			top.expectKey = true
+		} else {
+			top.index++
+			top.key = strconv.Itoa(top.index)
+		}
+	}
+
+	for {
+		offset := dec.InputOffset()
+		tok, err := dec.Token()
+		if err == io.EOF && len(stack) == 0 {
+			break
+		}
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch v := tok.(type) {
+		case json.Delim:
+			switch v {
+			case '{':
+				stack = append(stack, jsonFrame{object: true, expectKey: true})
+			case '[':
+				stack = append(stack, jsonFrame{key: "0"})
+			default:
+				stack = stack[:len(stack)-1]
+				valueDone()
+			}
+		case string:
+			if len(stack) > 0 && stack[len(stack)-1].expectKey {
+				stack[len(stack)-1].key = v
+				stack[len(stack)-1].expectKey = false
+				continue
+			}
+
+			path := keyPath()
+			if strings.TrimSpace(v) != "" && p.keys.Match(path) {
+				start := offset + int64(strings.IndexByte(src[offset:], '"'))
+				values = append(values, jsonString{
+					keyPath: path,
+					text:    v,
+					hash:    gotlai.HashText(strings.TrimSpace(v)),
+					start:   int(start),
+					end:     int(dec.InputOffset()),
+				})
+			}
+			valueDone()
+		default:
+			valueDone()
+		}
+	}
+
+	return values, nil
+}
+
+// Apply applies translations back to the JSON source.
+func (p *JSONProcessor) Apply(parsed interface{}, nodes []gotlai.TextNode, translations map[string]string) (string, error) {
+	pj, ok := parsed.(*parsedJSON)
+	if !ok {
+		return "", &gotlai.ProcessorError{
+			Message:     "invalid parsed content type",
+			ContentType: "json",
+		}
+	}
+
+	var b strings.Builder
+	pos := 0
+	for _, v := range pj.strings {
+		translated, ok := translations[v.hash]
+		if !ok {
+			continue
+		}
+		literal, err := marshalJSONString(preserveWhitespace(v.text, translated))
+		if err != nil {
+			continue
+		}
+		b.WriteString(pj.src[pos:v.start])
+		b.WriteString(literal)
+		pos = v.end
+	}
+	b.WriteString(pj.src[pos:])
+
+	return b.String(), nil
+}
+
+// ContentType returns "json".
+func (p *JSONProcessor) ContentType() string {
+	return "json"
+}
+
+// marshalJSONString encodes s as a JSON string literal without HTML escaping.
+func marshalJSONString(s string) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// catalogContext describes a message at keyPath in a resource file of the given format.
+func catalogContext(keyPath, format string) string {
+	message := "UI message"
+	if keyPath != "" {
+		message = fmt.Sprintf("UI message %q", keyPath)
+	}
+	return fmt.Sprintf("%s in a %s resource file; keep placeholders such as {name} and {{name}} unchanged", message, format)
+}
+
+// Verify JSONProcessor implements ContentProcessor
+var _ ContentProcessor = (*JSONProcessor)(nil)
@@ -0,0 +1,134 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+)
+
+const jsonCatalog = `{
+  "home": {
+    "title": "Welcome <b>back</b>",
+    "cta": "Sign up",
+    "hero": {"url": "https://example.com", "id": "hero-1"}
+  },
+  "items": ["One", "Two", 3, true, null],
+  "flat.key": "Flat & simple",
+  "count": 42,
+  "empty": " "
+}
+`
+
+func TestJSONProcessor_Extract(t *testing.T) {
+	p := NewJSONProcessor()
+
+	_, nodes, err := p.Extract(jsonCatalog)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	var paths []string
+	for _, n := range nodes {
+		paths = append(paths, n.Metadata["key_path"]+"="+n.Text)
+	}
+
+	expected := []string{
+		"home.title=Welcome <b>back</b>",
+		"home.cta=Sign up",
+		"home.hero.url=https://example.com",
+		"home.hero.id=hero-1",
+		"items.0=One",
+		"items.1=Two",
+		"flat.key=Flat & simple",
+	}
+	if strings.Join(paths, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, paths)
+	}
+
+	if nodes[0].NodeType != "json_string" || !strings.Contains(nodes[0].Context, `"home.title"`) {
+		t.Errorf("Expected key path in context, got %q %q", nodes[0].NodeType, nodes[0].Context)
+	}
+}
+
+func TestJSONProcessor_KeyFilter(t *testing.T) {
+	p := NewJSONProcessor(WithJSONKeys(KeyFilter{
+		Include: []string{"home.**", "items.*"},
+		Exclude: []string{"**.url", "**.id"},
+	}))
+
+	_, nodes, err := p.Extract(jsonCatalog)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	var texts []string
+	for _, n := range nodes {
+		texts = append(texts, n.Text)
+	}
+
+	expected := []string{"Welcome <b>back</b>", "Sign up", "One", "Two"}
+	if strings.Join(texts, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, texts)
+	}
+}
+
+func TestJSONProcessor_Apply(t *testing.T) {
+	p := NewJSONProcessor(WithJSONKeys(KeyFilter{Exclude: []string{"**.url", "**.id"}}))
+
+	parsed, nodes, err := p.Extract(jsonCatalog)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	result, err := p.Apply(parsed, nodes, bracketAll(nodes))
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	expected := strings.NewReplacer(
+		`"Welcome <b>back</b>"`, `"[Welcome <b>back</b>]"`,
+		`"Sign up"`, `"[Sign up]"`,
+		`"One"`, `"[One]"`,
+		`"Two"`, `"[Two]"`,
+		`"Flat & simple"`, `"[Flat & simple]"`,
+	).Replace(jsonCatalog)
+	if result != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, result)
+	}
+}
+
+func TestJSONProcessor_ApplyEscapes(t *testing.T) {
+	p := NewJSONProcessor()
+
+	parsed, nodes, err := p.Extract(`{"a":"Say \"hi\"\n"}`)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if nodes[0].Text != `Say "hi"` {
+		t.Errorf("Expected decoded text, got %q", nodes[0].Text)
+	}
+
+	result, err := p.Apply(parsed, nodes, map[string]string{nodes[0].Hash: `Di "hola"`})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if expected := `{"a":"Di \"hola\"\n"}`; result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestJSONProcessor_InvalidJSON(t *testing.T) {
+	p := NewJSONProcessor()
+
+	for _, input := range []string{`{"a": "b"`, `{"a" "b"}`} {
+		if _, _, err := p.Extract(input); err == nil {
+			t.Errorf("Expected error for %q", input)
+		}
+	}
+}
+
+func TestJSONProcessor_ContentType(t *testing.T) {
+	p := NewJSONProcessor()
+	if p.ContentType() != "json" {
+		t.Errorf("Expected 'json', got %q", p.ContentType())
+	}
+}
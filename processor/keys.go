@@ -0,0 +1,60 @@
+package processor
+
+import (
+	"path"
+	"strings"
+)
+
+// KeyFilter selects keys of resource files by dotted key path, e.g. "home.title"
+// or "items.0.label". Patterns are matched per path segment with path.Match
+// syntax, and "**" matches any number of segments, so "errors.*" matches
+// "errors.required" and "**.url" matches a "url" key at any depth.
+type KeyFilter struct {
+	Include []string // Translate only keys matching one of these; all keys when empty
+	Exclude []string // Never translate keys matching one of these
+}
+
+// Match reports whether the key path passes the filter.
+func (f KeyFilter) Match(keyPath string) bool {
+	for _, pattern := range f.Exclude {
+		if matchKeyGlob(pattern, keyPath) {
+			return false
+		}
+	}
+	if len(f.Include) == 0 {
+		return true
+	}
+	for _, pattern := range f.Include {
+		if matchKeyGlob(pattern, keyPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchKeyGlob reports whether a dotted key path matches a dotted glob pattern.
+func matchKeyGlob(pattern, keyPath string) bool {
+	return matchKeySegments(strings.Split(pattern, "."), strings.Split(keyPath, "."))
+}
+
+// matchKeySegments matches key path segments against pattern segments.
+func matchKeySegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(segments); i++ {
+				if matchKeySegments(pattern[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], segments[0]); err != nil || !ok {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
@@ -0,0 +1,29 @@
+package processor
+
+import "testing"
+
+func TestKeyFilter_Match(t *testing.T) {
+	tests := []struct {
+		filter   KeyFilter
+		keyPath  string
+		expected bool
+	}{
+		{KeyFilter{}, "home.title", true},
+		{KeyFilter{Include: []string{"home.*"}}, "home.title", true},
+		{KeyFilter{Include: []string{"home.*"}}, "home.hero.title", false},
+		{KeyFilter{Include: []string{"home.**"}}, "home.hero.title", true},
+		{KeyFilter{Exclude: []string{"**.url"}}, "url", false},
+		{KeyFilter{Exclude: []string{"**.url"}}, "links.0.url", false},
+		{KeyFilter{Exclude: []string{"**.url"}}, "links.0.label", true},
+		{KeyFilter{Exclude: []string{"*_id"}}, "user_id", false},
+		{KeyFilter{Include: []string{"**"}, Exclude: []string{"meta.**"}}, "meta.version", false},
+		{KeyFilter{Include: []string{"[ab].x"}}, "b.x", true},
+		{KeyFilter{Include: []string{"[a"}}, "a", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.filter.Match(tt.keyPath); got != tt.expected {
+			t.Errorf("%+v.Match(%q) = %v, want %v", tt.filter, tt.keyPath, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package processor
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/ZaguanLabs/gotlai"
+)
+
+// DefaultFrontMatterKeys lists the front matter keys translated when no keys are given.
+var DefaultFrontMatterKeys = []string{"title", "description"}
+
+// MarkdownProcessor extracts and applies translations to CommonMark and GFM
+// documents. Paragraphs, headings, list items, table cells and link text are
+// translated; code spans, fenced and indented code, URLs, link destinations and
+// HTML are kept. Each block is sent as one segment where code spans, links and
+// inline HTML become placeholders, as with WithInlineSegments. Translations are
+// spliced into the original source, so only translated text changes; a soft-wrapped
+// paragraph is written back on one line.
+type MarkdownProcessor struct {
+	frontMatterKeys map[string]bool
+}
+
+// MarkdownProcessorOption configures the Markdown processor.
+type MarkdownProcessorOption func(*MarkdownProcessor)
+
+// WithFrontMatterKeys sets the top-level YAML (---) or TOML (+++) front matter
+// keys whose string values are translated. With no keys, front matter is kept as is.
+func WithFrontMatterKeys(keys ...string) MarkdownProcessorOption {
+	return func(p *MarkdownProcessor) {
+		p.frontMatterKeys = make(map[string]bool, len(keys))
+		for _, key := range keys {
+			p.frontMatterKeys[key] = true
+		}
+	}
+}
+
+// NewMarkdownProcessor creates a new Markdown processor that translates DefaultFrontMatterKeys.
+func NewMarkdownProcessor(opts ...MarkdownProcessorOption) *MarkdownProcessor {
+	p := &MarkdownProcessor{}
+	WithFrontMatterKeys(DefaultFrontMatterKeys...)(p)
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// markdownBlock is a translatable span of the Markdown source.
+type markdownBlock struct {
+	start, end int // Byte range of the source text replaced by the translation
+	hash       string
+	seg        *codeSegment // Inline segment, nil for front matter values
+	quote      string       // Front matter quoting: `"`, `'` or "" for plain YAML
+	toml       bool
+	tableCell  bool // Pipes in the translation must be escaped
+}
+
+// parsedMarkdown holds the Markdown source and its translatable blocks.
+type parsedMarkdown struct {
+	src    string
+	blocks []markdownBlock
+}
+
+// Extract parses Markdown and extracts translatable text nodes.
+func (p *MarkdownProcessor) Extract(content string) (interface{}, []gotlai.TextNode, error) {
+	pm := &parsedMarkdown{src: content}
+	var nodes []gotlai.TextNode
+	seenHashes := make(map[string]bool)
+
+	add := func(block markdownBlock, node gotlai.TextNode) {
+		pm.blocks = append(pm.blocks, block)
+		if seenHashes[block.hash] {
+			return
+		}
+		seenHashes[block.hash] = true
+		node.ID = fmt.Sprintf("node-%d", len(nodes))
+		node.Hash = block.hash
+		nodes = append(nodes, node)
+	}
+
+	values, bodyStart := p.frontMatter(content)
+	for _, v := range values {
+		format := "yaml"
+		if v.block.toml {
+			format = "toml"
+		}
+		add(v.block, gotlai.TextNode{
+			Text:     v.text,
+			NodeType: "markdown_front_matter",
+			Context:  fmt.Sprintf("%s field in the front matter of a Markdown document", v.key),
+			Metadata: map[string]string{
+				"front_matter_key": v.key,
+				"format":           format,
+			},
+		})
+	}
+
+	blocks, refs := scanMarkdownBlocks(content, bodyStart)
+	for _, raw := range blocks {
+		parts := make([]string, len(raw.lines))
+		for i, line := range raw.lines {
+			parts[i] = content[line.start:line.end]
+		}
+		seg := buildMarkdownSegment(strings.Join(parts, " "), refs)
+		if !hasText(seg) {
+			continue
+		}
+
+		context := "Markdown " + raw.kind
+		if raw.quoted {
+			context += " inside a blockquote"
+		}
+		if len(seg.paired) > 0 {
+			context += " | contains placeholders for code, links and inline HTML"
+		}
+
+		add(markdownBlock{
+			start:     raw.lines[0].start,
+			end:       raw.lines[len(raw.lines)-1].end,
+			hash:      gotlai.HashText(seg.text),
+			seg:       seg,
+			tableCell: strings.HasPrefix(raw.kind, "table"),
+		}, gotlai.TextNode{
+			Text:     seg.text,
+			NodeType: "markdown_text",
+			Context:  context,
+			Metadata: map[string]string{
+				"block":        raw.kind,
+				"placeholders": encodePlaceholders(seg.paired),
+			},
+		})
+	}
+
+	return pm, nodes, nil
+}
+
+// Apply applies translations back to the Markdown source.
+func (p *MarkdownProcessor) Apply(parsed interface{}, nodes []gotlai.TextNode, translations map[string]string) (string, error) {
+	pm, ok := parsed.(*parsedMarkdown)
+	if !ok {
+		return "", &gotlai.ProcessorError{
+			Message:     "invalid parsed content type",
+			ContentType: "markdown",
+		}
+	}
+
+	var b strings.Builder
+	pos := 0
+	for _, block := range pm.blocks {
+		translated, ok := translations[block.hash]
+		if !ok {
+			continue
+		}
+
+		var replacement string
+		if block.seg != nil {
+			items, err := parseSegment(translated, block.seg.paired)
+			if err != nil {
+				continue
+			}
+			var escape func(string) string
+			if block.tableCell {
+				escape = escapeTableCell
+			}
+			replacement = block.seg.render(items, escape)
+		} else {
+			replacement = quoteFrontMatter(translated, block.quote, block.toml)
+		}
+
+		b.WriteString(pm.src[pos:block.start])
+		b.WriteString(replacement)
+		pos = block.end
+	}
+	b.WriteString(pm.src[pos:])
+
+	return b.String(), nil
+}
+
+// escapeTableCell escapes the pipes of a table cell that are not already escaped.
+func escapeTableCell(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			b.WriteString(s[i : i+2])
+			i++
+		case s[i] == '|':
+			b.WriteString(`\|`)
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// ValidateTranslation checks that a Markdown translation keeps every placeholder.
+func (p *MarkdownProcessor) ValidateTranslation(node gotlai.TextNode, translated string) error {
+	if node.NodeType != "markdown_text" {
+		return nil
+	}
+	_, err := parseSegment(translated, decodePlaceholders(node.Metadata["placeholders"]))
+	return err
+}
+
+// ContentType returns "markdown".
+func (p *MarkdownProcessor) ContentType() string {
+	return "markdown"
+}
+
+// hasLetter reports whether s contains a letter.
+func hasLetter(s string) bool {
+	return strings.IndexFunc(s, unicode.IsLetter) >= 0
+}
+
+// Verify MarkdownProcessor implements ContentProcessor and TranslationValidator
+var (
+	_ ContentProcessor            = (*MarkdownProcessor)(nil)
+	_ gotlai.TranslationValidator = (*MarkdownProcessor)(nil)
+)
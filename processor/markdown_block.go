@@ -0,0 +1,408 @@
+package processor
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	mdThematicPattern  = regexp.MustCompile(`^(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	mdSetextPattern    = regexp.MustCompile(`^(?:=+|-+)[ \t]*$`)
+	mdATXPattern       = regexp.MustCompile(`^#{1,6}(?:[ \t]+|$)`)
+	mdATXClosePattern  = regexp.MustCompile(`(?:^|[ \t]+)#+[ \t]*$`)
+	mdListPattern      = regexp.MustCompile(`^(?:[-+*]|[0-9]{1,9}[.)])(?:[ \t]+(?:\[[ xX]\][ \t]+)?|$)`)
+	mdRefDefPattern    = regexp.MustCompile(`^\[([^\]]+)\]:[ \t]*`)
+	mdDelimiterPattern = regexp.MustCompile(`^\|?[ \t]*:?-+:?[ \t]*(?:\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+
+	mdHTMLRawPattern   = regexp.MustCompile(`^<(?i:(script|pre|style|textarea))(?:[ \t>]|$)`)
+	mdHTMLBlockPattern = regexp.MustCompile(`^</?(?i:address|article|aside|blockquote|body|details|dialog|dd|div|dl|dt|fieldset|figcaption|figure|footer|form|h[1-6]|head|header|hr|html|iframe|li|main|nav|ol|p|section|summary|table|tbody|td|tfoot|th|thead|title|tr|ul)(?:[ \t]|/?>|$)`)
+	mdHTMLTagPattern   = regexp.MustCompile(`^(?:<[A-Za-z][A-Za-z0-9-]*(?:\s+[^<>]*)?/?>|</[A-Za-z][A-Za-z0-9-]*\s*>)[ \t]*$`)
+)
+
+// mdLine is the text of one source line within a block.
+type mdLine struct {
+	start, end int  // Byte range of the text, without a trailing hard line break
+	full       int  // End of the text including a trailing backslash
+	hardBreak  bool // Whether the line ends with a hard line break
+}
+
+// mdRawBlock is a run of lines translated as one segment.
+type mdRawBlock struct {
+	kind   string
+	quoted bool
+	lines  []mdLine
+}
+
+// mdBlockScanner splits a Markdown document into translatable blocks.
+type mdBlockScanner struct {
+	src    string
+	blocks []mdRawBlock
+	refs   map[string]bool
+
+	para      []mdLine
+	paraKind  string
+	paraDepth int
+}
+
+// scanMarkdownBlocks returns the translatable blocks of src from offset start,
+// and the normalized labels of its link reference definitions.
+func scanMarkdownBlocks(src string, start int) ([]mdRawBlock, map[string]bool) {
+	sc := &mdBlockScanner{src: src, refs: make(map[string]bool)}
+	lines := splitLines(src, start)
+
+	var fenceChar byte
+	var fenceLen int
+	var htmlOpen bool
+	var htmlEnd string
+	inList, inTable, prevBlank := false, false, true
+
+	for li := 0; li < len(lines); li++ {
+		ls, le := lines[li][0], lines[li][1]
+		line := src[ls:le]
+		pos, depth := stripQuotes(line)
+		rest := line[pos:]
+		blank := strings.TrimSpace(rest) == ""
+
+		if fenceChar != 0 {
+			if closesFence(rest, fenceChar, fenceLen) {
+				fenceChar = 0
+			}
+			continue
+		}
+		if htmlOpen {
+			if htmlEnd == "" && blank {
+				htmlOpen = false
+				prevBlank = true
+			} else if htmlEnd != "" && strings.Contains(strings.ToLower(line), htmlEnd) {
+				htmlOpen = false
+			}
+			continue
+		}
+		if blank {
+			sc.flush()
+			inTable = false
+			prevBlank = true
+			continue
+		}
+
+		indent, n := indentation(rest)
+		body := rest[n:]
+		bodyStart := ls + pos + n
+		marker := len(mdListPattern.FindString(body))
+		wasBlank := prevBlank
+		prevBlank = false
+
+		if inTable {
+			if strings.Contains(body, "|") {
+				sc.tableRow("table cell", depth, bodyStart, le)
+				continue
+			}
+			inTable = false
+		}
+
+		if wasBlank && indent == 0 && marker == 0 {
+			inList = false
+		}
+
+		// Indented code, unless it continues a paragraph or a list item
+		if indent >= 4 && !inList {
+			if len(sc.para) > 0 {
+				sc.addLine(bodyStart, le)
+			}
+			continue
+		}
+
+		if char, n := openingFence(body); n > 0 {
+			sc.flush()
+			fenceChar, fenceLen = char, n
+			continue
+		}
+		if len(sc.para) > 0 && mdSetextPattern.MatchString(body) {
+			level := 2
+			if body[0] == '=' {
+				level = 1
+			}
+			sc.paraKind = fmt.Sprintf("heading (level %d)", level)
+			sc.flush()
+			continue
+		}
+		if mdThematicPattern.MatchString(body) {
+			sc.flush()
+			continue
+		}
+		if sc.heading(body, bodyStart, depth) {
+			continue
+		}
+		if end, ok := htmlBlockStart(body, len(sc.para) > 0); ok {
+			sc.flush()
+			if end == "" || !strings.Contains(strings.ToLower(body), end) {
+				htmlOpen, htmlEnd = true, end
+			}
+			continue
+		}
+
+		kind := "paragraph"
+		if marker > 0 {
+			sc.flush()
+			inList = true
+			kind = "list item"
+			for marker > 0 {
+				body = body[marker:]
+				bodyStart += marker
+				marker = len(mdListPattern.FindString(body))
+			}
+			if strings.TrimSpace(body) == "" {
+				continue
+			}
+			if char, n := openingFence(body); n > 0 {
+				fenceChar, fenceLen = char, n
+				continue
+			}
+			if sc.heading(body, bodyStart, depth) {
+				continue
+			}
+		}
+
+		if len(sc.para) == 0 {
+			if m := mdRefDefPattern.FindStringSubmatchIndex(body); m != nil {
+				label := body[m[2]:m[3]]
+				sc.refs[normalizeLabel(label)] = true
+				if !strings.HasPrefix(label, "^") {
+					continue
+				}
+				// Footnote definitions hold translatable text
+				kind = "footnote"
+				body = body[m[1]:]
+				bodyStart += m[1]
+			}
+
+			if kind != "footnote" && strings.Contains(body, "|") && li+1 < len(lines) {
+				next := src[lines[li+1][0]:lines[li+1][1]]
+				npos, _ := stripQuotes(next)
+				delim := strings.TrimSpace(next[npos:])
+				if strings.Contains(delim, "|") && mdDelimiterPattern.MatchString(delim) {
+					sc.tableRow("table header cell", depth, bodyStart, le)
+					inTable = true
+					li++
+					continue
+				}
+			}
+		} else if depth > sc.paraDepth {
+			sc.flush()
+		}
+
+		if len(sc.para) == 0 {
+			sc.paraKind = kind
+			sc.paraDepth = depth
+		}
+		sc.addLine(bodyStart, le)
+	}
+	sc.flush()
+
+	return sc.blocks, sc.refs
+}
+
+// addLine adds the text from start to end to the open paragraph.
+func (sc *mdBlockScanner) addLine(start, end int) {
+	text := sc.src[start:end]
+	trimmed := strings.TrimRight(text, " \t")
+	line := mdLine{start: start, end: start + len(trimmed), full: start + len(trimmed)}
+
+	switch {
+	case len(text)-len(trimmed) >= 2 && strings.HasSuffix(text, "  "):
+		line.hardBreak = true
+	case strings.HasSuffix(trimmed, `\`):
+		line.hardBreak = true
+		line.end = start + len(strings.TrimRight(trimmed[:len(trimmed)-1], " \t"))
+	}
+
+	sc.para = append(sc.para, line)
+}
+
+// flush closes the open paragraph, splitting it into blocks at hard line breaks.
+func (sc *mdBlockScanner) flush() {
+	if len(sc.para) == 0 {
+		return
+	}
+
+	last := &sc.para[len(sc.para)-1]
+	last.end = last.full
+
+	var group []mdLine
+	for i, line := range sc.para {
+		if line.end > line.start {
+			group = append(group, line)
+		}
+		if (line.hardBreak || i == len(sc.para)-1) && len(group) > 0 {
+			sc.blocks = append(sc.blocks, mdRawBlock{kind: sc.paraKind, quoted: sc.paraDepth > 0, lines: group})
+			group = nil
+		}
+	}
+	sc.para = nil
+}
+
+// heading adds an ATX heading block if body is one.
+func (sc *mdBlockScanner) heading(body string, bodyStart, depth int) bool {
+	m := mdATXPattern.FindStringIndex(body)
+	if m == nil {
+		return false
+	}
+	sc.flush()
+
+	content := strings.TrimRight(body[m[1]:], " \t")
+	content = content[:len(content)-len(mdATXClosePattern.FindString(content))]
+	if content != "" {
+		start := bodyStart + m[1]
+		end := start + len(content)
+		level := len(strings.TrimRight(body[:m[1]], " \t"))
+		sc.blocks = append(sc.blocks, mdRawBlock{
+			kind:   fmt.Sprintf("heading (level %d)", level),
+			quoted: depth > 0,
+			lines:  []mdLine{{start: start, end: end, full: end}},
+		})
+	}
+	return true
+}
+
+// tableRow adds a block for each cell of the table row from start to end.
+func (sc *mdBlockScanner) tableRow(kind string, depth, start, end int) {
+	src := sc.src
+	addCell := func(from, to int) {
+		cell := src[from:to]
+		trimmed := strings.TrimSpace(cell)
+		if trimmed == "" {
+			return
+		}
+		cellStart := from + strings.Index(cell, trimmed)
+		cellEnd := cellStart + len(trimmed)
+		sc.blocks = append(sc.blocks, mdRawBlock{
+			kind:   kind,
+			quoted: depth > 0,
+			lines:  []mdLine{{start: cellStart, end: cellEnd, full: cellEnd}},
+		})
+	}
+
+	cellStart := start
+	for i := start; i < end; i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case '`':
+			if n := codeSpanLen(src[i:end]); n > 0 {
+				i += n - 1
+			} else {
+				i += backtickRun(src[i:end]) - 1
+			}
+		case '|':
+			addCell(cellStart, i)
+			cellStart = i + 1
+		}
+	}
+	addCell(cellStart, end)
+}
+
+// splitLines returns the [start, end) byte ranges of the lines of src from
+// offset start, excluding line endings.
+func splitLines(src string, start int) [][2]int {
+	var lines [][2]int
+	for pos := start; pos < len(src); {
+		end := strings.IndexByte(src[pos:], '\n')
+		next := len(src)
+		if end < 0 {
+			end = len(src)
+		} else {
+			end += pos
+			next = end + 1
+		}
+		lines = append(lines, [2]int{pos, len(strings.TrimSuffix(src[:end], "\r"))})
+		pos = next
+	}
+	return lines
+}
+
+// stripQuotes returns the offset after any blockquote markers and the quote depth.
+func stripQuotes(line string) (pos, depth int) {
+	for {
+		i := pos
+		for i < len(line) && i-pos < 3 && line[i] == ' ' {
+			i++
+		}
+		if i >= len(line) || line[i] != '>' {
+			return pos, depth
+		}
+		i++
+		if i < len(line) && (line[i] == ' ' || line[i] == '\t') {
+			i++
+		}
+		pos = i
+		depth++
+	}
+}
+
+// indentation returns the column width and byte length of the leading whitespace of s.
+func indentation(s string) (cols, n int) {
+	for n < len(s) {
+		switch s[n] {
+		case ' ':
+			cols++
+		case '\t':
+			cols += 4 - cols%4
+		default:
+			return cols, n
+		}
+		n++
+	}
+	return cols, n
+}
+
+// openingFence returns the fence character and length if s opens a fenced code block.
+func openingFence(s string) (byte, int) {
+	if s == "" || (s[0] != '`' && s[0] != '~') {
+		return 0, 0
+	}
+	n := 0
+	for n < len(s) && s[n] == s[0] {
+		n++
+	}
+	if n < 3 || (s[0] == '`' && strings.Contains(s[n:], "`")) {
+		return 0, 0
+	}
+	return s[0], n
+}
+
+// closesFence reports whether s closes a fence of at least n characters c.
+func closesFence(s string, c byte, n int) bool {
+	s = strings.TrimSpace(s)
+	run := 0
+	for run < len(s) && s[run] == c {
+		run++
+	}
+	return run >= n && run == len(s)
+}
+
+// htmlBlockStart reports whether s starts an HTML block, returning the lowercase
+// marker that ends it, or "" if it ends at a blank line. Blocks made of a single
+// arbitrary tag cannot interrupt a paragraph.
+func htmlBlockStart(s string, inParagraph bool) (string, bool) {
+	if !strings.HasPrefix(s, "<") {
+		return "", false
+	}
+	switch {
+	case mdHTMLRawPattern.MatchString(s):
+		return "</" + strings.ToLower(mdHTMLRawPattern.FindStringSubmatch(s)[1]) + ">", true
+	case strings.HasPrefix(s, "<!--"):
+		return "-->", true
+	case strings.HasPrefix(s, "<?"):
+		return "?>", true
+	case strings.HasPrefix(s, "<![CDATA["):
+		return "]]>", true
+	case len(s) > 2 && s[1] == '!' && (s[2] >= 'A' && s[2] <= 'Z' || s[2] >= 'a' && s[2] <= 'z'):
+		return ">", true
+	case mdHTMLBlockPattern.MatchString(s):
+		return "", true
+	case !inParagraph && mdHTMLTagPattern.MatchString(s):
+		return "", true
+	}
+	return "", false
+}
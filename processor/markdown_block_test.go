@@ -0,0 +1,90 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+)
+
+// markdownBlockTexts returns "kind:text" for each block of src.
+func markdownBlockTexts(src string) []string {
+	blocks, _ := scanMarkdownBlocks(src, 0)
+	var texts []string
+	for _, b := range blocks {
+		var parts []string
+		for _, line := range b.lines {
+			parts = append(parts, src[line.start:line.end])
+		}
+		texts = append(texts, b.kind+":"+strings.Join(parts, " "))
+	}
+	return texts
+}
+
+func TestScanMarkdownBlocks(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		expected []string
+	}{
+		{
+			name:     "headings",
+			src:      "## Closed ##\n\nSetext\n---\n",
+			expected: []string{"heading (level 2):Closed", "heading (level 2):Setext"},
+		},
+		{
+			name:     "soft and hard breaks",
+			src:      "one\ntwo  \nthree\\\nfour\\\n",
+			expected: []string{"paragraph:one two", "paragraph:three", "paragraph:four\\"},
+		},
+		{
+			name:     "fenced and indented code",
+			src:      "~~~\ncode\n~~~\n\n    indented\n\ntext\n    continued\n",
+			expected: []string{"paragraph:text continued"},
+		},
+		{
+			name:     "nested lists and tasks",
+			src:      "1. First\n   - [x] Done\n\n     Loose paragraph\n",
+			expected: []string{"list item:First", "list item:Done", "paragraph:Loose paragraph"},
+		},
+		{
+			name:     "blockquotes",
+			src:      "> Quoted\nlazy line\n> > Nested\n",
+			expected: []string{"paragraph:Quoted lazy line", "paragraph:Nested"},
+		},
+		{
+			name:     "html blocks",
+			src:      "<!-- a\ncomment -->\n<div>\nhidden\n</div>\n\n<span>inline</span> text\n",
+			expected: []string{"paragraph:<span>inline</span> text"},
+		},
+		{
+			name:     "tables",
+			src:      "| A | `b|c` |\n| - | :-: |\n| 1 | two \\| three |\n",
+			expected: []string{"table header cell:A", "table header cell:`b|c`", "table cell:1", "table cell:two \\| three"},
+		},
+		{
+			name:     "reference definitions",
+			src:      "[docs]: https://example.com\n[^note]: A footnote\n",
+			expected: []string{"footnote:A footnote"},
+		},
+		{
+			name:     "thematic breaks",
+			src:      "* * *\n___\n",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := markdownBlockTexts(tt.src)
+			if strings.Join(got, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestScanMarkdownBlocks_Refs(t *testing.T) {
+	_, refs := scanMarkdownBlocks("[The  Docs]: /docs\n", 0)
+	if !refs["the docs"] {
+		t.Errorf("Expected normalized reference label, got %v", refs)
+	}
+}
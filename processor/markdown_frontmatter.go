@@ -0,0 +1,175 @@
+package processor
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ZaguanLabs/gotlai"
+)
+
+// yamlKeyPattern matches a top-level YAML key and the rest of its line.
+var yamlKeyPattern = regexp.MustCompile(`^([A-Za-z0-9_-]+)[ \t]*:(?:[ \t]+|$)`)
+
+// tomlKeyPattern matches a top-level TOML key and the rest of its line.
+var tomlKeyPattern = regexp.MustCompile(`^([A-Za-z0-9_-]+)[ \t]*=[ \t]*`)
+
+// frontMatterValue is a translatable string value in front matter.
+type frontMatterValue struct {
+	key   string
+	text  string // Decoded value
+	block markdownBlock
+}
+
+// frontMatter returns the translatable values of the YAML (---) or TOML (+++)
+// front matter at the start of src, and the offset where the document body starts.
+func (p *MarkdownProcessor) frontMatter(src string) ([]frontMatterValue, int) {
+	var delim string
+	switch {
+	case strings.HasPrefix(src, "---\n"), strings.HasPrefix(src, "---\r\n"):
+		delim = "---"
+	case strings.HasPrefix(src, "+++\n"), strings.HasPrefix(src, "+++\r\n"):
+		delim = "+++"
+	default:
+		return nil, 0
+	}
+	toml := delim == "+++"
+
+	var values []frontMatterValue
+	inTable := false
+	for pos := strings.IndexByte(src, '\n') + 1; pos < len(src); {
+		end := strings.IndexByte(src[pos:], '\n')
+		next := len(src)
+		if end < 0 {
+			end = len(src)
+		} else {
+			end += pos
+			next = end + 1
+		}
+		line := strings.TrimSuffix(src[pos:end], "\r")
+
+		if line == delim || (!toml && line == "...") {
+			return values, next
+		}
+
+		// Keys inside TOML tables are not top-level
+		if toml && strings.HasPrefix(strings.TrimSpace(line), "[") {
+			inTable = true
+		}
+
+		pattern := yamlKeyPattern
+		if toml {
+			pattern = tomlKeyPattern
+		}
+		if m := pattern.FindStringSubmatchIndex(line); m != nil && !inTable && p.frontMatterKeys[line[m[2]:m[3]]] {
+			if v, ok := frontMatterScalar(line[m[1]:], toml); ok {
+				v.key = line[m[2]:m[3]]
+				v.block.start += pos + m[1]
+				v.block.end += pos + m[1]
+				values = append(values, v)
+			}
+		}
+
+		pos = next
+	}
+
+	// Unterminated front matter is part of the document
+	return nil, 0
+}
+
+// frontMatterScalar parses a single-line string value. Block scalars, flow
+// collections, anchors and non-string TOML values are not translated.
+func frontMatterScalar(s string, toml bool) (frontMatterValue, bool) {
+	var v frontMatterValue
+	if s == "" {
+		return v, false
+	}
+
+	switch s[0] {
+	case '"':
+		end := closingQuote(s)
+		if end < 0 {
+			return v, false
+		}
+		text, err := strconv.Unquote(s[:end+1])
+		if err != nil {
+			return v, false
+		}
+		v.text = text
+		v.block = markdownBlock{end: end + 1, quote: `"`, toml: toml}
+	case '\'':
+		end := 1
+		for ; end < len(s); end++ {
+			if s[end] != '\'' {
+				continue
+			}
+			if !toml && end+1 < len(s) && s[end+1] == '\'' {
+				end++
+				continue
+			}
+			break
+		}
+		if end >= len(s) {
+			return v, false
+		}
+		v.text = s[1:end]
+		if !toml {
+			v.text = strings.ReplaceAll(v.text, "''", "'")
+		}
+		v.block = markdownBlock{end: end + 1, quote: "'", toml: toml}
+	default:
+		if toml || strings.IndexByte("|>[{&*!%@`#", s[0]) >= 0 {
+			return v, false
+		}
+		value := s
+		if i := strings.Index(value, " #"); i >= 0 {
+			value = value[:i]
+		}
+		value = strings.TrimRight(value, " \t")
+		v.text = value
+		v.block = markdownBlock{end: len(value)}
+	}
+
+	if strings.TrimSpace(v.text) == "" {
+		return v, false
+	}
+	v.block.hash = gotlai.HashText(v.text)
+	return v, true
+}
+
+// closingQuote returns the index of the unescaped '"' closing the string at the start of s, or -1.
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// quoteFrontMatter encodes a translated value in the original quoting style,
+// switching to double quotes when the original style cannot hold it.
+func quoteFrontMatter(s, quote string, toml bool) string {
+	switch {
+	case quote == "'" && toml && !strings.ContainsAny(s, "'\n"):
+		return "'" + s + "'"
+	case quote == "'" && !toml && !strings.Contains(s, "\n"):
+		return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	case quote == "" && plainYAMLSafe(s):
+		return s
+	}
+
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// plainYAMLSafe reports whether s can be written as a plain YAML scalar.
+func plainYAMLSafe(s string) bool {
+	if s == "" || s != strings.TrimSpace(s) || strings.IndexByte("-?:,[]{}#&*!|>'\"%@`", s[0]) >= 0 {
+		return false
+	}
+	return !strings.Contains(s, ": ") && !strings.Contains(s, " #") && !strings.ContainsAny(s, "\n\t") && !strings.HasSuffix(s, ":")
+}
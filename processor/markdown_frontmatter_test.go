@@ -0,0 +1,93 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkdownProcessor_FrontMatter(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		expected string
+	}{
+		{
+			name:     "yaml plain",
+			src:      "---\ntitle: Hello # comment\ndate: 2024-01-01\n---\nBody\n",
+			expected: "---\ntitle: \"[Hello]\" # comment\ndate: 2024-01-01\n---\n[Body]\n",
+		},
+		{
+			name:     "yaml plain needing quotes",
+			src:      "---\ntitle: Hello\n---\n",
+			expected: "---\ntitle: \"Hola: \\\"x\\\"\"\n---\n",
+		},
+		{
+			name:     "yaml plain kept plain",
+			src:      "---\ntitle: Hello\n---\n",
+			expected: "---\ntitle: Hola\n---\n",
+		},
+		{
+			name:     "yaml single quoted",
+			src:      "---\ndescription: 'It''s here'\n---\n",
+			expected: "---\ndescription: '[It''s here]'\n---\n",
+		},
+		{
+			name:     "yaml nested and block scalars",
+			src:      "---\nseo:\n  title: Nested\ndescription: >\n  Folded\n---\n",
+			expected: "---\nseo:\n  title: Nested\ndescription: >\n  Folded\n---\n",
+		},
+		{
+			name:     "toml",
+			src:      "+++\ntitle = \"Hello\"\ndescription = 'Literal'\ndraft = true\n[params]\ntitle = \"Nested\"\n+++\n",
+			expected: "+++\ntitle = \"[Hello]\"\ndescription = '[Literal]'\ndraft = true\n[params]\ntitle = \"Nested\"\n+++\n",
+		},
+		{
+			name:     "unterminated",
+			src:      "---\ntitle: Hello\n",
+			expected: "---\n[title: Hello]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewMarkdownProcessor()
+			parsed, nodes, err := p.Extract(tt.src)
+			if err != nil {
+				t.Fatalf("Extract failed: %v", err)
+			}
+
+			translations := bracketAll(nodes)
+			switch tt.name {
+			case "yaml plain needing quotes":
+				translations[nodes[0].Hash] = `Hola: "x"`
+			case "yaml plain kept plain":
+				translations[nodes[0].Hash] = "Hola"
+			}
+
+			result, err := p.Apply(parsed, nodes, translations)
+			if err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestMarkdownProcessor_FrontMatterKeys(t *testing.T) {
+	p := NewMarkdownProcessor(WithFrontMatterKeys("summary"))
+
+	_, nodes, err := p.Extract("---\ntitle: Kept\nsummary: Translated\n---\n")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	var texts []string
+	for _, n := range nodes {
+		texts = append(texts, n.Text)
+	}
+	if strings.Join(texts, "|") != "Translated" {
+		t.Errorf("Expected only the summary, got %q", texts)
+	}
+}
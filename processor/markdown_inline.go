@@ -0,0 +1,209 @@
+package processor
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// markdownAutolinkPattern matches autolinks such as <https://example.com> and <me@example.com>.
+var markdownAutolinkPattern = regexp.MustCompile(`^<(?:[A-Za-z][A-Za-z0-9+.-]{1,31}:[^\s<>]*|[^\s@<>]+@[^\s@<>]+)>`)
+
+// markdownHTMLPattern matches inline HTML tags and comments.
+var markdownHTMLPattern = regexp.MustCompile(`^(?:</?[A-Za-z][A-Za-z0-9-]*(?:\s+[^<>]*)?/?>|<!--[\s\S]*?-->)`)
+
+// markdownURLPattern matches GFM bare URLs.
+var markdownURLPattern = regexp.MustCompile(`^(?:https?://|www\.)[^\s<]+`)
+
+// buildMarkdownSegment flattens inline Markdown into text with placeholders.
+// Code spans, autolinks, bare URLs, footnote references and inline HTML become
+// self-closing placeholders; links and images become paired placeholders around
+// their text, keeping destinations untouched. refs holds normalized reference
+// labels so shortcut links like [docs] are recognized.
+func buildMarkdownSegment(s string, refs map[string]bool) *codeSegment {
+	seg := &codeSegment{}
+	var b strings.Builder
+	seg.scanMarkdown(&b, s, refs)
+	seg.text = strings.Join(strings.Fields(b.String()), " ")
+	return seg
+}
+
+// scanMarkdown writes s to b, replacing protected inline content with placeholders.
+func (seg *codeSegment) scanMarkdown(b *strings.Builder, s string, refs map[string]bool) {
+	text := 0
+	atom := func(i, n int) {
+		b.WriteString(escapeSegmentText(s[text:i]))
+		seg.writeAtom(b, s[i:i+n])
+		text = i + n
+	}
+
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s):
+			i += 2
+			continue
+		case c == '`':
+			if n := codeSpanLen(s[i:]); n > 0 {
+				atom(i, n)
+				i += n
+				continue
+			}
+			i += backtickRun(s[i:])
+			continue
+		case c == '<':
+			if m := markdownAutolinkPattern.FindString(s[i:]); m != "" {
+				atom(i, len(m))
+				i += len(m)
+				continue
+			}
+			if m := markdownHTMLPattern.FindString(s[i:]); m != "" {
+				atom(i, len(m))
+				i += len(m)
+				continue
+			}
+		case (c == 'h' || c == 'w') && (i == 0 || strings.IndexByte(" \t(*_~", s[i-1]) >= 0):
+			if n := bareURLLen(s[i:]); n > 0 {
+				atom(i, n)
+				i += n
+				continue
+			}
+		case c == '[' || (c == '!' && strings.HasPrefix(s[i:], "![")):
+			open := "["
+			if c == '!' {
+				open = "!["
+			}
+			inner, closing, n := markdownLink(s[i+len(open)-1:], refs)
+			switch {
+			case n == 0:
+			case strings.HasPrefix(inner, "^") && closing == "]", !hasText(buildMarkdownSegment(inner, refs)):
+				atom(i, len(open)-1+n)
+				i += len(open) - 1 + n
+				continue
+			default:
+				b.WriteString(escapeSegmentText(s[text:i]))
+				id := seg.writeOpen(b, open, closing)
+				seg.scanMarkdown(b, inner, refs)
+				fmt.Fprintf(b, "</x%d>", id)
+				i += len(open) - 1 + n
+				text = i
+				continue
+			}
+		}
+		i++
+	}
+	b.WriteString(escapeSegmentText(s[text:]))
+}
+
+// backtickRun returns the length of the run of backticks at the start of s.
+func backtickRun(s string) int {
+	n := 0
+	for n < len(s) && s[n] == '`' {
+		n++
+	}
+	return n
+}
+
+// codeSpanLen returns the length of the code span at the start of s, or 0 if
+// its opening backticks are not closed by a run of the same length.
+func codeSpanLen(s string) int {
+	open := backtickRun(s)
+	for i := open; i < len(s); {
+		if s[i] != '`' {
+			i++
+			continue
+		}
+		run := backtickRun(s[i:])
+		if run == open {
+			return i + run
+		}
+		i += run
+	}
+	return 0
+}
+
+// bareURLLen returns the length of the bare URL at the start of s, without
+// trailing punctuation or an unbalanced closing parenthesis, or 0 if there is none.
+func bareURLLen(s string) int {
+	m := markdownURLPattern.FindString(s)
+	for m != "" {
+		last := m[len(m)-1]
+		if strings.IndexByte(".,:;!?'\"*_~", last) >= 0 ||
+			(last == ')' && strings.Count(m, "(") < strings.Count(m, ")")) {
+			m = m[:len(m)-1]
+			continue
+		}
+		break
+	}
+	if m == "www." || strings.HasSuffix(m, "://") {
+		return 0
+	}
+	return len(m)
+}
+
+// markdownLink parses a link starting at the '[' at the start of s. It returns
+// the link text, the source after the text (e.g. "](url)"), and the total length,
+// or 0 if s does not start a link.
+func markdownLink(s string, refs map[string]bool) (inner, closing string, n int) {
+	end := matchBracket(s, '[', ']')
+	if end < 0 {
+		return "", "", 0
+	}
+	inner = s[1:end]
+	rest := s[end+1:]
+
+	switch {
+	case strings.HasPrefix(rest, "("):
+		if close := matchBracket(rest, '(', ')'); close >= 0 {
+			return inner, "]" + rest[:close+1], end + close + 2
+		}
+	case strings.HasPrefix(rest, "["):
+		if close := matchBracket(rest, '[', ']'); close >= 0 {
+			label := rest[1:close]
+			if label == "" {
+				label = inner
+			}
+			if refs[normalizeLabel(label)] {
+				return inner, "]" + rest[:close+1], end + close + 2
+			}
+		}
+	case strings.HasPrefix(inner, "^") || refs[normalizeLabel(inner)]:
+		return inner, "]", end + 1
+	}
+
+	return "", "", 0
+}
+
+// matchBracket returns the index of the bracket closing the one at the start of
+// s, skipping escapes, code spans and angle-bracket destinations, or -1.
+func matchBracket(s string, open, close byte) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\':
+			i++
+		case c == '`':
+			if n := codeSpanLen(s[i:]); n > 0 {
+				i += n - 1
+			} else {
+				i += backtickRun(s[i:]) - 1
+			}
+		case c == '<' && open == '(':
+			if j := strings.IndexByte(s[i:], '>'); j >= 0 {
+				i += j
+			}
+		case c == open:
+			depth++
+		case c == close:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// normalizeLabel normalizes a link reference label for matching.
+func normalizeLabel(label string) string {
+	return strings.ToLower(strings.Join(strings.Fields(label), " "))
+}
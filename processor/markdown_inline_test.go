@@ -0,0 +1,45 @@
+package processor
+
+import "testing"
+
+func TestBuildMarkdownSegment(t *testing.T) {
+	refs := map[string]bool{"docs": true}
+
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{"Plain *text* here", "Plain *text* here"},
+		{"Use ``a ` b`` now", "Use <x1/> now"},
+		{"Unclosed `tick", "Unclosed `tick"},
+		{"Read [the **guide**](/a (b)) first", "Read <x1>the **guide**</x1> first"},
+		{"See [docs] and [more][docs] and [x][missing]", "See <x1>docs</x1> and <x2>more</x2> and [x][missing]"},
+		{"An ![icon](i.png) image", "An <x1>icon</x1> image"},
+		{"Link [`code`](/c)", "Link <x1/>"},
+		{"Visit https://example.com/a_(b). Or www.example.com!", "Visit <x1/>. Or <x2/>!"},
+		{"Mail <me@example.com> or <https://x.io>", "Mail <x1/> or <x2/>"},
+		{"A <b>bold</b> <!-- c --> move", "A <x1/>bold<x2/> <x3/> move"},
+		{"Note[^1] and 1 < 2 & \\[not a link]", "Note<x1/> and 1 &lt; 2 &amp; \\[not a link]"},
+	}
+
+	for _, tt := range tests {
+		seg := buildMarkdownSegment(tt.src, refs)
+		if seg.text != tt.expected {
+			t.Errorf("buildMarkdownSegment(%q) = %q, want %q", tt.src, seg.text, tt.expected)
+		}
+	}
+}
+
+func TestMarkdownSegment_Render(t *testing.T) {
+	seg := buildMarkdownSegment("Read [the guide](/guide \"Guide\") and run `make` & <br> wait", nil)
+
+	items, err := parseSegment("Ejecuta <x2/> &amp; lee <x1>la guía</x1> <x3/> espera", seg.paired)
+	if err != nil {
+		t.Fatalf("parseSegment failed: %v", err)
+	}
+
+	expected := "Ejecuta `make` & lee [la guía](/guide \"Guide\") <br> espera"
+	if got := seg.render(items, nil); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
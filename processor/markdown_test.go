@@ -0,0 +1,188 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+)
+
+const markdownDoc = "---\n" +
+	"title: Getting Started\n" +
+	"layout: docs\n" +
+	"---\n" +
+	"# Install the *CLI*\n" +
+	"\n" +
+	"Run `go install` and read [the guide](https://example.com/guide).\n" +
+	"It takes a minute.\n" +
+	"\n" +
+	"```sh\n" +
+	"go install example.com/cli@latest\n" +
+	"```\n" +
+	"\n" +
+	"- First step\n" +
+	"- Second step\n" +
+	"\n" +
+	"| Option | Meaning |\n" +
+	"|--------|---------|\n" +
+	"| `-v`   | Verbose |\n" +
+	"\n" +
+	"<div class=\"note\">\n" +
+	"Raw HTML\n" +
+	"</div>\n"
+
+func TestMarkdownProcessor_Extract(t *testing.T) {
+	p := NewMarkdownProcessor()
+
+	_, nodes, err := p.Extract(markdownDoc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	var texts []string
+	for _, n := range nodes {
+		texts = append(texts, n.Text)
+	}
+
+	expected := []string{
+		"Getting Started",
+		"Install the *CLI*",
+		"Run <x1/> and read <x2>the guide</x2>. It takes a minute.",
+		"First step",
+		"Second step",
+		"Option",
+		"Meaning",
+		"Verbose",
+	}
+	if strings.Join(texts, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, texts)
+	}
+
+	if nodes[0].NodeType != "markdown_front_matter" || nodes[0].Metadata["front_matter_key"] != "title" {
+		t.Errorf("Expected front matter title node, got %+v", nodes[0])
+	}
+	if nodes[1].NodeType != "markdown_text" || nodes[1].Context != "Markdown heading (level 1)" {
+		t.Errorf("Expected heading node, got %q %q", nodes[1].NodeType, nodes[1].Context)
+	}
+	if nodes[2].Metadata["placeholders"] != "sp" {
+		t.Errorf("Expected placeholders %q, got %q", "sp", nodes[2].Metadata["placeholders"])
+	}
+	if nodes[3].Metadata["block"] != "list item" || nodes[5].Metadata["block"] != "table header cell" {
+		t.Errorf("Expected list item and table header blocks, got %q %q", nodes[3].Metadata["block"], nodes[5].Metadata["block"])
+	}
+}
+
+func TestMarkdownProcessor_Apply(t *testing.T) {
+	p := NewMarkdownProcessor()
+
+	parsed, nodes, err := p.Extract(markdownDoc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	translations := map[string]string{}
+	spanish := map[string]string{
+		"Getting Started":   "Primeros pasos",
+		"Install the *CLI*": "Instala la *CLI*",
+		"First step":        "Primer paso",
+		"Verbose":           "Detallado",
+		"Run <x1/> and read <x2>the guide</x2>. It takes a minute.": "Lee <x2>la guía</x2> y ejecuta <x1/>. Tarda un minuto.",
+	}
+	for _, n := range nodes {
+		if s, ok := spanish[n.Text]; ok {
+			translations[n.Hash] = s
+		}
+	}
+
+	result, err := p.Apply(parsed, nodes, translations)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	expected := strings.NewReplacer(
+		"title: Getting Started", "title: Primeros pasos",
+		"# Install the *CLI*", "# Instala la *CLI*",
+		"Run `go install` and read [the guide](https://example.com/guide).\nIt takes a minute.",
+		"Lee [la guía](https://example.com/guide) y ejecuta `go install`. Tarda un minuto.",
+		"- First step", "- Primer paso",
+		"| Verbose |", "| Detallado |",
+	).Replace(markdownDoc)
+	if result != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, result)
+	}
+}
+
+func TestMarkdownProcessor_ApplyInvalidPlaceholders(t *testing.T) {
+	p := NewMarkdownProcessor()
+	src := "See [the docs](/docs) for `details`.\n"
+
+	parsed, nodes, err := p.Extract(src)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if err := p.ValidateTranslation(nodes[0], "Consulta la documentación."); err == nil {
+		t.Error("Expected validation error for dropped placeholders")
+	}
+
+	result, err := p.Apply(parsed, nodes, map[string]string{nodes[0].Hash: "Consulta la documentación."})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if result != src {
+		t.Errorf("Expected source kept for invalid translation, got %q", result)
+	}
+}
+
+func TestMarkdownProcessor_ApplyIsRepeatable(t *testing.T) {
+	p := NewMarkdownProcessor()
+
+	parsed, nodes, err := p.Extract(markdownDoc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	first, _ := p.Apply(parsed, nodes, bracketAll(nodes))
+	second, _ := p.Apply(parsed, nodes, bracketAll(nodes))
+	if first != second {
+		t.Errorf("Expected repeated Apply to match:\n%s\n---\n%s", first, second)
+	}
+}
+
+func TestMarkdownProcessor_TableCellPipes(t *testing.T) {
+	p := NewMarkdownProcessor()
+
+	src := "| Option | Meaning |\n| --- | --- |\n| `-v` | Verbose \\| quiet |\n"
+	parsed, nodes, err := p.Extract(src)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	translations := make(map[string]string)
+	for _, n := range nodes {
+		switch n.Text {
+		case "Meaning":
+			translations[n.Hash] = "Sens | usage"
+		case `Verbose \| quiet`:
+			translations[n.Hash] = `Détaillé \| silencieux`
+		}
+	}
+	if len(translations) != 2 {
+		t.Fatalf("Expected both cells to be extracted, got %v", nodes)
+	}
+
+	result, err := p.Apply(parsed, nodes, translations)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	expected := "| Option | Sens \\| usage |\n| --- | --- |\n| `-v` | Détaillé \\| silencieux |\n"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestMarkdownProcessor_ContentType(t *testing.T) {
+	p := NewMarkdownProcessor()
+	if p.ContentType() != "markdown" {
+		t.Errorf("Expected 'markdown', got %q", p.ContentType())
+	}
+}
@@ -0,0 +1,188 @@
+package processor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ZaguanLabs/gotlai"
+)
+
+// cldrPluralCategories maps language codes to their CLDR plural categories for
+// integers, as used by Android <plurals> and Apple .stringsdict files.
+var cldrPluralCategories = map[string][]string{
+	"ja": {"other"}, "zh": {"other"}, "ko": {"other"}, "vi": {"other"},
+	"th": {"other"}, "id": {"other"}, "ms": {"other"}, "lo": {"other"},
+	"km": {"other"}, "my": {"other"},
+
+	"ru": {"one", "few", "many", "other"}, "uk": {"one", "few", "many", "other"},
+	"be": {"one", "few", "many", "other"}, "pl": {"one", "few", "many", "other"},
+	"cs": {"one", "few", "many", "other"}, "sk": {"one", "few", "many", "other"},
+	"lt": {"one", "few", "many", "other"},
+
+	"hr": {"one", "few", "other"}, "sr": {"one", "few", "other"},
+	"bs": {"one", "few", "other"}, "ro": {"one", "few", "other"},
+
+	"lv": {"zero", "one", "other"},
+	"he": {"one", "two", "other"},
+	"sl": {"one", "two", "few", "other"},
+	"ga": {"one", "two", "few", "many", "other"},
+	"ar": {"zero", "one", "two", "few", "many", "other"},
+	"cy": {"zero", "one", "two", "few", "many", "other"},
+}
+
+// pluralCategories returns the CLDR plural categories of a language code such
+// as "ru" or "pt_BR", defaulting to "one" and "other".
+func pluralCategories(lang string) []string {
+	base, _, _ := strings.Cut(strings.ReplaceAll(lang, "-", "_"), "_")
+	if categories, ok := cldrPluralCategories[strings.ToLower(base)]; ok {
+		return categories
+	}
+	return []string{"one", "other"}
+}
+
+// pluralCategory returns the CLDR plural category a language uses for the
+// count n. Categories only used for fractions, such as "other" in Russian, are
+// never returned.
+func pluralCategory(lang string, n int) string {
+	base, _, _ := strings.Cut(strings.ReplaceAll(lang, "-", "_"), "_")
+	base = strings.ToLower(base)
+	n10, n100 := n%10, n%100
+	switch base {
+	case "ja", "zh", "ko", "vi", "th", "id", "ms", "lo", "km", "my":
+		return "other"
+	case "ru", "uk", "be", "pl", "hr", "sr", "bs":
+		switch {
+		case n10 == 1 && n100 != 11 && (n == 1 || base != "pl"):
+			return "one"
+		case n10 >= 2 && n10 <= 4 && (n100 < 12 || n100 > 14):
+			return "few"
+		case base == "hr" || base == "sr" || base == "bs":
+			return "other"
+		}
+		return "many"
+	case "cs", "sk":
+		switch {
+		case n == 1:
+			return "one"
+		case n >= 2 && n <= 4:
+			return "few"
+		}
+	case "lt":
+		switch {
+		case n10 == 1 && (n100 < 11 || n100 > 19):
+			return "one"
+		case n10 >= 2 && (n100 < 11 || n100 > 19):
+			return "few"
+		}
+	case "ro":
+		switch {
+		case n == 1:
+			return "one"
+		case n == 0 || (n100 >= 2 && n100 <= 19):
+			return "few"
+		}
+	case "lv":
+		switch {
+		case n10 == 0 || (n100 >= 11 && n100 <= 19):
+			return "zero"
+		case n10 == 1:
+			return "one"
+		}
+	case "he":
+		switch n {
+		case 1:
+			return "one"
+		case 2:
+			return "two"
+		}
+	case "sl":
+		switch n100 {
+		case 1:
+			return "one"
+		case 2:
+			return "two"
+		case 3, 4:
+			return "few"
+		}
+	case "ga":
+		switch {
+		case n == 1:
+			return "one"
+		case n == 2:
+			return "two"
+		case n >= 3 && n <= 6:
+			return "few"
+		case n >= 7 && n <= 10:
+			return "many"
+		}
+	case "ar":
+		switch {
+		case n <= 2:
+			return []string{"zero", "one", "two"}[n]
+		case n100 >= 3 && n100 <= 10:
+			return "few"
+		case n100 >= 11:
+			return "many"
+		}
+	case "cy":
+		switch n {
+		case 0, 1, 2:
+			return []string{"zero", "one", "two"}[n]
+		case 3:
+			return "few"
+		case 6:
+			return "many"
+		}
+	default:
+		if n == 1 {
+			return "one"
+		}
+	}
+	return "other"
+}
+
+// pluralFormContext describes a target plural category for the AI, with
+// sample counts that select it.
+func pluralFormContext(lang, category string) string {
+	return fmt.Sprintf("plural form %q of the target language, used for %s; translate the \"other\" text for these counts", category, pluralCounts(lang, category))
+}
+
+// pluralCounts describes the counts that select a plural category, such as
+// "counts such as 2, 3, 4, 22, 23, 24".
+func pluralCounts(lang, category string) string {
+	var samples []string
+	for n := 0; n <= 1000 && len(samples) < 6; n++ {
+		if pluralCategory(lang, n) == category {
+			samples = append(samples, strconv.Itoa(n))
+		}
+	}
+	if len(samples) == 0 {
+		return "fractional counts such as 1.5"
+	}
+	return "counts such as " + strings.Join(samples, ", ")
+}
+
+// pluralFormHash returns the hash of the translation of text for a target
+// plural category that has no form of its own in the source.
+func pluralFormHash(text, category string) string {
+	return gotlai.HashTextWithContext(text, "plural:"+category)
+}
+
+// pluralSource picks the source form for a target category. "zero", "one" and
+// "other" use their own form when the source has one. Other categories, and
+// those missing from the source, are translated from "other" on their own, and
+// own reports false.
+func pluralSource[T any](forms map[string]T, category string) (form T, own, ok bool) {
+	if form, ok := forms[category]; ok && (category == "one" || category == "zero" || category == "other") {
+		return form, true, true
+	}
+	form, ok = forms["other"]
+	return form, false, ok
+}
+
+// pluralReviewComment is written before plural forms copied from "other"
+// because their own translation is missing.
+func pluralReviewComment(category string) string {
+	return fmt.Sprintf("<!-- gotlai: %q copied from \"other\", needs review -->", category)
+}
@@ -0,0 +1,76 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPluralCategories(t *testing.T) {
+	tests := map[string]string{
+		"en_US": "one,other",
+		"ja":    "other",
+		"ru-RU": "one,few,many,other",
+		"ar_SA": "zero,one,two,few,many,other",
+	}
+	for lang, expected := range tests {
+		if got := strings.Join(pluralCategories(lang), ","); got != expected {
+			t.Errorf("pluralCategories(%q) = %q, want %q", lang, got, expected)
+		}
+	}
+}
+
+func TestPluralSource(t *testing.T) {
+	forms := map[string]string{"zero": "No songs", "one": "%d song", "few": "%d songs (few)", "other": "%d songs"}
+
+	tests := []struct {
+		category, expected string
+		own                bool
+	}{
+		{"zero", "No songs", true},
+		{"one", "%d song", true},
+		{"other", "%d songs", true},
+		// Categories whose meaning depends on the language are translated from "other"
+		{"few", "%d songs", false},
+		{"many", "%d songs", false},
+	}
+	for _, tt := range tests {
+		got, own, ok := pluralSource(forms, tt.category)
+		if !ok || got != tt.expected || own != tt.own {
+			t.Errorf("pluralSource(%q) = %q, %v, want %q, %v", tt.category, got, own, tt.expected, tt.own)
+		}
+	}
+
+	if _, _, ok := pluralSource(map[string]string{"one": "x"}, "few"); ok {
+		t.Error("Expected no source without an other form")
+	}
+}
+
+func TestPluralCategory(t *testing.T) {
+	counts := []int{0, 1, 2, 3, 5, 11, 12, 21, 22, 25, 101, 111}
+	tests := map[string]string{
+		"en":    "other,one,other,other,other,other,other,other,other,other,other,other",
+		"ru_RU": "many,one,few,few,many,many,many,one,few,many,one,many",
+		"pl":    "many,one,few,few,many,many,many,many,few,many,many,many",
+		"cs":    "other,one,few,few,other,other,other,other,other,other,other,other",
+		"hr":    "other,one,few,few,other,other,other,one,few,other,one,other",
+		"ar":    "zero,one,two,few,few,many,many,many,many,many,other,many",
+		"lv":    "zero,one,other,other,other,zero,zero,one,other,other,one,zero",
+		"ja":    "other,other,other,other,other,other,other,other,other,other,other,other",
+	}
+	for lang, expected := range tests {
+		var got []string
+		for _, n := range counts {
+			got = append(got, pluralCategory(lang, n))
+		}
+		if strings.Join(got, ",") != expected {
+			t.Errorf("pluralCategory(%q) = %q, want %q", lang, strings.Join(got, ","), expected)
+		}
+	}
+
+	if context := pluralFormContext("ru", "few"); !strings.Contains(context, "counts such as 2, 3, 4, 22, 23, 24") {
+		t.Errorf("Unexpected context %q", context)
+	}
+	if context := pluralFormContext("ru", "other"); !strings.Contains(context, "fractional counts") {
+		t.Errorf("Unexpected context %q", context)
+	}
+}
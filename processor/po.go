@@ -0,0 +1,611 @@
+package processor
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ZaguanLabs/gotlai"
+)
+
+// poKeywordPattern matches the keyword and quoted string of a PO message line.
+var poKeywordPattern = regexp.MustCompile(`^(msgctxt|msgid_plural|msgid|msgstr(?:\[(\d+)\])?)\s+(".*")\s*$`)
+
+// poNPluralsPattern extracts nplurals from a Plural-Forms header.
+var poNPluralsPattern = regexp.MustCompile(`nplurals\s*=\s*(\d+)`)
+
+// POProcessor extracts and applies translations to GNU gettext .po and .pot
+// files. Only entries whose msgstr is empty or that are flagged fuzzy are
+// translated; msgctxt and extracted comments (#.) become node context. Plural
+// entries are sent as two nodes, msgid and msgid_plural, and written with as many
+// msgstr[n] forms as the target language's Plural-Forms needs: the form for n == 1
+// gets the msgid translation. Languages with two forms use the msgid_plural
+// translation for the other; languages with more (ru, pl, ar...) get a node per
+// form from TargetNodes, described by the counts that select it. Entries missing
+// a form's translation fall back to msgid_plural and stay fuzzy. Other translated
+// entries lose their fuzzy flag, the header gets the target Language and
+// Plural-Forms, and every other line is kept byte for byte.
+type POProcessor struct {
+	markFuzzy bool
+}
+
+// POProcessorOption configures the PO processor.
+type POProcessorOption func(*POProcessor)
+
+// WithMarkFuzzy flags translated entries as fuzzy so translators review them.
+// Fuzzy entries are translated again on the next run.
+func WithMarkFuzzy() POProcessorOption {
+	return func(p *POProcessor) {
+		p.markFuzzy = true
+	}
+}
+
+// NewPOProcessor creates a new gettext PO processor.
+func NewPOProcessor(opts ...POProcessorOption) *POProcessor {
+	p := &POProcessor{}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// poLine is a source line of a PO entry.
+type poLine struct {
+	text string // Line without its line ending
+	kind string // "comment", "flags", "extracted", "previous", "obsolete" or a keyword
+}
+
+// poEntry is a message of a PO file.
+type poEntry struct {
+	start, end  int // Byte range of the entry's lines, without the final line ending
+	lines       []poLine
+	msgctxt     *string
+	msgid       string
+	msgidPlural *string
+	msgstr      map[int]string
+	flags       []string
+	extracted   []string
+	obsolete    bool
+	hash        string // Hash of msgid
+	pluralHash  string // Hash of msgid_plural
+}
+
+// parsedPO holds the PO source and its entries.
+type parsedPO struct {
+	src     string
+	newline string
+	entries []*poEntry
+}
+
+// isHeader reports whether the entry is the PO header.
+func (e *poEntry) isHeader() bool {
+	return e.msgid == "" && e.msgctxt == nil && !e.obsolete
+}
+
+// fuzzy reports whether the entry is flagged fuzzy.
+func (e *poEntry) fuzzy() bool {
+	for _, flag := range e.flags {
+		if flag == "fuzzy" {
+			return true
+		}
+	}
+	return false
+}
+
+// translatable reports whether the entry needs a translation.
+func (e *poEntry) translatable() bool {
+	if e.obsolete || e.isHeader() || strings.TrimSpace(e.msgid) == "" {
+		return false
+	}
+	if e.fuzzy() {
+		return true
+	}
+	for _, s := range e.msgstr {
+		if s != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Extract parses a PO file and extracts the untranslated and fuzzy messages.
+func (p *POProcessor) Extract(content string) (interface{}, []gotlai.TextNode, error) {
+	pp, err := parsePO(content)
+	if err != nil {
+		return nil, nil, &gotlai.ProcessorError{
+			Message:     "failed to parse PO file",
+			Cause:       err,
+			ContentType: "po",
+		}
+	}
+
+	var nodes []gotlai.TextNode
+	seenHashes := make(map[string]bool)
+	add := func(e *poEntry, text, hash string, plural bool) {
+		if seenHashes[hash] {
+			return
+		}
+		seenHashes[hash] = true
+
+		node := gotlai.TextNode{
+			ID:       fmt.Sprintf("node-%d", len(nodes)),
+			Text:     strings.TrimSpace(text),
+			Hash:     hash,
+			NodeType: "po_message",
+			Context:  poContext(e, plural),
+			Metadata: map[string]string{},
+		}
+		if e.msgctxt != nil {
+			node.Metadata["msgctxt"] = *e.msgctxt
+		}
+		if len(e.flags) > 0 {
+			node.Metadata["flags"] = strings.Join(e.flags, ", ")
+		}
+		if plural {
+			node.Metadata["plural"] = "true"
+		}
+		nodes = append(nodes, node)
+	}
+
+	for _, e := range pp.entries {
+		if !e.translatable() {
+			continue
+		}
+		add(e, e.msgid, e.hash, false)
+		if e.msgidPlural != nil {
+			add(e, *e.msgidPlural, e.pluralHash, true)
+		}
+	}
+
+	return pp, nodes, nil
+}
+
+// TargetNodes returns a node per plural form of languages with more than two
+// forms, besides the form for n == 1, so that each is translated for the
+// counts it is used for.
+func (p *POProcessor) TargetNodes(parsed interface{}, nodes []gotlai.TextNode, targetLang string) []gotlai.TextNode {
+	pp, ok := parsed.(*parsedPO)
+	rule := pluralRuleFor(targetLang)
+	if !ok || targetLang == "" || rule.count <= 2 {
+		return nil
+	}
+
+	var extra []gotlai.TextNode
+	seenHashes := make(map[string]bool)
+	for _, e := range pp.entries {
+		if !e.translatable() || e.msgidPlural == nil {
+			continue
+		}
+		for form := 0; form < rule.count; form++ {
+			hash := e.formHash(form)
+			if form == rule.one || seenHashes[hash] {
+				continue
+			}
+			seenHashes[hash] = true
+
+			context := fmt.Sprintf("%s | plural form msgstr[%d] of the target language, used for counts such as %s; singular: %q",
+				poContext(e, false), form, rule.samples(form), e.msgid)
+			node := gotlai.TextNode{
+				ID:       fmt.Sprintf("node-%d", len(nodes)+len(extra)),
+				Text:     strings.TrimSpace(*e.msgidPlural),
+				Hash:     hash,
+				NodeType: "po_message",
+				Context:  context,
+				Metadata: map[string]string{"plural": "true", "plural_form": strconv.Itoa(form)},
+			}
+			if e.msgctxt != nil {
+				node.Metadata["msgctxt"] = *e.msgctxt
+			}
+			if len(e.flags) > 0 {
+				node.Metadata["flags"] = strings.Join(e.flags, ", ")
+			}
+			extra = append(extra, node)
+		}
+	}
+	return extra
+}
+
+// formHash returns the hash of the translation of a plural entry's msgstr[form].
+func (e *poEntry) formHash(form int) string {
+	ctx := ""
+	if e.msgctxt != nil {
+		ctx = *e.msgctxt
+	}
+	return gotlai.HashTextWithContext(*e.msgidPlural, fmt.Sprintf("%s\x00msgstr[%d]", ctx, form))
+}
+
+// poContext describes an entry for the AI.
+func poContext(e *poEntry, plural bool) string {
+	parts := []string{"gettext message"}
+	if e.msgctxt != nil {
+		parts = append(parts, fmt.Sprintf("context: %s", *e.msgctxt))
+	}
+	if len(e.extracted) > 0 {
+		parts = append(parts, strings.Join(e.extracted, " "))
+	}
+	for _, flag := range e.flags {
+		if strings.HasSuffix(flag, "-format") && !strings.HasPrefix(flag, "no-") {
+			parts = append(parts, "keep format directives such as %s, %d and {0} unchanged")
+			break
+		}
+	}
+	if plural {
+		parts = append(parts, "plural form, used for counts other than one")
+	}
+	return strings.Join(parts, " | ")
+}
+
+// parsePO splits a PO file into entries separated by blank lines.
+func parsePO(src string) (*parsedPO, error) {
+	pp := &parsedPO{src: src, newline: "\n"}
+	if strings.Contains(src, "\r\n") {
+		pp.newline = "\r\n"
+	}
+
+	var cur *poEntry
+	var field string // Keyword the next continuation line belongs to
+	lineNo := 0
+
+	finish := func() {
+		if cur != nil && field != "" {
+			pp.entries = append(pp.entries, cur)
+		}
+		cur, field = nil, ""
+	}
+
+	for pos := 0; pos < len(src); {
+		lineNo++
+		end := strings.IndexByte(src[pos:], '\n')
+		next := len(src)
+		if end < 0 {
+			end = len(src)
+		} else {
+			end += pos
+			next = end + 1
+		}
+		text := strings.TrimSuffix(src[pos:end], "\r")
+		lineEnd := pos + len(text)
+		trimmed := strings.TrimSpace(text)
+
+		if trimmed == "" {
+			finish()
+			pos = next
+			continue
+		}
+		if cur == nil {
+			cur = &poEntry{start: pos, msgstr: make(map[int]string)}
+		}
+		cur.end = lineEnd
+
+		line := poLine{text: text, kind: "comment"}
+		switch {
+		case strings.HasPrefix(trimmed, "#,"):
+			line.kind = "flags"
+			for _, flag := range strings.Split(trimmed[2:], ",") {
+				if flag = strings.TrimSpace(flag); flag != "" {
+					cur.flags = append(cur.flags, flag)
+				}
+			}
+		case strings.HasPrefix(trimmed, "#."):
+			line.kind = "extracted"
+			if comment := strings.TrimSpace(trimmed[2:]); comment != "" {
+				cur.extracted = append(cur.extracted, comment)
+			}
+		case strings.HasPrefix(trimmed, "#|"):
+			line.kind = "previous"
+		case strings.HasPrefix(trimmed, "#~"):
+			line.kind = "obsolete"
+			cur.obsolete = true
+		case strings.HasPrefix(trimmed, "#"):
+		case strings.HasPrefix(trimmed, `"`):
+			if field == "" {
+				return nil, fmt.Errorf("line %d: string without keyword", lineNo)
+			}
+			s, err := strconv.Unquote(trimmed)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			line.kind = field
+			cur.appendString(field, s)
+		default:
+			m := poKeywordPattern.FindStringSubmatch(trimmed)
+			if m == nil {
+				return nil, fmt.Errorf("line %d: unexpected %q", lineNo, trimmed)
+			}
+			s, err := strconv.Unquote(m[3])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			field = m[1]
+			if m[2] != "" {
+				field = "msgstr[" + m[2] + "]"
+			}
+			line.kind = field
+			cur.startString(field, s)
+		}
+
+		cur.lines = append(cur.lines, line)
+		pos = next
+	}
+	finish()
+
+	for _, e := range pp.entries {
+		ctx := ""
+		if e.msgctxt != nil {
+			ctx = *e.msgctxt
+		}
+		e.hash = gotlai.HashTextWithContext(e.msgid, ctx)
+		if e.msgidPlural != nil {
+			e.pluralHash = gotlai.HashTextWithContext(*e.msgidPlural, ctx)
+		}
+	}
+
+	return pp, nil
+}
+
+// startString sets the field of a keyword line.
+func (e *poEntry) startString(field, s string) {
+	switch {
+	case field == "msgctxt":
+		e.msgctxt = &s
+	case field == "msgid":
+		e.msgid = s
+	case field == "msgid_plural":
+		e.msgidPlural = &s
+	default:
+		e.msgstr[msgstrIndex(field)] = s
+	}
+}
+
+// appendString appends a continuation line to a field.
+func (e *poEntry) appendString(field, s string) {
+	switch {
+	case field == "msgctxt":
+		*e.msgctxt += s
+	case field == "msgid":
+		e.msgid += s
+	case field == "msgid_plural":
+		*e.msgidPlural += s
+	default:
+		e.msgstr[msgstrIndex(field)] += s
+	}
+}
+
+// msgstrIndex returns the plural index of "msgstr[n]", or 0 for "msgstr".
+func msgstrIndex(field string) int {
+	n, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(field, "msgstr["), "]"))
+	return n
+}
+
+// Apply applies translations using the plural forms of the file's existing header.
+func (p *POProcessor) Apply(parsed interface{}, nodes []gotlai.TextNode, translations map[string]string) (string, error) {
+	return p.ApplyTarget(parsed, nodes, translations, "")
+}
+
+// ApplyTarget applies translations for targetLang, writing its plural forms and
+// updating the Language and Plural-Forms headers.
+func (p *POProcessor) ApplyTarget(parsed interface{}, nodes []gotlai.TextNode, translations map[string]string, targetLang string) (string, error) {
+	pp, ok := parsed.(*parsedPO)
+	if !ok {
+		return "", &gotlai.ProcessorError{
+			Message:     "invalid parsed content type",
+			ContentType: "po",
+		}
+	}
+
+	rule := pluralRuleFor(targetLang)
+	var header *poEntry
+	for _, e := range pp.entries {
+		if e.isHeader() {
+			header = e
+			break
+		}
+	}
+	if targetLang == "" {
+		rule = pluralNotOne
+		if header != nil {
+			if m := poNPluralsPattern.FindStringSubmatch(header.msgstr[0]); m != nil {
+				rule.count, _ = strconv.Atoi(m[1])
+			}
+		}
+	}
+
+	var b strings.Builder
+	pos := 0
+	if header == nil && targetLang != "" {
+		lines := append([]string{`msgid ""`}, poHeaderLines(poDefaultHeader, targetLang, rule)...)
+		b.WriteString(strings.Join(lines, pp.newline))
+		b.WriteString(pp.newline + pp.newline)
+	}
+
+	for _, e := range pp.entries {
+		var lines []string
+		switch {
+		case e == header && targetLang != "":
+			lines = p.rewriteEntry(e, poHeaderLines(e.msgstr[0], targetLang, rule), false)
+		case e.translatable():
+			msgstr, fuzzy, ok := poMsgstrLines(e, translations, rule)
+			if !ok {
+				continue
+			}
+			lines = p.rewriteEntry(e, msgstr, p.markFuzzy || fuzzy)
+		default:
+			continue
+		}
+
+		b.WriteString(pp.src[pos:e.start])
+		b.WriteString(strings.Join(lines, pp.newline))
+		pos = e.end
+	}
+	b.WriteString(pp.src[pos:])
+
+	return b.String(), nil
+}
+
+// rewriteEntry returns the entry's lines with msgstr replaced, the fuzzy flag
+// set or cleared, and previous-msgid (#|) comments dropped unless fuzzy.
+func (p *POProcessor) rewriteEntry(e *poEntry, msgstr []string, fuzzy bool) []string {
+	var flags []string
+	for _, flag := range e.flags {
+		if flag != "fuzzy" {
+			flags = append(flags, flag)
+		}
+	}
+	if fuzzy {
+		flags = append([]string{"fuzzy"}, flags...)
+	}
+	flagsLine := ""
+	if len(flags) > 0 {
+		flagsLine = "#, " + strings.Join(flags, ", ")
+	}
+
+	var lines []string
+	wroteFlags, wroteMsgstr := false, false
+	for _, line := range e.lines {
+		switch {
+		case line.kind == "flags":
+			if !wroteFlags && flagsLine != "" {
+				lines = append(lines, flagsLine)
+			}
+			wroteFlags = true
+		case line.kind == "previous" && !fuzzy:
+		case strings.HasPrefix(line.kind, "msgstr"):
+			if !wroteMsgstr {
+				lines = append(lines, msgstr...)
+			}
+			wroteMsgstr = true
+		case strings.HasPrefix(line.kind, "msg"):
+			if !wroteFlags && flagsLine != "" {
+				lines = append(lines, flagsLine)
+			}
+			wroteFlags = true
+			lines = append(lines, line.text)
+		default:
+			lines = append(lines, line.text)
+		}
+	}
+	if !wroteMsgstr {
+		lines = append(lines, msgstr...)
+	}
+
+	return lines
+}
+
+// poMsgstrLines formats the translated msgstr lines of an entry, or reports
+// false if a translation is missing. Plural forms of languages with more than
+// two forms that have no translation of their own get the msgid_plural
+// translation, and the entry is reported fuzzy.
+func poMsgstrLines(e *poEntry, translations map[string]string, rule pluralRule) (lines []string, fuzzy, ok bool) {
+	singular, ok := translations[e.hash]
+	if !ok {
+		return nil, false, false
+	}
+	singular = preserveWhitespace(e.msgid, singular)
+
+	if e.msgidPlural == nil {
+		return formatPOString("msgstr", singular), false, true
+	}
+
+	plural, ok := translations[e.pluralHash]
+	if !ok {
+		return nil, false, false
+	}
+	plural = preserveWhitespace(*e.msgidPlural, plural)
+
+	for i := 0; i < rule.count; i++ {
+		s := plural
+		switch {
+		case i == rule.one && rule.count > 1:
+			s = singular
+		case rule.count > 2:
+			if form, ok := translations[e.formHash(i)]; ok {
+				s = preserveWhitespace(*e.msgidPlural, form)
+			} else {
+				fuzzy = true
+			}
+		}
+		lines = append(lines, formatPOString(fmt.Sprintf("msgstr[%d]", i), s)...)
+	}
+	return lines, fuzzy, true
+}
+
+// poDefaultHeader is the header written to files that have none.
+const poDefaultHeader = "MIME-Version: 1.0\nContent-Type: text/plain; charset=UTF-8\nContent-Transfer-Encoding: 8bit\n"
+
+// poHeaderLines returns the msgstr lines of a header updated for targetLang.
+func poHeaderLines(header, targetLang string, rule pluralRule) []string {
+	fields := strings.SplitAfter(header, "\n")
+	if fields[len(fields)-1] == "" {
+		fields = fields[:len(fields)-1]
+	}
+
+	set := map[string]string{
+		"Language":     targetLang,
+		"Plural-Forms": rule.header,
+	}
+	seen := make(map[string]bool)
+	for i, field := range fields {
+		key, value, ok := strings.Cut(field, ":")
+		if !ok {
+			continue
+		}
+		switch {
+		case set[key] != "":
+			fields[i] = key + ": " + set[key] + "\n"
+			seen[key] = true
+		case key == "Content-Type" && strings.Contains(value, "charset=CHARSET"):
+			fields[i] = strings.Replace(field, "charset=CHARSET", "charset=UTF-8", 1)
+		case key == "Content-Transfer-Encoding" && strings.TrimSpace(value) == "ENCODING":
+			fields[i] = key + ": 8bit\n"
+		}
+	}
+	for _, key := range []string{"Language", "Plural-Forms"} {
+		if !seen[key] {
+			fields = append(fields, key+": "+set[key]+"\n")
+		}
+	}
+
+	// Headers always use the multi-line form
+	lines := []string{`msgstr ""`}
+	for _, field := range fields {
+		lines = append(lines, poQuote(field))
+	}
+	return lines
+}
+
+// formatPOString formats a keyword and string as PO lines, splitting multi-line
+// strings after each newline as gettext tools do.
+func formatPOString(keyword, s string) []string {
+	if !strings.Contains(strings.TrimSuffix(s, "\n"), "\n") {
+		return []string{keyword + " " + poQuote(s)}
+	}
+
+	lines := []string{keyword + ` ""`}
+	for _, part := range strings.SplitAfter(s, "\n") {
+		if part != "" {
+			lines = append(lines, poQuote(part))
+		}
+	}
+	return lines
+}
+
+// poEscaper escapes strings for PO files.
+var poEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+
+// poQuote returns s as a quoted PO string.
+func poQuote(s string) string {
+	return `"` + poEscaper.Replace(s) + `"`
+}
+
+// ContentType returns "po".
+func (p *POProcessor) ContentType() string {
+	return "po"
+}
+
+// Verify POProcessor implements ContentProcessor, TargetApplier and TargetNodeProvider
+var (
+	_ ContentProcessor          = (*POProcessor)(nil)
+	_ gotlai.TargetApplier      = (*POProcessor)(nil)
+	_ gotlai.TargetNodeProvider = (*POProcessor)(nil)
+)
@@ -0,0 +1,257 @@
+package processor
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// pluralRule is a gettext Plural-Forms rule.
+type pluralRule struct {
+	header string // Plural-Forms header value
+	count  int    // nplurals
+	one    int    // Form used for n == 1
+}
+
+var (
+	pluralNone       = pluralRule{"nplurals=1; plural=0;", 1, 0}
+	pluralNotOne     = pluralRule{"nplurals=2; plural=(n != 1);", 2, 0}
+	pluralAboveOne   = pluralRule{"nplurals=2; plural=(n > 1);", 2, 0}
+	pluralEastSlavic = pluralRule{"nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);", 3, 0}
+	pluralWestSlavic = pluralRule{"nplurals=3; plural=(n==1) ? 0 : (n>=2 && n<=4) ? 1 : 2;", 3, 0}
+)
+
+// pluralRules maps language codes to gettext plural rules. Regional codes such
+// as "pt_BR" take precedence over their base language.
+var pluralRules = map[string]pluralRule{
+	"ja": pluralNone, "zh": pluralNone, "ko": pluralNone, "vi": pluralNone,
+	"th": pluralNone, "id": pluralNone, "ms": pluralNone, "lo": pluralNone,
+	"km": pluralNone, "my": pluralNone,
+
+	"fr": pluralAboveOne, "pt_BR": pluralAboveOne, "fa": pluralAboveOne,
+	"hy": pluralAboveOne, "tr": pluralAboveOne,
+
+	"ru": pluralEastSlavic, "uk": pluralEastSlavic, "be": pluralEastSlavic,
+	"sr": pluralEastSlavic, "hr": pluralEastSlavic, "bs": pluralEastSlavic,
+
+	"cs": pluralWestSlavic, "sk": pluralWestSlavic,
+
+	"pl": {"nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);", 3, 0},
+	"lt": {"nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && (n%100<10 || n%100>=20) ? 1 : 2);", 3, 0},
+	"lv": {"nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n != 0 ? 1 : 2);", 3, 0},
+	"ro": {"nplurals=3; plural=(n==1 ? 0 : (n==0 || (n%100 > 0 && n%100 < 20)) ? 1 : 2);", 3, 0},
+	"sl": {"nplurals=4; plural=(n%100==1 ? 0 : n%100==2 ? 1 : n%100==3 || n%100==4 ? 2 : 3);", 4, 0},
+	"cy": {"nplurals=4; plural=(n==1) ? 0 : (n==2) ? 1 : (n != 8 && n != 11) ? 2 : 3;", 4, 0},
+	"ga": {"nplurals=5; plural=(n==1 ? 0 : n==2 ? 1 : n<7 ? 2 : n<11 ? 3 : 4);", 5, 0},
+	"ar": {"nplurals=6; plural=(n==0 ? 0 : n==1 ? 1 : n==2 ? 2 : n%100>=3 && n%100<=10 ? 3 : n%100>=11 ? 4 : 5);", 6, 1},
+}
+
+// pluralRuleFor returns the plural rule for a language code such as "ru",
+// "pt_BR" or "pt-BR", defaulting to the two-form (n != 1) rule.
+func pluralRuleFor(lang string) pluralRule {
+	lang = strings.ReplaceAll(lang, "-", "_")
+	if rule, ok := pluralRules[lang]; ok {
+		return rule
+	}
+	base, _, _ := strings.Cut(lang, "_")
+	if rule, ok := pluralRules[strings.ToLower(base)]; ok {
+		return rule
+	}
+	return pluralNotOne
+}
+
+// poPluralExprPattern extracts the plural expression from a Plural-Forms header.
+var poPluralExprPattern = regexp.MustCompile(`plural\s*=\s*([^;]+)`)
+
+// samples returns up to six counts that select form, e.g. "2, 3, 4, 22, 23, 24".
+func (r pluralRule) samples(form int) string {
+	m := poPluralExprPattern.FindStringSubmatch(r.header)
+	if m == nil {
+		return ""
+	}
+	eval, err := parsePluralExpr(m[1])
+	if err != nil {
+		return ""
+	}
+
+	var samples []string
+	for n := 0; n <= 1000 && len(samples) < 6; n++ {
+		if eval(n) == form {
+			samples = append(samples, strconv.Itoa(n))
+		}
+	}
+	return strings.Join(samples, ", ")
+}
+
+// pluralExpr evaluates a gettext plural expression for the count n.
+type pluralExpr func(n int) int
+
+// parsePluralExpr compiles a gettext plural expression, the C subset used in
+// Plural-Forms headers: n, integers, ?:, ||, &&, comparisons, arithmetic and !.
+func parsePluralExpr(s string) (pluralExpr, error) {
+	p := &pluralParser{src: s}
+	expr := p.ternary()
+	p.skipSpace()
+	if p.err == nil && p.pos < len(p.src) {
+		p.fail()
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	return expr, nil
+}
+
+// pluralParser is a recursive descent parser for plural expressions.
+type pluralParser struct {
+	src string
+	pos int
+	err error
+}
+
+func (p *pluralParser) fail() {
+	if p.err == nil {
+		p.err = fmt.Errorf("invalid plural expression %q at offset %d", p.src, p.pos)
+	}
+}
+
+func (p *pluralParser) skipSpace() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// accept consumes op if it comes next.
+func (p *pluralParser) accept(op string) bool {
+	p.skipSpace()
+	if !strings.HasPrefix(p.src[p.pos:], op) {
+		return false
+	}
+	// "<" and ">" must not take the first character of "<=" and ">="
+	if len(op) == 1 && strings.Contains("<>!", op) && strings.HasPrefix(p.src[p.pos+1:], "=") {
+		return false
+	}
+	p.pos += len(op)
+	return true
+}
+
+func (p *pluralParser) ternary() pluralExpr {
+	cond := p.binary(0)
+	if !p.accept("?") {
+		return cond
+	}
+	then := p.ternary()
+	if !p.accept(":") {
+		p.fail()
+	}
+	otherwise := p.ternary()
+	return func(n int) int {
+		if cond(n) != 0 {
+			return then(n)
+		}
+		return otherwise(n)
+	}
+}
+
+// pluralOperators lists binary operators by increasing precedence.
+var pluralOperators = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<=", ">=", "<", ">"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *pluralParser) binary(level int) pluralExpr {
+	if level == len(pluralOperators) {
+		return p.unary()
+	}
+	left := p.binary(level + 1)
+	for {
+		op := ""
+		for _, candidate := range pluralOperators[level] {
+			if p.accept(candidate) {
+				op = candidate
+				break
+			}
+		}
+		if op == "" {
+			return left
+		}
+		l, r := left, p.binary(level+1)
+		left = func(n int) int { return applyPluralOperator(op, l(n), r(n)) }
+	}
+}
+
+func (p *pluralParser) unary() pluralExpr {
+	if p.accept("!") {
+		operand := p.unary()
+		return func(n int) int { return boolInt(operand(n) == 0) }
+	}
+
+	p.skipSpace()
+	switch {
+	case p.accept("("):
+		expr := p.ternary()
+		if !p.accept(")") {
+			p.fail()
+		}
+		return expr
+	case p.accept("n"):
+		return func(n int) int { return n }
+	}
+
+	start := p.pos
+	for p.pos < len(p.src) && '0' <= p.src[p.pos] && p.src[p.pos] <= '9' {
+		p.pos++
+	}
+	value, err := strconv.Atoi(p.src[start:p.pos])
+	if err != nil {
+		p.fail()
+	}
+	return func(int) int { return value }
+}
+
+// applyPluralOperator applies a binary operator; division by zero yields 0.
+func applyPluralOperator(op string, a, b int) int {
+	switch op {
+	case "||":
+		return boolInt(a != 0 || b != 0)
+	case "&&":
+		return boolInt(a != 0 && b != 0)
+	case "==":
+		return boolInt(a == b)
+	case "!=":
+		return boolInt(a != b)
+	case "<":
+		return boolInt(a < b)
+	case "<=":
+		return boolInt(a <= b)
+	case ">":
+		return boolInt(a > b)
+	case ">=":
+		return boolInt(a >= b)
+	case "+":
+		return a + b
+	case "-":
+		return a - b
+	case "*":
+		return a * b
+	}
+	if b == 0 {
+		return 0
+	}
+	if op == "/" {
+		return a / b
+	}
+	return a % b
+}
+
+// boolInt converts a condition to C's 0 or 1.
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
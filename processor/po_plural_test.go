@@ -0,0 +1,60 @@
+package processor
+
+import "testing"
+
+func TestPluralRuleFor(t *testing.T) {
+	tests := []struct {
+		lang  string
+		count int
+		one   int
+	}{
+		{"en_US", 2, 0},
+		{"pt-BR", 2, 0},
+		{"pt_PT", 2, 0},
+		{"ru", 3, 0},
+		{"zh_CN", 1, 0},
+		{"ar_SA", 6, 1},
+		{"xx", 2, 0},
+	}
+
+	for _, tt := range tests {
+		rule := pluralRuleFor(tt.lang)
+		if rule.count != tt.count || rule.one != tt.one {
+			t.Errorf("pluralRuleFor(%q) = %+v, want %d forms with one at %d", tt.lang, rule, tt.count, tt.one)
+		}
+	}
+
+	if pluralRuleFor("pt_BR").header != "nplurals=2; plural=(n > 1);" {
+		t.Errorf("Expected pt_BR to use the French-style rule, got %q", pluralRuleFor("pt_BR").header)
+	}
+}
+
+func TestParsePluralExpr(t *testing.T) {
+	tests := []struct {
+		rule     string
+		expected []int // Forms for n = 0..12, 21, 101, 111
+	}{
+		{"ru", []int{2, 0, 1, 1, 1, 2, 2, 2, 2, 2, 2, 2, 2, 0, 0, 2}},
+		{"pl", []int{2, 0, 1, 1, 1, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2}},
+		{"ar", []int{0, 1, 2, 3, 3, 3, 3, 3, 3, 3, 3, 4, 4, 4, 5, 4}},
+		{"cy", []int{2, 0, 1, 2, 2, 2, 2, 2, 3, 2, 2, 3, 2, 2, 2, 2}},
+	}
+
+	counts := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 21, 101, 111}
+	for _, tt := range tests {
+		m := poPluralExprPattern.FindStringSubmatch(pluralRuleFor(tt.rule).header)
+		eval, err := parsePluralExpr(m[1])
+		if err != nil {
+			t.Fatalf("%s: %v", tt.rule, err)
+		}
+		for i, n := range counts {
+			if got := eval(n); got != tt.expected[i] {
+				t.Errorf("%s: plural(%d) = %d, want %d", tt.rule, n, got, tt.expected[i])
+			}
+		}
+	}
+
+	if _, err := parsePluralExpr("n %% 2"); err == nil {
+		t.Error("Expected an error for an invalid expression")
+	}
+}
@@ -0,0 +1,274 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+)
+
+const poTemplate = `# Translations for the app.
+#, fuzzy
+msgid ""
+msgstr ""
+"Project-Id-Version: app 1.0\n"
+"Language: \n"
+"Content-Type: text/plain; charset=CHARSET\n"
+"Content-Transfer-Encoding: ENCODING\n"
+
+#. Shown on the login button
+#: login.go:12
+msgctxt "button"
+msgid "Open"
+msgstr ""
+
+msgctxt "state"
+msgid "Open"
+msgstr "Abierto"
+
+#, fuzzy, c-format
+#| msgid "Hello %s"
+msgid "Hello, %s!"
+msgstr "Hola %s"
+
+#, c-format
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] ""
+msgstr[1] ""
+
+msgid ""
+"Line one\n"
+"Line two"
+msgstr ""
+
+#~ msgid "Old"
+#~ msgstr "Viejo"
+`
+
+func TestPOProcessor_Extract(t *testing.T) {
+	p := NewPOProcessor()
+
+	_, nodes, err := p.Extract(poTemplate)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	var texts []string
+	for _, n := range nodes {
+		texts = append(texts, n.Text)
+	}
+
+	expected := []string{"Open", "Hello, %s!", "%d file", "%d files", "Line one\nLine two"}
+	if strings.Join(texts, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, texts)
+	}
+
+	if nodes[0].Context != "gettext message | context: button | Shown on the login button" {
+		t.Errorf("Unexpected context %q", nodes[0].Context)
+	}
+	if nodes[0].Metadata["msgctxt"] != "button" || nodes[0].NodeType != "po_message" {
+		t.Errorf("Expected msgctxt metadata, got %v", nodes[0].Metadata)
+	}
+	if !strings.Contains(nodes[1].Context, "format directives") {
+		t.Errorf("Expected format directive hint, got %q", nodes[1].Context)
+	}
+	if nodes[3].Metadata["plural"] != "true" || !strings.Contains(nodes[3].Context, "plural form") {
+		t.Errorf("Expected plural node, got %+v", nodes[3])
+	}
+}
+
+func TestPOProcessor_ApplyTarget(t *testing.T) {
+	p := NewPOProcessor()
+
+	parsed, nodes, err := p.Extract(poTemplate)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	nodes = append(nodes, p.TargetNodes(parsed, nodes, "ru_RU")...)
+	result, err := p.ApplyTarget(parsed, nodes, bracketAll(nodes), "ru_RU")
+	if err != nil {
+		t.Fatalf("ApplyTarget failed: %v", err)
+	}
+
+	expected := `# Translations for the app.
+msgid ""
+msgstr ""
+"Project-Id-Version: app 1.0\n"
+"Language: ru_RU\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Content-Transfer-Encoding: 8bit\n"
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+#. Shown on the login button
+#: login.go:12
+msgctxt "button"
+msgid "Open"
+msgstr "[Open]"
+
+msgctxt "state"
+msgid "Open"
+msgstr "Abierto"
+
+#, c-format
+msgid "Hello, %s!"
+msgstr "[Hello, %s!]"
+
+#, c-format
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "[%d file]"
+msgstr[1] "[%d files]"
+msgstr[2] "[%d files]"
+
+msgid ""
+"Line one\n"
+"Line two"
+msgstr ""
+"[Line one\n"
+"Line two]"
+
+#~ msgid "Old"
+#~ msgstr "Viejo"
+`
+	if result != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, result)
+	}
+}
+
+func TestPOProcessor_PluralForms(t *testing.T) {
+	src := "msgid \"%d day\"\nmsgid_plural \"%d days\"\nmsgstr[0] \"\"\nmsgstr[1] \"\"\n"
+	p := NewPOProcessor()
+
+	tests := []struct {
+		lang     string
+		expected []string
+	}{
+		{"ja_JP", []string{`msgstr[0] "[%d days]"`}},
+		{"fr_FR", []string{`msgstr[0] "[%d day]"`, `msgstr[1] "[%d days]"`}},
+		{"ar_SA", []string{`msgstr[0] "[%d days]"`, `msgstr[1] "[%d day]"`, `msgstr[5] "[%d days]"`}},
+	}
+
+	for _, tt := range tests {
+		parsed, nodes, err := p.Extract(src)
+		if err != nil {
+			t.Fatalf("Extract failed: %v", err)
+		}
+		nodes = append(nodes, p.TargetNodes(parsed, nodes, tt.lang)...)
+		result, err := p.ApplyTarget(parsed, nodes, bracketAll(nodes), tt.lang)
+		if err != nil {
+			t.Fatalf("ApplyTarget failed: %v", err)
+		}
+		if strings.Contains(result, "fuzzy") {
+			t.Errorf("%s: unexpected fuzzy flag in:\n%s", tt.lang, result)
+		}
+		for _, s := range tt.expected {
+			if !strings.Contains(result, s) {
+				t.Errorf("%s: expected %q in:\n%s", tt.lang, s, result)
+			}
+		}
+		if !strings.HasPrefix(result, "msgid \"\"\nmsgstr \"\"\n") || !strings.Contains(result, `"Language: `+tt.lang+`\n"`) {
+			t.Errorf("%s: expected a generated header, got:\n%s", tt.lang, result)
+		}
+	}
+}
+
+func TestPOProcessor_TargetPluralForms(t *testing.T) {
+	src := "msgid \"%d file\"\nmsgid_plural \"%d files\"\nmsgstr[0] \"\"\nmsgstr[1] \"\"\n"
+	p := NewPOProcessor()
+
+	parsed, nodes, err := p.Extract(src)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	extra := p.TargetNodes(parsed, nodes, "ru")
+	if len(extra) != 2 {
+		t.Fatalf("Expected nodes for the few and many forms, got %d", len(extra))
+	}
+	if extra[0].ID != "node-2" || extra[0].Metadata["plural_form"] != "1" || !strings.Contains(extra[0].Context, "counts such as 2, 3, 4, 22, 23, 24") {
+		t.Errorf("Unexpected few node %+v", extra[0])
+	}
+	if extra[1].Metadata["plural_form"] != "2" || !strings.Contains(extra[1].Context, "counts such as 0, 5, 6, 7, 8, 9") {
+		t.Errorf("Unexpected many node %+v", extra[1])
+	}
+	if p.TargetNodes(parsed, nodes, "fr") != nil {
+		t.Error("Expected no extra nodes for a two-form language")
+	}
+
+	translations := map[string]string{
+		nodes[0].Hash: "%d файл",
+		nodes[1].Hash: "%d файлов",
+		extra[0].Hash: "%d файла",
+		extra[1].Hash: "%d файлов",
+	}
+	result, err := p.ApplyTarget(parsed, append(nodes, extra...), translations, "ru")
+	if err != nil {
+		t.Fatalf("ApplyTarget failed: %v", err)
+	}
+	expected := "msgid \"%d file\"\nmsgid_plural \"%d files\"\nmsgstr[0] \"%d файл\"\nmsgstr[1] \"%d файла\"\nmsgstr[2] \"%d файлов\"\n"
+	if !strings.HasSuffix(result, expected) {
+		t.Errorf("Expected %q in:\n%s", expected, result)
+	}
+
+	// Without their own translations the forms fall back to msgid_plural and stay fuzzy
+	delete(translations, extra[0].Hash)
+	result, err = p.ApplyTarget(parsed, nodes, translations, "ru")
+	if err != nil {
+		t.Fatalf("ApplyTarget failed: %v", err)
+	}
+	expected = "#, fuzzy\nmsgid \"%d file\"\nmsgid_plural \"%d files\"\nmsgstr[0] \"%d файл\"\nmsgstr[1] \"%d файлов\"\nmsgstr[2] \"%d файлов\"\n"
+	if !strings.HasSuffix(result, expected) {
+		t.Errorf("Expected %q in:\n%s", expected, result)
+	}
+}
+
+func TestPOProcessor_MarkFuzzy(t *testing.T) {
+	p := NewPOProcessor(WithMarkFuzzy())
+
+	parsed, nodes, err := p.Extract("#: a.go:1\nmsgid \"Save\"\nmsgstr \"\"\n")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	result, err := p.Apply(parsed, nodes, bracketAll(nodes))
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	expected := "#: a.go:1\n#, fuzzy\nmsgid \"Save\"\nmsgstr \"[Save]\"\n"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestPOProcessor_MissingTranslation(t *testing.T) {
+	p := NewPOProcessor()
+	src := "msgid \"One\"\nmsgid_plural \"Many\"\nmsgstr[0] \"\"\nmsgstr[1] \"\"\n"
+
+	parsed, nodes, err := p.Extract(src)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	result, err := p.Apply(parsed, nodes, map[string]string{nodes[0].Hash: "Uno"})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if result != src {
+		t.Errorf("Expected entry kept without its plural translation, got %q", result)
+	}
+}
+
+func TestPOProcessor_InvalidPO(t *testing.T) {
+	p := NewPOProcessor()
+
+	for _, input := range []string{"msgid \"unterminated\n", "\"orphan\"\n", "bogus line\n"} {
+		if _, _, err := p.Extract(input); err == nil {
+			t.Errorf("Expected error for %q", input)
+		}
+	}
+}
+
+func TestPOProcessor_ContentType(t *testing.T) {
+	p := NewPOProcessor()
+	if p.ContentType() != "po" {
+		t.Errorf("Expected 'po', got %q", p.ContentType())
+	}
+}
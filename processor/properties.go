@@ -0,0 +1,322 @@
+package processor
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/ZaguanLabs/gotlai"
+)
+
+// messageFormatPattern matches java.text.MessageFormat arguments such as {0} and {1,number}.
+var messageFormatPattern = regexp.MustCompile(`\{\d+(?:\s*,[^{}]*)?\}`)
+
+// PropertiesProcessor extracts and applies translations to Java .properties
+// resource bundles. It reads key=value, key: value and key value entries,
+// line continuations and \uXXXX escapes; the # and ! comments above an entry
+// become node context. Values that use MessageFormat arguments ({0}) have their
+// doubled apostrophes decoded for translation and re-doubled on output.
+// Translated values are written on one line with non-ASCII characters escaped
+// as \uXXXX, which every Java version reads; keys and comments are unchanged.
+type PropertiesProcessor struct {
+	utf8 bool
+}
+
+// PropertiesProcessorOption configures the properties processor.
+type PropertiesProcessorOption func(*PropertiesProcessor)
+
+// WithUTF8Properties writes non-ASCII characters as UTF-8 instead of \uXXXX
+// escapes, for bundles read as UTF-8 (Java 9+ ResourceBundle, Spring).
+func WithUTF8Properties() PropertiesProcessorOption {
+	return func(p *PropertiesProcessor) {
+		p.utf8 = true
+	}
+}
+
+// NewPropertiesProcessor creates a new properties processor.
+func NewPropertiesProcessor(opts ...PropertiesProcessorOption) *PropertiesProcessor {
+	p := &PropertiesProcessor{}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// property is an entry of a .properties file.
+type property struct {
+	key           string
+	comment       string
+	text          string // Decoded value
+	hash          string
+	messageFormat bool
+	start, end    int // Byte range of the raw value, including continuations
+}
+
+// parsedProperties holds the properties source and its entries.
+type parsedProperties struct {
+	src        string
+	properties []*property
+}
+
+// Extract parses a .properties file and extracts its values.
+func (p *PropertiesProcessor) Extract(content string) (interface{}, []gotlai.TextNode, error) {
+	pp := parseProperties(content)
+
+	var nodes []gotlai.TextNode
+	seenHashes := make(map[string]bool)
+	for _, prop := range pp.properties {
+		if prop.hash == "" || seenHashes[prop.hash] {
+			continue
+		}
+		seenHashes[prop.hash] = true
+
+		parts := []string{fmt.Sprintf("Java resource bundle message %q", prop.key)}
+		if prop.comment != "" {
+			parts = append(parts, prop.comment)
+		}
+		parts = append(parts, "keep placeholders such as {0} and %s unchanged")
+
+		nodes = append(nodes, gotlai.TextNode{
+			ID:       fmt.Sprintf("node-%d", len(nodes)),
+			Text:     strings.TrimSpace(prop.text),
+			Hash:     prop.hash,
+			NodeType: "properties_value",
+			Context:  strings.Join(parts, " | "),
+			Metadata: map[string]string{
+				"key": prop.key,
+			},
+		})
+	}
+
+	return pp, nodes, nil
+}
+
+// parseProperties scans the logical lines of a .properties file. It accepts
+// any input, as java.util.Properties does.
+func parseProperties(src string) *parsedProperties {
+	pp := &parsedProperties{src: src}
+	var comments []string
+
+	pos := 0
+	for pos < len(src) {
+		// A logical line ends at a newline not escaped by an odd number of backslashes
+		end := pos
+		for {
+			nl := strings.IndexByte(src[end:], '\n')
+			if nl < 0 {
+				end = len(src)
+				break
+			}
+			end += nl
+			line := strings.TrimSuffix(src[pos:end], "\r")
+			if trailingBackslashes(line)%2 == 0 || isPropertiesComment(src[pos:end]) {
+				break
+			}
+			end++
+		}
+		next := min(end+1, len(src))
+
+		line := strings.TrimLeft(src[pos:end], " \t\f")
+		switch {
+		case strings.TrimSpace(line) == "":
+			comments = nil
+		case isPropertiesComment(line):
+			comments = append(comments, strings.TrimSpace(strings.TrimLeft(line, "#!")))
+		default:
+			lineStart := end - len(line)
+			lineEnd := len(strings.TrimRight(src[:end], "\r"))
+			prop := parseProperty(src, lineStart, lineEnd)
+			prop.comment = strings.Join(comments, " ")
+			if hasLetter(messageFormatPattern.ReplaceAllString(prop.text, "")) {
+				prop.hash = gotlai.HashText(strings.TrimSpace(prop.text))
+			}
+			pp.properties = append(pp.properties, prop)
+			comments = nil
+		}
+		pos = next
+	}
+
+	return pp
+}
+
+// isPropertiesComment reports whether a line, without leading whitespace, is a comment.
+func isPropertiesComment(line string) bool {
+	line = strings.TrimLeft(line, " \t\f")
+	return strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!")
+}
+
+// trailingBackslashes counts the backslashes at the end of s.
+func trailingBackslashes(s string) int {
+	n := 0
+	for n < len(s) && s[len(s)-1-n] == '\\' {
+		n++
+	}
+	return n
+}
+
+// parseProperty splits the logical line src[start:end] into key and value.
+func parseProperty(src string, start, end int) *property {
+	i := start
+	for i < end {
+		c := src[i]
+		if c == '\\' {
+			i += 2
+			continue
+		}
+		if c == '=' || c == ':' || c == ' ' || c == '\t' || c == '\f' {
+			break
+		}
+		i++
+	}
+	i = min(i, end)
+	prop := &property{key: propertiesUnescape(src[start:i])}
+
+	// Whitespace, then at most one separator, then whitespace
+	for i < end && strings.IndexByte(" \t\f", src[i]) >= 0 {
+		i++
+	}
+	if i < end && (src[i] == '=' || src[i] == ':') {
+		i++
+	}
+	for i < end && strings.IndexByte(" \t\f", src[i]) >= 0 {
+		i++
+	}
+
+	prop.start, prop.end = i, end
+	prop.text = propertiesUnescape(src[i:end])
+	if messageFormatPattern.MatchString(prop.text) {
+		prop.messageFormat = true
+		prop.text = strings.ReplaceAll(prop.text, "''", "'")
+	}
+	return prop
+}
+
+// propertiesUnescape decodes escapes and line continuations of a key or value.
+func propertiesUnescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var units []uint16
+	var b strings.Builder
+	flush := func() {
+		if len(units) > 0 {
+			b.WriteString(string(utf16.Decode(units)))
+			units = nil
+		}
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			flush()
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch c := s[i]; c {
+		case '\r', '\n':
+			// Continuation: skip the line break and the next line's indentation
+			flush()
+			if c == '\r' && i+1 < len(s) && s[i+1] == '\n' {
+				i++
+			}
+			for i+1 < len(s) && strings.IndexByte(" \t\f", s[i+1]) >= 0 {
+				i++
+			}
+		case 'u':
+			if i+5 <= len(s) {
+				if u, err := strconv.ParseUint(s[i+1:i+5], 16, 16); err == nil {
+					units = append(units, uint16(u))
+					i += 4
+					continue
+				}
+			}
+			flush()
+			b.WriteByte('u')
+		default:
+			flush()
+			switch c {
+			case 't':
+				b.WriteByte('\t')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 'f':
+				b.WriteByte('\f')
+			default:
+				b.WriteByte(c)
+			}
+		}
+	}
+	flush()
+	return b.String()
+}
+
+// escapeValue encodes a value on a single line.
+func (p *PropertiesProcessor) escapeValue(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch {
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == '\f':
+			b.WriteString(`\f`)
+		case r == ' ' && i == 0:
+			b.WriteString(`\ `)
+		case r > 0x7e && !p.utf8:
+			for _, u := range utf16.Encode([]rune{r}) {
+				fmt.Fprintf(&b, `\u%04X`, u)
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Apply splices translated values into the properties source.
+func (p *PropertiesProcessor) Apply(parsed interface{}, nodes []gotlai.TextNode, translations map[string]string) (string, error) {
+	pp, ok := parsed.(*parsedProperties)
+	if !ok {
+		return "", &gotlai.ProcessorError{
+			Message:     "invalid parsed content type",
+			ContentType: "properties",
+		}
+	}
+
+	var b strings.Builder
+	pos := 0
+	for _, prop := range pp.properties {
+		translated, ok := translations[prop.hash]
+		if prop.hash == "" || !ok {
+			continue
+		}
+		value := preserveWhitespace(prop.text, translated)
+		if prop.messageFormat {
+			value = strings.ReplaceAll(value, "'", "''")
+		}
+		b.WriteString(pp.src[pos:prop.start])
+		b.WriteString(p.escapeValue(value))
+		pos = prop.end
+	}
+	b.WriteString(pp.src[pos:])
+
+	return b.String(), nil
+}
+
+// ContentType returns "properties".
+func (p *PropertiesProcessor) ContentType() string {
+	return "properties"
+}
+
+// Verify PropertiesProcessor implements ContentProcessor
+var _ ContentProcessor = (*PropertiesProcessor)(nil)
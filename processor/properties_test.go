@@ -0,0 +1,125 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+)
+
+const propertiesDoc = `# Application messages
+app.title = Notes
+
+! Shown on the home screen
+greeting: Hello {0}, you can''t undo this
+multi=First part \
+      second part
+caf\u00e9.name=Caf\u00e9
+count {0,number}
+empty=
+`
+
+func TestPropertiesProcessor_Extract(t *testing.T) {
+	p := NewPropertiesProcessor()
+
+	_, nodes, err := p.Extract(propertiesDoc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	var texts []string
+	for _, n := range nodes {
+		texts = append(texts, n.Text)
+	}
+
+	expected := []string{"Notes", "Hello {0}, you can't undo this", "First part second part", "Café"}
+	if strings.Join(texts, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, texts)
+	}
+
+	if !strings.HasPrefix(nodes[1].Context, `Java resource bundle message "greeting" | Shown on the home screen`) {
+		t.Errorf("Unexpected context %q", nodes[1].Context)
+	}
+	if nodes[0].Context != `Java resource bundle message "app.title" | Application messages | keep placeholders such as {0} and %s unchanged` {
+		t.Errorf("Unexpected context %q", nodes[0].Context)
+	}
+	if nodes[3].Metadata["key"] != "café.name" {
+		t.Errorf("Unexpected metadata %v", nodes[3].Metadata)
+	}
+}
+
+func TestPropertiesProcessor_Apply(t *testing.T) {
+	p := NewPropertiesProcessor()
+
+	parsed, nodes, err := p.Extract(propertiesDoc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	translations := map[string]string{
+		nodes[0].Hash: "Notes",
+		nodes[1].Hash: "Bonjour {0}, l'action est définitive",
+		nodes[2].Hash: "Première partie\nseconde partie",
+		nodes[3].Hash: "Café",
+	}
+
+	result, err := p.Apply(parsed, nodes, translations)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	expected := `# Application messages
+app.title = Notes
+
+! Shown on the home screen
+greeting: Bonjour {0}, l''action est d\u00E9finitive
+multi=Premi\u00E8re partie\nseconde partie
+caf\u00e9.name=Caf\u00E9
+count {0,number}
+empty=
+`
+	if result != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, result)
+	}
+}
+
+func TestPropertiesProcessor_UTF8(t *testing.T) {
+	p := NewPropertiesProcessor(WithUTF8Properties())
+
+	parsed, nodes, err := p.Extract("title=Hello\r\n")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	result, err := p.Apply(parsed, nodes, map[string]string{nodes[0].Hash: "Привет 👋"})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if result != "title=Привет 👋\r\n" {
+		t.Errorf("Unexpected result %q", result)
+	}
+}
+
+func TestPropertiesUnescape(t *testing.T) {
+	tests := map[string]string{
+		`a\tb\nc`:         "a\tb\nc",
+		`\uD83D\uDC4B`:    "👋",
+		`back\\slash`:     `back\slash`,
+		"one \\\n   two":  "one two",
+		`key\=with\:seps`: "key=with:seps",
+		`bad\uZZZZ`:       "baduZZZZ",
+	}
+	for input, expected := range tests {
+		if got := propertiesUnescape(input); got != expected {
+			t.Errorf("propertiesUnescape(%q) = %q, want %q", input, got, expected)
+		}
+	}
+
+	if got := NewPropertiesProcessor().escapeValue(" 👋\\"); got != `\ \uD83D\uDC4B\\` {
+		t.Errorf("Unexpected escape %q", got)
+	}
+}
+
+func TestPropertiesProcessor_ContentType(t *testing.T) {
+	if ct := NewPropertiesProcessor().ContentType(); ct != "properties" {
+		t.Errorf("Expected 'properties', got %q", ct)
+	}
+}
@@ -0,0 +1,143 @@
+package processor
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// codeSegment is text flattened with placeholders for source markup that must
+// be kept, such as Markdown code spans and links or XLIFF inline codes. Unlike
+// inlineSegment it refers to source text rather than parsed HTML nodes.
+type codeSegment struct {
+	text   string   // Text with placeholders
+	opens  []string // Source of self-closing placeholder N, or the source before paired content
+	closes []string // Source after the content of paired placeholder N
+	paired []bool
+}
+
+// writeAtom records source that cannot be translated and writes its self-closing placeholder.
+func (seg *codeSegment) writeAtom(b *strings.Builder, source string) {
+	seg.opens = append(seg.opens, source)
+	seg.closes = append(seg.closes, "")
+	seg.paired = append(seg.paired, false)
+	fmt.Fprintf(b, "<x%d/>", len(seg.paired))
+}
+
+// writeOpen records markup around translatable content and writes its opening
+// placeholder. The caller writes the content and the closing placeholder.
+func (seg *codeSegment) writeOpen(b *strings.Builder, open, closing string) int {
+	seg.opens = append(seg.opens, open)
+	seg.closes = append(seg.closes, closing)
+	seg.paired = append(seg.paired, true)
+	id := len(seg.paired)
+	fmt.Fprintf(b, "<x%d>", id)
+	return id
+}
+
+// hasText reports whether a segment has letters outside its placeholders.
+func hasText(seg *codeSegment) bool {
+	return hasLetter(placeholderPattern.ReplaceAllString(seg.text, ""))
+}
+
+// render rebuilds source from parsed segment items. Text is unescaped, then
+// passed through escape when it is not nil.
+func (seg *codeSegment) render(items []segmentItem, escape func(string) string) string {
+	var b strings.Builder
+	var write func([]segmentItem)
+	write = func(items []segmentItem) {
+		for _, item := range items {
+			switch {
+			case item.id == 0:
+				text := html.UnescapeString(item.text)
+				if escape != nil {
+					text = escape(text)
+				}
+				b.WriteString(text)
+			case seg.paired[item.id-1]:
+				b.WriteString(seg.opens[item.id-1])
+				write(item.children)
+				b.WriteString(seg.closes[item.id-1])
+			default:
+				b.WriteString(seg.opens[item.id-1])
+			}
+		}
+	}
+	write(items)
+	return b.String()
+}
+
+// buildXMLSegment flattens XML content into text with placeholders for inline
+// elements. Elements named in paired wrap translatable content; others, and
+// paired elements without text, are kept whole. Character data is passed through
+// unescape when it is not nil.
+func buildXMLSegment(inner string, paired map[string]bool, unescape func(string) string) (*codeSegment, error) {
+	seg := &codeSegment{}
+	var b strings.Builder
+	dec := xml.NewDecoder(strings.NewReader(inner))
+
+	type frame struct {
+		start, openEnd int
+		paired         bool
+		id             int
+		mark           int // Builder length before the element
+		opens          int // len(seg.opens) before the element
+	}
+	var stack []frame
+
+	for {
+		start := int(dec.InputOffset())
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		end := int(dec.InputOffset())
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			f := frame{start: start, openEnd: end, paired: paired[t.Name.Local], mark: b.Len(), opens: len(seg.opens)}
+			if f.paired {
+				f.id = seg.writeOpen(&b, inner[start:end], "")
+			}
+			stack = append(stack, f)
+		case xml.EndElement:
+			f := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if f.paired && f.openEnd < start && hasLetter(placeholderPattern.ReplaceAllString(b.String()[f.mark:], "")) {
+				seg.closes[f.id-1] = inner[start:end]
+				fmt.Fprintf(&b, "</x%d>", f.id)
+				continue
+			}
+
+			// Keep elements without translatable content whole
+			text := b.String()[:f.mark]
+			b.Reset()
+			b.WriteString(text)
+			seg.opens = seg.opens[:f.opens]
+			seg.closes = seg.closes[:f.opens]
+			seg.paired = seg.paired[:f.opens]
+			seg.writeAtom(&b, inner[f.start:end])
+		case xml.CharData:
+			if len(stack) == 0 || stack[len(stack)-1].paired {
+				text := string(t)
+				if unescape != nil {
+					text = unescape(text)
+				}
+				b.WriteString(escapeSegmentText(text))
+			}
+		case xml.Comment, xml.ProcInst:
+			if len(stack) == 0 || stack[len(stack)-1].paired {
+				seg.writeAtom(&b, inner[start:end])
+			}
+		}
+	}
+
+	seg.text = b.String()
+	return seg, nil
+}
@@ -0,0 +1,46 @@
+package processor
+
+import "github.com/ZaguanLabs/gotlai"
+
+// SRTProcessor extracts and applies translations to SubRip (.srt) subtitles.
+// The text lines of each cue are translated as one sentence, with the previous
+// and next cues as context, and the translation is re-wrapped to the maximum
+// line length. Indexes and timestamps are kept byte for byte, and formatting
+// tags such as <i> become placeholders.
+type SRTProcessor struct {
+	opts subtitleOptions
+}
+
+// NewSRTProcessor creates a new SubRip processor.
+func NewSRTProcessor(opts ...SubtitleOption) *SRTProcessor {
+	return &SRTProcessor{opts: newSubtitleOptions(opts)}
+}
+
+// Extract parses SubRip subtitles and extracts their cue text.
+func (p *SRTProcessor) Extract(content string) (interface{}, []gotlai.TextNode, error) {
+	return extractSubtitles(p.opts, content, false)
+}
+
+// Apply writes translated cue text into the subtitles.
+func (p *SRTProcessor) Apply(parsed interface{}, nodes []gotlai.TextNode, translations map[string]string) (string, error) {
+	return applySubtitles(p.opts, parsed, translations, false)
+}
+
+// ValidateTranslation checks that a translation keeps every tag and cue break.
+func (p *SRTProcessor) ValidateTranslation(node gotlai.TextNode, translated string) error {
+	if node.NodeType != "subtitle_cue" {
+		return nil
+	}
+	return validateSubtitle(node, translated)
+}
+
+// ContentType returns "srt".
+func (p *SRTProcessor) ContentType() string {
+	return "srt"
+}
+
+// Verify SRTProcessor implements ContentProcessor and TranslationValidator
+var (
+	_ ContentProcessor            = (*SRTProcessor)(nil)
+	_ gotlai.TranslationValidator = (*SRTProcessor)(nil)
+)
@@ -0,0 +1,117 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+)
+
+const srtDoc = `1
+00:00:01,000 --> 00:00:03,500
+Welcome to the
+<i>product tour</i>.
+
+2
+00:00:04,000 --> 00:00:06,000
+- Ready?
+- Let's go!
+
+3
+00:00:06,500 --> 00:00:08,000
+♪ ♪
+`
+
+func TestSRTProcessor_Extract(t *testing.T) {
+	p := NewSRTProcessor()
+
+	_, nodes, err := p.Extract(srtDoc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	var texts []string
+	for _, n := range nodes {
+		texts = append(texts, n.Text)
+	}
+
+	expected := []string{"Welcome to the <x1/>product tour<x2/>.", "- Ready?<x1/>- Let's go!"}
+	if strings.Join(texts, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, texts)
+	}
+
+	if !strings.Contains(nodes[0].Context, `next cue: "- Ready? - Let's go!"`) {
+		t.Errorf("Expected next cue in context, got %q", nodes[0].Context)
+	}
+	if !strings.Contains(nodes[1].Context, `previous cue: "Welcome to the product tour."`) {
+		t.Errorf("Expected previous cue in context, got %q", nodes[1].Context)
+	}
+	if nodes[1].Metadata["cue"] != "2" {
+		t.Errorf("Unexpected metadata %v", nodes[1].Metadata)
+	}
+}
+
+func TestSRTProcessor_Apply(t *testing.T) {
+	p := NewSRTProcessor(WithMaxLineLength(20))
+
+	parsed, nodes, err := p.Extract(srtDoc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	translations := map[string]string{
+		nodes[0].Hash: "Bienvenue dans la <x1/>visite du produit<x2/>.",
+		nodes[1].Hash: "- Prêts ?<x1/>- C'est parti !",
+	}
+
+	result, err := p.Apply(parsed, nodes, translations)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	expected := `1
+00:00:01,000 --> 00:00:03,500
+Bienvenue dans la
+<i>visite du produit</i>.
+
+2
+00:00:04,000 --> 00:00:06,000
+- Prêts ?
+- C'est parti !
+
+3
+00:00:06,500 --> 00:00:08,000
+♪ ♪
+`
+	if result != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, result)
+	}
+}
+
+func TestSRTProcessor_CRLF(t *testing.T) {
+	p := NewSRTProcessor(WithMaxLineLength(10))
+
+	src := "1\r\n00:00:01,000 --> 00:00:02,000\r\nHello world\r\n"
+	parsed, nodes, err := p.Extract(src)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	result, err := p.Apply(parsed, nodes, map[string]string{nodes[0].Hash: "Hola mundo entero"})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if result != "1\r\n00:00:01,000 --> 00:00:02,000\r\nHola mundo\r\nentero\r\n" {
+		t.Errorf("Unexpected result %q", result)
+	}
+}
+
+func TestSRTProcessor_InvalidInput(t *testing.T) {
+	if _, _, err := NewSRTProcessor().Extract("just some text"); err == nil {
+		t.Error("Expected error for input without cues")
+	}
+}
+
+func TestSRTProcessor_ContentType(t *testing.T) {
+	if ct := NewSRTProcessor().ContentType(); ct != "srt" {
+		t.Errorf("Expected 'srt', got %q", ct)
+	}
+}
@@ -0,0 +1,437 @@
+package processor
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ZaguanLabs/gotlai"
+)
+
+// subtitleTagPattern matches formatting tags in cue text, such as <i>,
+// <font color="red">, <v Bob> and <00:01.500>.
+var subtitleTagPattern = regexp.MustCompile(`<[^<>\n]*>`)
+
+// sentenceEndPattern matches text that ends a sentence, with optional closing quotes.
+var sentenceEndPattern = regexp.MustCompile(`[.!?…。！？][)"'”’»」』]*$`)
+
+// DefaultMaxLineLength is the default maximum number of characters per subtitle line.
+const DefaultMaxLineLength = 42
+
+// maxMergedCues limits how many consecutive cues WithSentenceMerging joins.
+const maxMergedCues = 4
+
+// cueBreak is the source of the placeholder between merged cues.
+const cueBreak = "\x00"
+
+// SubtitleOption configures the SRT and WebVTT processors.
+type SubtitleOption func(*subtitleOptions)
+
+// subtitleOptions holds settings shared by the subtitle processors.
+type subtitleOptions struct {
+	maxLineLength  int
+	mergeSentences bool
+}
+
+// WithMaxLineLength sets the maximum number of characters per translated line
+// (DefaultMaxLineLength by default). Lines are wrapped at spaces into lines of
+// similar length; n <= 0 writes each cue on one line.
+func WithMaxLineLength(n int) SubtitleOption {
+	return func(o *subtitleOptions) {
+		o.maxLineLength = n
+	}
+}
+
+// WithSentenceMerging translates a sentence that spans consecutive cues as one
+// node, with self-closing placeholders marking the cue breaks; each cue gets
+// the part of the translation between its breaks. Up to four cues are merged.
+func WithSentenceMerging() SubtitleOption {
+	return func(o *subtitleOptions) {
+		o.mergeSentences = true
+	}
+}
+
+// newSubtitleOptions applies opts to the defaults.
+func newSubtitleOptions(opts []SubtitleOption) subtitleOptions {
+	o := subtitleOptions{maxLineLength: DefaultMaxLineLength}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// subtitleCue is the text of a cue. Indexes, identifiers and timings are never touched.
+type subtitleCue struct {
+	start, end int // Byte range of the text lines, without the final line break
+	lines      []string
+	plain      string // Text without tags, on one line
+}
+
+// subtitleGroup is one cue, or consecutive cues merged into one sentence.
+type subtitleGroup struct {
+	first  int // Index of the first cue
+	cues   []*subtitleCue
+	seg    *codeSegment
+	breaks []int // Cue-break placeholder IDs
+	hash   string
+}
+
+// parsedSubtitles holds a subtitle source and its translatable cue groups.
+type parsedSubtitles struct {
+	src     string
+	newline string
+	groups  []*subtitleGroup
+}
+
+// parseSubtitleCues splits src into blank-line separated blocks and returns the
+// text of those with a timing line. In WebVTT, the header and NOTE, STYLE and
+// REGION blocks are skipped.
+func parseSubtitleCues(src string, vtt bool) ([]*subtitleCue, error) {
+	if vtt && !strings.HasPrefix(strings.TrimPrefix(src, "\ufeff"), "WEBVTT") {
+		return nil, fmt.Errorf("missing WEBVTT header")
+	}
+
+	type line struct {
+		text       string
+		start, end int
+	}
+
+	var cues []*subtitleCue
+	var block []line
+	flush := func() {
+		defer func() { block = nil }()
+
+		timing := -1
+		for i := 0; i < len(block) && i < 2; i++ {
+			if strings.Contains(block[i].text, "-->") {
+				timing = i
+				break
+			}
+		}
+		if timing < 0 || timing == len(block)-1 {
+			return
+		}
+
+		text := block[timing+1:]
+		cue := &subtitleCue{start: text[0].start, end: text[len(text)-1].end}
+		for _, l := range text {
+			cue.lines = append(cue.lines, strings.TrimSpace(l.text))
+		}
+		cue.plain = strings.Join(strings.Fields(html.UnescapeString(subtitleTagPattern.ReplaceAllString(strings.Join(cue.lines, " "), ""))), " ")
+		cues = append(cues, cue)
+	}
+
+	pos := 0
+	for pos < len(src) {
+		end := strings.IndexByte(src[pos:], '\n')
+		next := pos + end + 1
+		if end < 0 {
+			end = len(src) - pos
+			next = len(src)
+		}
+		text := strings.TrimSuffix(src[pos:pos+end], "\r")
+
+		if strings.TrimSpace(text) == "" {
+			flush()
+		} else {
+			block = append(block, line{text: text, start: pos, end: pos + len(text)})
+		}
+		pos = next
+	}
+	flush()
+
+	if len(cues) == 0 && strings.TrimSpace(src) != "" && !vtt {
+		return nil, fmt.Errorf("no subtitle cues found")
+	}
+	return cues, nil
+}
+
+// extractSubtitles groups cues and extracts one node per group.
+func extractSubtitles(o subtitleOptions, content string, vtt bool) (*parsedSubtitles, []gotlai.TextNode, error) {
+	format, contentType := "SubRip", "srt"
+	if vtt {
+		format, contentType = "WebVTT", "vtt"
+	}
+
+	cues, err := parseSubtitleCues(content, vtt)
+	if err != nil {
+		return nil, nil, &gotlai.ProcessorError{
+			Message:     "failed to parse " + format + " subtitles",
+			Cause:       err,
+			ContentType: contentType,
+		}
+	}
+
+	ps := &parsedSubtitles{src: content, newline: "\n"}
+	if strings.Contains(content, "\r\n") {
+		ps.newline = "\r\n"
+	}
+
+	for i := 0; i < len(cues); {
+		n := 1
+		for o.mergeSentences && n < maxMergedCues && i+n < len(cues) && !sentenceEndPattern.MatchString(cues[i+n-1].plain) {
+			n++
+		}
+
+		group := &subtitleGroup{first: i, cues: cues[i : i+n]}
+		group.build(vtt)
+		if hasText(group.seg) {
+			group.hash = gotlai.HashText(group.seg.text)
+			ps.groups = append(ps.groups, group)
+		}
+		i += n
+	}
+
+	var nodes []gotlai.TextNode
+	seenHashes := make(map[string]bool)
+	for _, g := range ps.groups {
+		if seenHashes[g.hash] {
+			continue
+		}
+		seenHashes[g.hash] = true
+
+		parts := []string{format + " subtitle cue; keep it about as short as the source so it can be read in time"}
+		if len(g.cues) > 1 {
+			parts[0] = fmt.Sprintf("%s subtitle sentence spanning %d cues; keep the cue-break placeholders %s at natural pauses, with text on each side", format, len(g.cues), cueBreakList(g.breaks))
+		}
+		if g.first > 0 {
+			parts = append(parts, fmt.Sprintf("previous cue: %q", cues[g.first-1].plain))
+		}
+		if next := g.first + len(g.cues); next < len(cues) {
+			parts = append(parts, fmt.Sprintf("next cue: %q", cues[next].plain))
+		}
+
+		node := gotlai.TextNode{
+			ID:       fmt.Sprintf("node-%d", len(nodes)),
+			Text:     g.seg.text,
+			Hash:     g.hash,
+			NodeType: "subtitle_cue",
+			Context:  strings.Join(parts, " | "),
+			Metadata: map[string]string{
+				"cue":          strconv.Itoa(g.first + 1),
+				"placeholders": encodePlaceholders(g.seg.paired),
+			},
+		}
+		if len(g.breaks) > 0 {
+			ids := make([]string, len(g.breaks))
+			for i, id := range g.breaks {
+				ids[i] = strconv.Itoa(id)
+			}
+			node.Metadata["cue_breaks"] = strings.Join(ids, ",")
+		}
+		nodes = append(nodes, node)
+	}
+
+	return ps, nodes, nil
+}
+
+// cueBreakList formats cue-break placeholders for node context.
+func cueBreakList(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprintf("<x%d/>", id)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// build flattens the group's cues into one segment. Tags become self-closing
+// placeholders, and so do cue breaks and the line breaks of dialogue cues,
+// whose lines each start with a dash.
+func (g *subtitleGroup) build(vtt bool) {
+	seg := &codeSegment{}
+	var b strings.Builder
+
+	text := func(s string) string {
+		if vtt {
+			s = html.UnescapeString(s)
+		}
+		return escapeSegmentText(s)
+	}
+
+	for k, cue := range g.cues {
+		if k > 0 {
+			seg.writeAtom(&b, cueBreak)
+			g.breaks = append(g.breaks, len(seg.paired))
+		}
+
+		dialogue := len(cue.lines) > 1
+		for _, line := range cue.lines {
+			dialogue = dialogue && strings.HasPrefix(line, "-")
+		}
+
+		for i, line := range cue.lines {
+			if i > 0 {
+				if dialogue {
+					seg.writeAtom(&b, "\n")
+				} else {
+					b.WriteByte(' ')
+				}
+			}
+			pos := 0
+			for _, m := range subtitleTagPattern.FindAllStringIndex(line, -1) {
+				b.WriteString(text(line[pos:m[0]]))
+				seg.writeAtom(&b, line[m[0]:m[1]])
+				pos = m[1]
+			}
+			b.WriteString(text(line[pos:]))
+		}
+	}
+
+	seg.text = b.String()
+	g.seg = seg
+}
+
+// validateSubtitle checks a translation's placeholders and that every merged
+// cue gets some text.
+func validateSubtitle(node gotlai.TextNode, translated string) error {
+	items, err := parseSegment(translated, decodePlaceholders(node.Metadata["placeholders"]))
+	if err != nil || node.Metadata["cue_breaks"] == "" {
+		return err
+	}
+
+	breaks := make(map[int]bool)
+	for _, id := range strings.Split(node.Metadata["cue_breaks"], ",") {
+		n, _ := strconv.Atoi(id)
+		breaks[n] = true
+	}
+
+	part, hasText := 1, false
+	for _, item := range items {
+		if breaks[item.id] {
+			if !hasText {
+				return fmt.Errorf("part %d of the sentence has no text", part)
+			}
+			part, hasText = part+1, false
+		} else if item.id == 0 && strings.TrimSpace(item.text) != "" {
+			hasText = true
+		}
+	}
+	if !hasText {
+		return fmt.Errorf("part %d of the sentence has no text", part)
+	}
+	return nil
+}
+
+// applySubtitles splices wrapped translations into the cue text ranges.
+func applySubtitles(o subtitleOptions, parsed interface{}, translations map[string]string, vtt bool) (string, error) {
+	contentType := "srt"
+	if vtt {
+		contentType = "vtt"
+	}
+
+	ps, ok := parsed.(*parsedSubtitles)
+	if !ok {
+		return "", &gotlai.ProcessorError{
+			Message:     "invalid parsed content type",
+			ContentType: contentType,
+		}
+	}
+
+	var escape func(string) string
+	if vtt {
+		escape = escapeSegmentText
+	}
+
+	type edit struct {
+		start, end int
+		text       string
+	}
+	var edits []edit
+
+	for _, g := range ps.groups {
+		translated, ok := translations[g.hash]
+		if !ok {
+			continue
+		}
+		items, err := parseSegment(translated, g.seg.paired)
+		if err != nil {
+			continue
+		}
+
+		parts := strings.Split(g.seg.render(items, escape), cueBreak)
+		if len(parts) != len(g.cues) {
+			continue
+		}
+		for i, cue := range g.cues {
+			lines := wrapSubtitle(parts[i], o.maxLineLength)
+			if len(lines) == 0 {
+				continue
+			}
+			edits = append(edits, edit{cue.start, cue.end, strings.Join(lines, ps.newline)})
+		}
+	}
+
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+
+	var b strings.Builder
+	pos := 0
+	for _, e := range edits {
+		b.WriteString(ps.src[pos:e.start])
+		b.WriteString(e.text)
+		pos = e.end
+	}
+	b.WriteString(ps.src[pos:])
+
+	return b.String(), nil
+}
+
+// wrapSubtitle splits cue text into lines of at most max visible characters.
+// Line breaks in the text (between dialogue lines) are kept.
+func wrapSubtitle(text string, max int) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		words := strings.Fields(line)
+		if len(words) == 0 {
+			continue
+		}
+		if max <= 0 {
+			lines = append(lines, strings.Join(words, " "))
+			continue
+		}
+		lines = append(lines, wrapWords(words, max)...)
+	}
+	return lines
+}
+
+// wrapWords wraps words greedily into as few lines as max allows, then
+// narrows the width while the line count stays the same, so lines are balanced.
+// Words longer than max get a line of their own.
+func wrapWords(words []string, max int) []string {
+	greedy := func(width int) []string {
+		var lines []string
+		var current string
+		for _, w := range words {
+			if current != "" && visibleLength(current)+1+visibleLength(w) > width {
+				lines = append(lines, current)
+				current = ""
+			}
+			if current != "" {
+				current += " "
+			}
+			current += w
+		}
+		return append(lines, current)
+	}
+
+	lines := greedy(max)
+	if len(lines) < 2 {
+		return lines
+	}
+
+	total := visibleLength(strings.Join(words, " "))
+	for width := (total + len(lines) - 1) / len(lines); width < max; width++ {
+		if balanced := greedy(width); len(balanced) == len(lines) {
+			return balanced
+		}
+	}
+	return lines
+}
+
+// visibleLength counts the characters of s that are displayed, ignoring tags.
+func visibleLength(s string) int {
+	return utf8.RuneCountInString(html.UnescapeString(subtitleTagPattern.ReplaceAllString(s, "")))
+}
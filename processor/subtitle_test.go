@@ -0,0 +1,41 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWrapSubtitle(t *testing.T) {
+	tests := []struct {
+		text     string
+		max      int
+		expected string
+	}{
+		{"Short line", 42, "Short line"},
+		{"This sentence is a little too long for one subtitle line", 42, "This sentence is a little too|long for one subtitle line"},
+		{"<i>Tagged</i> words are counted without tags", 37, "<i>Tagged</i> words are counted without tags"},
+		{"- First speaker\n- Second speaker", 42, "- First speaker|- Second speaker"},
+		{"Kept on one line however long it is", 0, "Kept on one line however long it is"},
+		{"Supercalifragilistic word", 10, "Supercalifragilistic|word"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(wrapSubtitle(tt.text, tt.max), "|"); got != tt.expected {
+			t.Errorf("wrapSubtitle(%q, %d) = %q, want %q", tt.text, tt.max, got, tt.expected)
+		}
+	}
+}
+
+func TestSubtitleSentenceMergingLimit(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < 6; i++ {
+		b.WriteString("00:00:01,000 --> 00:00:02,000\nand then\n\n")
+	}
+
+	_, nodes, err := NewSRTProcessor(WithSentenceMerging()).Extract(b.String())
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(nodes) != 2 || nodes[0].Metadata["cue_breaks"] != "1,2,3" {
+		t.Errorf("Expected merges of at most %d cues, got %v", maxMergedCues, nodes)
+	}
+}
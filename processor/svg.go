@@ -0,0 +1,212 @@
+package processor
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ZaguanLabs/gotlai"
+	"golang.org/x/net/html"
+)
+
+// svgTextElements contains SVG elements whose text is rendered or announced.
+var svgTextElements = map[string]bool{
+	"text":     true,
+	"tspan":    true,
+	"textpath": true,
+	"title":    true,
+	"desc":     true,
+}
+
+// svgIgnoredElements contains SVG elements whose content is never translated.
+var svgIgnoredElements = map[string]bool{
+	"style":    true,
+	"script":   true,
+	"metadata": true,
+}
+
+// svgContexts describes SVG text elements for the AI.
+var svgContexts = map[string]string{
+	"title": "SVG accessible title of a graphic",
+	"desc":  "SVG description of a graphic",
+}
+
+// svgLabelContext describes rendered SVG text, which cannot wrap.
+const svgLabelContext = "SVG %s label in a graphic; horizontal space is limited, so keep the translation about as long as the source"
+
+// svgTextNode marks node as text of the given SVG element.
+func svgTextNode(node gotlai.TextNode, element string) gotlai.TextNode {
+	element = strings.ToLower(element)
+	node.NodeType = "svg_text"
+	if ctx, ok := svgContexts[element]; ok {
+		node.Context = ctx
+	} else {
+		node.Context = fmt.Sprintf(svgLabelContext, "<"+element+">")
+	}
+	if node.Metadata == nil {
+		node.Metadata = map[string]string{}
+	}
+	node.Metadata["svg_element"] = element
+	return node
+}
+
+// svgText reports whether a text node in an HTML document is inside inline SVG,
+// and if so whether its parent element holds translatable text.
+func svgText(n *html.Node) (inSVG, translatable bool) {
+	parent := n.Parent
+	if n.Type != html.TextNode || parent == nil || parent.Type != html.ElementNode || parent.Namespace != "svg" {
+		return false, false
+	}
+	return true, svgTextElements[strings.ToLower(parent.Data)]
+}
+
+// SVGProcessor extracts and applies translations to standalone SVG documents.
+// Text in <text>, <tspan>, <textPath>, <title> and <desc> is translated; each
+// text run is translated separately so <tspan> structure is kept. Translations
+// are spliced into the original source, so the output is valid XML that differs
+// only in translated text.
+type SVGProcessor struct{}
+
+// NewSVGProcessor creates a new SVG processor.
+func NewSVGProcessor() *SVGProcessor {
+	return &SVGProcessor{}
+}
+
+// svgRun is a translatable text run in an SVG document.
+type svgRun struct {
+	text       string // Decoded text
+	hash       string
+	start, end int // Byte range of the raw text, including any CDATA markers
+}
+
+// parsedSVG holds the SVG source and its translatable text runs.
+type parsedSVG struct {
+	src  string
+	runs []svgRun
+}
+
+// Extract parses SVG and extracts translatable text nodes.
+func (p *SVGProcessor) Extract(content string) (interface{}, []gotlai.TextNode, error) {
+	dec := xml.NewDecoder(strings.NewReader(content))
+
+	var nodes []gotlai.TextNode
+	var runs []svgRun
+	var stack []string
+	seenHashes := make(map[string]bool)
+	ignoredDepth := 0
+
+	for {
+		offset := dec.InputOffset()
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, &gotlai.ProcessorError{
+				Message:     "failed to parse SVG",
+				Cause:       err,
+				ContentType: "svg",
+			}
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := strings.ToLower(t.Name.Local)
+			if ignoredDepth > 0 || svgIgnoredElements[name] || svgNoTranslate(t.Attr) {
+				ignoredDepth++
+			}
+			stack = append(stack, name)
+		case xml.EndElement:
+			if ignoredDepth > 0 {
+				ignoredDepth--
+			}
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if ignoredDepth > 0 || len(stack) == 0 || !svgTextElements[stack[len(stack)-1]] {
+				continue
+			}
+			text := string(t)
+			trimmed := strings.TrimSpace(text)
+			if trimmed == "" {
+				continue
+			}
+
+			run := svgRun{
+				text:  text,
+				hash:  gotlai.HashText(trimmed),
+				start: int(offset),
+				end:   int(dec.InputOffset()),
+			}
+			runs = append(runs, run)
+
+			if seenHashes[run.hash] {
+				continue
+			}
+			seenHashes[run.hash] = true
+
+			element := stack[len(stack)-1]
+			node := gotlai.TextNode{
+				ID:       fmt.Sprintf("node-%d", len(nodes)),
+				Text:     trimmed,
+				Hash:     run.hash,
+				Metadata: map[string]string{},
+			}
+			if len(stack) > 1 {
+				node.Metadata["parent_tag"] = stack[len(stack)-2]
+			}
+			nodes = append(nodes, svgTextNode(node, element))
+		}
+	}
+
+	return &parsedSVG{src: content, runs: runs}, nodes, nil
+}
+
+// svgNoTranslate reports whether attributes mark an element as not translated.
+func svgNoTranslate(attrs []xml.Attr) bool {
+	for _, attr := range attrs {
+		switch attr.Name.Local {
+		case "data-no-translate":
+			return true
+		case "translate":
+			if strings.EqualFold(strings.TrimSpace(attr.Value), "no") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Apply applies translations back to the SVG source.
+func (p *SVGProcessor) Apply(parsed interface{}, nodes []gotlai.TextNode, translations map[string]string) (string, error) {
+	ps, ok := parsed.(*parsedSVG)
+	if !ok {
+		return "", &gotlai.ProcessorError{
+			Message:     "invalid parsed content type",
+			ContentType: "svg",
+		}
+	}
+
+	var b strings.Builder
+	pos := 0
+	for _, run := range ps.runs {
+		translated, ok := translations[run.hash]
+		if !ok {
+			continue
+		}
+		b.WriteString(ps.src[pos:run.start])
+		b.WriteString(escapeSegmentText(preserveWhitespace(run.text, translated)))
+		pos = run.end
+	}
+	b.WriteString(ps.src[pos:])
+
+	return b.String(), nil
+}
+
+// ContentType returns "svg".
+func (p *SVGProcessor) ContentType() string {
+	return "svg"
+}
+
+// Verify SVGProcessor implements ContentProcessor
+var _ ContentProcessor = (*SVGProcessor)(nil)
@@ -0,0 +1,163 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+const svgDoc = `<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" viewBox="0 0 100 50">
+  <title>Sales chart</title>
+  <desc>Sales &amp; returns by quarter</desc>
+  <style>.label { font: 10px sans-serif; }</style>
+  <g id="labels">
+    <text x="10" y="20" class="label">Revenue <tspan font-weight="bold">Q1</tspan> total</text>
+    <text x="10" y="40"><![CDATA[Returns < 5%]]></text>
+    <text translate="no">ACME</text>
+  </g>
+  <!-- generated -->
+</svg>
+`
+
+func TestSVGProcessor_Extract(t *testing.T) {
+	p := NewSVGProcessor()
+
+	_, nodes, err := p.Extract(svgDoc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	var texts []string
+	for _, n := range nodes {
+		texts = append(texts, n.Metadata["svg_element"]+":"+n.Text)
+	}
+
+	expected := []string{
+		"title:Sales chart",
+		"desc:Sales & returns by quarter",
+		"text:Revenue",
+		"tspan:Q1",
+		"text:total",
+		"text:Returns < 5%",
+	}
+	if strings.Join(texts, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, texts)
+	}
+
+	if nodes[2].NodeType != "svg_text" || !strings.Contains(nodes[2].Context, "horizontal space is limited") {
+		t.Errorf("Expected SVG label context, got %q %q", nodes[2].NodeType, nodes[2].Context)
+	}
+	if strings.Contains(nodes[0].Context, "horizontal space") {
+		t.Errorf("Titles are not space-limited, got %q", nodes[0].Context)
+	}
+}
+
+func TestSVGProcessor_Apply(t *testing.T) {
+	p := NewSVGProcessor()
+
+	parsed, nodes, err := p.Extract(svgDoc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	result, err := p.Apply(parsed, nodes, bracketAll(nodes))
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	expected := strings.NewReplacer(
+		"<title>Sales chart</title>", "<title>[Sales chart]</title>",
+		"<desc>Sales &amp; returns by quarter</desc>", "<desc>[Sales &amp; returns by quarter]</desc>",
+		`class="label">Revenue <tspan font-weight="bold">Q1</tspan> total</text>`, `class="label">[Revenue] <tspan font-weight="bold">[Q1]</tspan> [total]</text>`,
+		"<![CDATA[Returns < 5%]]>", "[Returns &lt; 5%]",
+	).Replace(svgDoc)
+	if result != expected {
+		t.Errorf("Apply = %q, want %q", result, expected)
+	}
+
+	// The output must be well-formed XML
+	dec := xml.NewDecoder(strings.NewReader(result))
+	for {
+		if _, err := dec.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Output is not valid XML: %v", err)
+		}
+	}
+}
+
+func TestSVGProcessor_InvalidXML(t *testing.T) {
+	p := NewSVGProcessor()
+
+	if _, _, err := p.Extract(`<svg><text>Unclosed</svg>`); err == nil {
+		t.Error("Extract should fail on malformed XML")
+	}
+}
+
+func TestSVGProcessor_ContentType(t *testing.T) {
+	if ct := NewSVGProcessor().ContentType(); ct != "svg" {
+		t.Errorf("Expected 'svg', got %q", ct)
+	}
+}
+
+const inlineSVGHTML = `<html><body><p>Chart</p>
+<svg viewBox="0 0 10 10"><title>Diagram</title><g>stray</g><text>Start <tspan>here</tspan></text>
+<foreignObject><div>Note</div></foreignObject></svg></body></html>`
+
+func TestHTMLProcessor_InlineSVG(t *testing.T) {
+	p := NewHTMLProcessor()
+
+	parsed, nodes, err := p.Extract(inlineSVGHTML)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	types := make(map[string]string)
+	for _, n := range nodes {
+		types[n.Text] = n.NodeType
+	}
+
+	expected := map[string]string{
+		"Chart":   "html_text",
+		"Diagram": "svg_text",
+		"Start":   "svg_text",
+		"here":    "svg_text",
+		"Note":    "html_text",
+	}
+	if len(types) != len(expected) {
+		t.Errorf("Expected %d nodes, got %v", len(expected), types)
+	}
+	for text, nodeType := range expected {
+		if types[text] != nodeType {
+			t.Errorf("Node %q type = %q, want %q", text, types[text], nodeType)
+		}
+	}
+
+	result, err := p.Apply(parsed, nodes, bracketAll(nodes))
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if !strings.Contains(result, "<text>[Start] <tspan>[here]</tspan></text>") || !strings.Contains(result, "<g>stray</g>") {
+		t.Errorf("Unexpected SVG output: %s", result)
+	}
+}
+
+func TestHTMLProcessor_Stream_InlineSVG(t *testing.T) {
+	p := NewHTMLProcessor()
+	tr := &fakeStreamTranslator{}
+
+	var out bytes.Buffer
+	if err := p.Stream(context.Background(), strings.NewReader(inlineSVGHTML), &out, tr); err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+
+	for _, s := range []string{"<title>[Diagram]</title>", "<g>stray</g>", "<text>[Start] <tspan>[here]</tspan></text>", "<div>[Note]</div>"} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("Expected %q in output, got: %s", s, out.String())
+		}
+	}
+}
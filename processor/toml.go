@@ -0,0 +1,383 @@
+package processor
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ZaguanLabs/gotlai"
+)
+
+// tomlBareKeyPattern matches TOML bare keys.
+var tomlBareKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// TOMLProcessor extracts and applies translations to TOML files such as Hugo
+// data and i18n files. Basic, literal and multi-line strings are translated,
+// including strings in arrays; key paths follow table headers,
+// dotted keys and array-of-tables indexes. When every table lives under one
+// locale key ([en.menu]), key paths are relative to it and it is renamed to
+// the target language. Translations are spliced into the source, so comments
+// and key order are kept.
+type TOMLProcessor struct {
+	keys KeyFilter
+}
+
+// TOMLProcessorOption configures the TOML processor.
+type TOMLProcessorOption func(*TOMLProcessor)
+
+// WithTOMLKeys restricts translation to strings whose key path passes filter.
+func WithTOMLKeys(filter KeyFilter) TOMLProcessorOption {
+	return func(p *TOMLProcessor) {
+		p.keys = filter
+	}
+}
+
+// NewTOMLProcessor creates a new TOML processor.
+func NewTOMLProcessor(opts ...TOMLProcessorOption) *TOMLProcessor {
+	p := &TOMLProcessor{}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Extract parses TOML and extracts its translatable strings.
+func (p *TOMLProcessor) Extract(content string) (interface{}, []gotlai.TextNode, error) {
+	pc, err := parseTOML(content)
+	if err != nil {
+		return nil, nil, &gotlai.ProcessorError{
+			Message:     "failed to parse TOML",
+			Cause:       err,
+			ContentType: "toml",
+		}
+	}
+
+	return pc, configNodes(pc, p.keys, "TOML"), nil
+}
+
+// parseTOML scans the tables and key/value pairs of a TOML document.
+func parseTOML(src string) (*parsedConfig, error) {
+	pc := &parsedConfig{src: src, toml: true}
+	lines := splitLines(src, 0)
+
+	var table []string
+	arrayTables := make(map[string]int)
+	var roots []string
+	rootValues := false
+
+	add := func(keyPath, text, quote string, start, end int) {
+		if !hasLetter(text) {
+			return
+		}
+		pc.values = append(pc.values, &configValue{
+			keyPath: keyPath,
+			text:    text,
+			hash:    gotlai.HashText(strings.TrimSpace(text)),
+			start:   start,
+			end:     end,
+			quote:   quote,
+		})
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := src[lines[i][0]:lines[i][1]]
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" || trimmed[0] == '#' {
+			continue
+		}
+		col := lines[i][1] - len(trimmed)
+
+		// Table and array-of-tables headers
+		if trimmed[0] == '[' {
+			open, closer := 1, "]"
+			if strings.HasPrefix(trimmed, "[[") {
+				open, closer = 2, "]]"
+			}
+			end := strings.Index(trimmed, closer)
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated table header", i+1)
+			}
+			keys, spans, err := tomlKeyPath(trimmed[open:end], col+open)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			table = keys
+			if open == 2 {
+				name := strings.Join(keys, ".")
+				table = append(append([]string(nil), keys...), strconv.Itoa(arrayTables[name]))
+				arrayTables[name]++
+			}
+			roots = append(roots, keys[0])
+			pc.localeKeys = append(pc.localeKeys, spans[0])
+			continue
+		}
+
+		eq := tomlKeyEnd(trimmed)
+		if eq < 0 {
+			return nil, fmt.Errorf("line %d: expected key = value", i+1)
+		}
+		keys, _, err := tomlKeyPath(trimmed[:eq], col)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if len(table) == 0 {
+			rootValues = true
+		}
+		keyPath := strings.Join(append(append([]string(nil), table...), keys...), ".")
+
+		value := strings.TrimLeft(trimmed[eq+1:], " \t")
+		start := lines[i][1] - len(value)
+		if value == "" {
+			return nil, fmt.Errorf("line %d: missing value", i+1)
+		}
+
+		switch {
+		case strings.HasPrefix(value, `"""`) || strings.HasPrefix(value, "'''"):
+			delim := value[:3]
+			n := strings.Index(src[start+3:], delim)
+			if n < 0 {
+				return nil, fmt.Errorf("line %d: unterminated multi-line string", i+1)
+			}
+			end := start + 3 + n + 3
+			for i+1 < len(lines) && lines[i+1][0] < end {
+				i++
+			}
+
+			// The first newline is trimmed; escapes in basic strings are kept as they are
+			text := strings.TrimPrefix(strings.TrimPrefix(src[start+3:end-3], "\r"), "\n")
+			if delim == `"""` && strings.Contains(text, `\`) {
+				continue
+			}
+			add(keyPath, strings.ReplaceAll(text, "\r\n", "\n"), delim, start, end)
+		case value[0] == '"' || value[0] == '\'':
+			v, ok := frontMatterScalar(value, true)
+			if ok {
+				add(keyPath, v.text, v.block.quote, start, start+v.block.end)
+			}
+		case value[0] == '[':
+			// String elements are translated; arrays may span lines
+			end := tomlValueEnd(src, start)
+			for i+1 < len(lines) && lines[i+1][0] < end {
+				i++
+			}
+			index := 0
+			for k := start + 1; k < end-1; {
+				switch c := src[k]; {
+				case c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == ',':
+					k++
+					continue
+				case c == '#':
+					if n := strings.IndexByte(src[k:end], '\n'); n >= 0 {
+						k += n + 1
+					} else {
+						k = end
+					}
+					continue
+				case (c == '"' || c == '\'') && !strings.HasPrefix(src[k:], `"""`) && !strings.HasPrefix(src[k:], "'''"):
+					if v, ok := frontMatterScalar(src[k:end-1], true); ok {
+						add(fmt.Sprintf("%s.%d", keyPath, index), v.text, v.block.quote, k, k+v.block.end)
+						k += v.block.end
+					} else {
+						k = tomlValueEnd(src, k)
+					}
+				default:
+					// Nested arrays, inline tables and other values are kept
+					k = tomlValueEnd(src, k)
+				}
+				index++
+			}
+		case value[0] == '{':
+			end := tomlValueEnd(src, start)
+			for i+1 < len(lines) && lines[i+1][0] < end {
+				i++
+			}
+		}
+	}
+
+	// Tables all under one locale key make a locale bundle
+	if !rootValues && len(roots) > 0 && isLocaleKey(roots[0]) {
+		pc.localeRoot = roots[0]
+		for _, root := range roots {
+			if root != pc.localeRoot {
+				pc.localeRoot = ""
+				break
+			}
+		}
+	}
+	if pc.localeRoot == "" {
+		pc.localeKeys = nil
+	} else {
+		pc.stripLocaleRoot()
+	}
+
+	return pc, nil
+}
+
+// tomlKeyPath splits a dotted key into its segments. The returned ranges
+// locate each segment without quotes, offset by start.
+func tomlKeyPath(s string, start int) ([]string, [][2]int, error) {
+	var keys []string
+	var spans [][2]int
+
+	i := 0
+	for {
+		for i < len(s) && (s[i] == ' ' || s[i] == '\t') {
+			i++
+		}
+		if i == len(s) {
+			return nil, nil, fmt.Errorf("empty key in %q", s)
+		}
+
+		switch s[i] {
+		case '"':
+			end := closingQuote(s[i:])
+			if end < 0 {
+				return nil, nil, fmt.Errorf("unterminated key in %q", s)
+			}
+			key, err := strconv.Unquote(s[i : i+end+1])
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid key in %q", s)
+			}
+			keys = append(keys, key)
+			spans = append(spans, [2]int{start + i + 1, start + i + end})
+			i += end + 1
+		case '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, nil, fmt.Errorf("unterminated key in %q", s)
+			}
+			keys = append(keys, s[i+1:i+1+end])
+			spans = append(spans, [2]int{start + i + 1, start + i + 1 + end})
+			i += end + 2
+		default:
+			end := i
+			for end < len(s) && s[end] != '.' && s[end] != ' ' && s[end] != '\t' {
+				end++
+			}
+			if !tomlBareKeyPattern.MatchString(s[i:end]) {
+				return nil, nil, fmt.Errorf("invalid key in %q", s)
+			}
+			keys = append(keys, s[i:end])
+			spans = append(spans, [2]int{start + i, start + end})
+			i = end
+		}
+
+		for i < len(s) && (s[i] == ' ' || s[i] == '\t') {
+			i++
+		}
+		if i == len(s) {
+			return keys, spans, nil
+		}
+		if s[i] != '.' {
+			return nil, nil, fmt.Errorf("invalid key in %q", s)
+		}
+		i++
+	}
+}
+
+// tomlKeyEnd returns the offset of the '=' ending the key at the start of s, or -1.
+func tomlKeyEnd(s string) int {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '=':
+			return i
+		case c == '#':
+			return -1
+		}
+	}
+	return -1
+}
+
+// tomlValueEnd returns the offset after the value starting at src[start],
+// matching brackets and braces outside strings and comments.
+func tomlValueEnd(src string, start int) int {
+	depth := 0
+	for i := start; i < len(src); i++ {
+		switch c := src[i]; c {
+		case '"', '\'':
+			delim := string(c)
+			if strings.HasPrefix(src[i:], strings.Repeat(delim, 3)) {
+				delim = strings.Repeat(delim, 3)
+			}
+			j := i + len(delim)
+			for j < len(src) && !strings.HasPrefix(src[j:], delim) {
+				if c == '"' && src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			i = j + len(delim) - 1
+		case '#':
+			if n := strings.IndexByte(src[i:], '\n'); n >= 0 {
+				i += n
+			} else {
+				i = len(src)
+			}
+		case '[', '{':
+			depth++
+		case ']', '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		case ',', '\n':
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(src)
+}
+
+// tomlMultiline encodes text as a multi-line string. Literal strings that
+// would contain their own delimiter are written as basic strings.
+func tomlMultiline(text, delim string, leadingNewline bool) string {
+	if delim == "'''" && strings.Contains(text, "'''") {
+		delim = `"""`
+	}
+	if delim == `"""` {
+		text = strings.ReplaceAll(text, `\`, `\\`)
+		text = strings.ReplaceAll(text, `"""`, `""\"`)
+	}
+
+	newline := ""
+	if leadingNewline {
+		newline = "\n"
+	}
+	return delim + newline + text + delim
+}
+
+// ApplyTarget splices translated strings into the TOML source and renames the
+// root locale key to the target language.
+func (p *TOMLProcessor) ApplyTarget(parsed interface{}, nodes []gotlai.TextNode, translations map[string]string, targetLang string) (string, error) {
+	return applyConfig(parsed, translations, targetLang, "toml")
+}
+
+// Apply splices translated strings into the TOML source.
+func (p *TOMLProcessor) Apply(parsed interface{}, nodes []gotlai.TextNode, translations map[string]string) (string, error) {
+	return applyConfig(parsed, translations, "", "toml")
+}
+
+// ContentType returns "toml".
+func (p *TOMLProcessor) ContentType() string {
+	return "toml"
+}
+
+// Verify TOMLProcessor implements ContentProcessor and TargetApplier
+var (
+	_ ContentProcessor     = (*TOMLProcessor)(nil)
+	_ gotlai.TargetApplier = (*TOMLProcessor)(nil)
+)
@@ -0,0 +1,192 @@
+package processor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ZaguanLabs/gotlai"
+)
+
+const hugoDataDoc = `# Site data
+title = "My site"
+"sub.title" = 'Notes & essays'
+weight = 3
+draft = false
+site.description = """
+A blog about
+everything."""
+keywords = ["travel", 'food', 42]
+
+[author]
+name = "Ana"
+bio = '''
+Writes "stories".'''
+
+[[features]]
+label = "Fast"
+
+[[features]]
+label = "Simple" # shown second
+`
+
+func TestTOMLProcessor_Extract(t *testing.T) {
+	p := NewTOMLProcessor()
+
+	_, nodes, err := p.Extract(hugoDataDoc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	var paths []string
+	for _, n := range nodes {
+		paths = append(paths, n.Metadata["key_path"]+"="+n.Text)
+		if _, ok := n.Metadata["locale_root"]; ok {
+			t.Errorf("Unexpected locale root in %v", n.Metadata)
+		}
+	}
+
+	expected := []string{
+		"title=My site",
+		"sub.title=Notes & essays",
+		"site.description=A blog about\neverything.",
+		"keywords.0=travel",
+		"keywords.1=food",
+		"author.name=Ana",
+		`author.bio=Writes "stories".`,
+		"features.0.label=Fast",
+		"features.1.label=Simple",
+	}
+	if strings.Join(paths, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, paths)
+	}
+	if !strings.Contains(nodes[0].Context, "TOML resource file") {
+		t.Errorf("Unexpected context %q", nodes[0].Context)
+	}
+}
+
+func TestTOMLProcessor_Apply(t *testing.T) {
+	p := NewTOMLProcessor()
+
+	parsed, nodes, err := p.Extract(hugoDataDoc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	translations := map[string]string{
+		nodes[0].Hash: `Mi "sitio"`,
+		nodes[1].Hash: "Notas y ensayos",
+		nodes[2].Hash: "Un blog sobre\ntodo.",
+		nodes[3].Hash: "viajes",
+		nodes[6].Hash: "Escribe '''historias'''.",
+		nodes[8].Hash: "Simple",
+	}
+
+	result, err := p.Apply(parsed, nodes, translations)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	expected := `# Site data
+title = "Mi \"sitio\""
+"sub.title" = 'Notas y ensayos'
+weight = 3
+draft = false
+site.description = """
+Un blog sobre
+todo."""
+keywords = ["viajes", 'food', 42]
+
+[author]
+name = "Ana"
+bio = """
+Escribe '''historias'''."""
+
+[[features]]
+label = "Fast"
+
+[[features]]
+label = "Simple" # shown second
+`
+	if result != expected {
+		t.Errorf("Unexpected output:\n%s", result)
+	}
+}
+
+func TestTOMLProcessor_LocaleRoot(t *testing.T) {
+	p := NewTOMLProcessor(WithTOMLKeys(KeyFilter{Exclude: []string{"menu.url"}}))
+
+	content := `[en.menu]
+label = "Home"
+url = "/home"
+
+[en."footer"]
+copyright = "All rights reserved"
+`
+	parsed, nodes, err := p.Extract(content)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	var paths []string
+	for _, n := range nodes {
+		paths = append(paths, n.Metadata["key_path"])
+	}
+	expected := []string{"menu.label", "footer.copyright"}
+	if strings.Join(paths, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, paths)
+	}
+	if nodes[0].Metadata["locale_root"] != "en" {
+		t.Errorf("Expected locale root, got %v", nodes[0].Metadata)
+	}
+
+	result, err := p.ApplyTarget(parsed, nodes, map[string]string{nodes[0].Hash: "Startseite"}, "de_DE")
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	expectedResult := `[de-DE.menu]
+label = "Startseite"
+url = "/home"
+
+[de-DE."footer"]
+copyright = "All rights reserved"
+`
+	if result != expectedResult {
+		t.Errorf("Unexpected output:\n%s", result)
+	}
+}
+
+func TestTOMLProcessor_MultiLineArray(t *testing.T) {
+	p := NewTOMLProcessor()
+
+	content := `labels = [
+  "One", # first
+  { name = "skip" },
+  "Two",
+]
+after = "Three"
+`
+	_, nodes, err := p.Extract(content)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	var paths []string
+	for _, n := range nodes {
+		paths = append(paths, n.Metadata["key_path"]+"="+n.Text)
+	}
+	expected := []string{"labels.0=One", "labels.2=Two", "after=Three"}
+	if strings.Join(paths, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, paths)
+	}
+}
+
+func TestTOMLProcessor_InvalidInput(t *testing.T) {
+	p := NewTOMLProcessor()
+
+	_, _, err := p.Extract("[unterminated\n")
+	var perr *gotlai.ProcessorError
+	if !errors.As(err, &perr) || perr.Message != "failed to parse TOML" {
+		t.Errorf("Expected a parse error, got %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package processor
+
+import "github.com/ZaguanLabs/gotlai"
+
+// VTTProcessor extracts and applies translations to WebVTT (.vtt) subtitles.
+// Cue text is translated like SRTProcessor does; the header, cue identifiers,
+// timings and settings, and NOTE, STYLE and REGION blocks are kept unchanged.
+// Cue tags such as <v Speaker>, <c.class> and <b> become placeholders, and
+// character references (&amp;) are decoded for translation and re-escaped.
+type VTTProcessor struct {
+	opts subtitleOptions
+}
+
+// NewVTTProcessor creates a new WebVTT processor.
+func NewVTTProcessor(opts ...SubtitleOption) *VTTProcessor {
+	return &VTTProcessor{opts: newSubtitleOptions(opts)}
+}
+
+// Extract parses WebVTT subtitles and extracts their cue text.
+func (p *VTTProcessor) Extract(content string) (interface{}, []gotlai.TextNode, error) {
+	return extractSubtitles(p.opts, content, true)
+}
+
+// Apply writes translated cue text into the subtitles.
+func (p *VTTProcessor) Apply(parsed interface{}, nodes []gotlai.TextNode, translations map[string]string) (string, error) {
+	return applySubtitles(p.opts, parsed, translations, true)
+}
+
+// ValidateTranslation checks that a translation keeps every tag and cue break.
+func (p *VTTProcessor) ValidateTranslation(node gotlai.TextNode, translated string) error {
+	if node.NodeType != "subtitle_cue" {
+		return nil
+	}
+	return validateSubtitle(node, translated)
+}
+
+// ContentType returns "vtt".
+func (p *VTTProcessor) ContentType() string {
+	return "vtt"
+}
+
+// Verify VTTProcessor implements ContentProcessor and TranslationValidator
+var (
+	_ ContentProcessor            = (*VTTProcessor)(nil)
+	_ gotlai.TranslationValidator = (*VTTProcessor)(nil)
+)
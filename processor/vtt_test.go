@@ -0,0 +1,102 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+)
+
+const vttDoc = `WEBVTT - Product tour
+
+NOTE Reviewed by the video team
+
+STYLE
+::cue { color: yellow }
+
+intro
+00:00:01.000 --> 00:00:03.000 align:start
+<v Ana>Tom &amp; Jerry are here
+
+00:00:03.500 --> 00:00:05.000
+and they brought snacks.
+`
+
+func TestVTTProcessor_Extract(t *testing.T) {
+	p := NewVTTProcessor()
+
+	_, nodes, err := p.Extract(vttDoc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	var texts []string
+	for _, n := range nodes {
+		texts = append(texts, n.Text)
+	}
+
+	expected := []string{"<x1/>Tom &amp; Jerry are here", "and they brought snacks."}
+	if strings.Join(texts, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, texts)
+	}
+}
+
+func TestVTTProcessor_SentenceMerging(t *testing.T) {
+	p := NewVTTProcessor(WithSentenceMerging())
+
+	parsed, nodes, err := p.Extract(vttDoc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].Text != "<x1/>Tom &amp; Jerry are here<x2/>and they brought snacks." {
+		t.Fatalf("Expected one merged node, got %v", nodes)
+	}
+	if nodes[0].Metadata["cue_breaks"] != "2" || !strings.Contains(nodes[0].Context, "spanning 2 cues") {
+		t.Errorf("Unexpected node %v", nodes[0])
+	}
+
+	translations := map[string]string{nodes[0].Hash: "<x1/>Tom &amp; Jerry sont là<x2/>et ont apporté des en-cas."}
+	result, err := p.Apply(parsed, nodes, translations)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"WEBVTT - Product tour\n\nNOTE Reviewed by the video team\n\nSTYLE\n::cue { color: yellow }\n\n",
+		"intro\n00:00:01.000 --> 00:00:03.000 align:start\n<v Ana>Tom &amp; Jerry sont là\n",
+		"00:00:03.500 --> 00:00:05.000\net ont apporté des en-cas.\n",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in result, got:\n%s", want, result)
+		}
+	}
+}
+
+func TestVTTProcessor_ValidateTranslation(t *testing.T) {
+	p := NewVTTProcessor(WithSentenceMerging())
+
+	_, nodes, err := p.Extract(vttDoc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if err := p.ValidateTranslation(nodes[0], "<x1/>Tom et Jerry<x2/>ont des en-cas."); err != nil {
+		t.Errorf("Expected valid translation, got %v", err)
+	}
+	if err := p.ValidateTranslation(nodes[0], "<x1/>Tom et Jerry ont des en-cas.<x2/>"); err == nil {
+		t.Error("Expected an empty cue to be rejected")
+	}
+	if err := p.ValidateTranslation(nodes[0], "Tom et Jerry<x2/>ont des en-cas."); err == nil {
+		t.Error("Expected a missing tag to be rejected")
+	}
+}
+
+func TestVTTProcessor_MissingHeader(t *testing.T) {
+	if _, _, err := NewVTTProcessor().Extract("00:00:01.000 --> 00:00:02.000\nHi\n"); err == nil {
+		t.Error("Expected error without WEBVTT header")
+	}
+}
+
+func TestVTTProcessor_ContentType(t *testing.T) {
+	if ct := NewVTTProcessor().ContentType(); ct != "vtt" {
+		t.Errorf("Expected 'vtt', got %q", ct)
+	}
+}
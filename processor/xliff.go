@@ -0,0 +1,431 @@
+package processor
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ZaguanLabs/gotlai"
+)
+
+// xliffPairedCodes contains inline elements whose content is translatable.
+// Other inline codes (<x/>, <ph>, <bpt>, <sc/>, ...) are kept whole.
+var xliffPairedCodes = map[string]bool{
+	"g":   true,
+	"pc":  true,
+	"mrk": true,
+}
+
+// xliffSkippedElements contains elements inside units whose sources are not translated.
+var xliffSkippedElements = map[string]bool{
+	"alt-trans":    true,
+	"matches":      true,
+	"ignorable":    true,
+	"originalData": true,
+}
+
+// xliffUntranslatedStates contains XLIFF 1.2 target states that need a translation.
+var xliffUntranslatedStates = map[string]bool{
+	"":                  true,
+	"new":               true,
+	"needs-translation": true,
+}
+
+// XLIFFProcessor extracts and applies translations to XLIFF 1.2 and 2.0
+// documents. Sources of <trans-unit> and <unit><segment> entries are translated
+// when they have no target yet or their state asks for one: new or
+// needs-translation on a 1.2 <target>, initial on a 2.0 <segment>. Notes and
+// the unit's resname (1.2) or name (2.0) become node context, and inline codes
+// such as <g>, <pc> and <ph> become placeholders that translations must keep.
+// Targets are written with state="translated", and the rest of the document is
+// unchanged.
+type XLIFFProcessor struct {
+	needsReview bool
+}
+
+// XLIFFProcessorOption configures the XLIFF processor.
+type XLIFFProcessorOption func(*XLIFFProcessor)
+
+// WithNeedsReview marks translated targets for review: state="needs-review-translation"
+// in XLIFF 1.2, and state="translated" with subState="gotlai:needs-review" in 2.0.
+func WithNeedsReview() XLIFFProcessorOption {
+	return func(p *XLIFFProcessor) {
+		p.needsReview = true
+	}
+}
+
+// NewXLIFFProcessor creates a new XLIFF processor.
+func NewXLIFFProcessor(opts ...XLIFFProcessorOption) *XLIFFProcessor {
+	p := &XLIFFProcessor{}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// xliffSpan is a byte range of an element in the source.
+type xliffSpan struct {
+	start, openEnd, end int // Element start, end of its start tag, element end
+}
+
+// xliffUnit is a <trans-unit> or <unit>.
+type xliffUnit struct {
+	id, name string
+	notes    []string
+}
+
+// xliffSegment is a translatable source of a unit.
+type xliffSegment struct {
+	unit              *xliffUnit
+	seg               *codeSegment
+	leading, trailing string // Whitespace around the source content
+	hash              string
+	source            xliffSpan
+	indent            string     // Whitespace before <source> on its line
+	target            *xliffSpan // Existing target element
+	segmentTag        *xliffSpan // <segment> start tag (2.0)
+}
+
+// parsedXLIFF holds the XLIFF source and its translatable segments.
+type parsedXLIFF struct {
+	src      string
+	version2 bool
+	langTag  *xliffSpan // Start tag that carries the target language, if it lacks one
+	segments []*xliffSegment
+}
+
+// Extract parses XLIFF and extracts the sources that need translation.
+func (p *XLIFFProcessor) Extract(content string) (interface{}, []gotlai.TextNode, error) {
+	px, err := parseXLIFF(content)
+	if err != nil {
+		return nil, nil, &gotlai.ProcessorError{
+			Message:     "failed to parse XLIFF",
+			Cause:       err,
+			ContentType: "xliff",
+		}
+	}
+
+	var nodes []gotlai.TextNode
+	seenHashes := make(map[string]bool)
+	for _, s := range px.segments {
+		if seenHashes[s.hash] {
+			continue
+		}
+		seenHashes[s.hash] = true
+
+		parts := []string{"XLIFF translation unit"}
+		if s.unit.name != "" {
+			parts = append(parts, "resname: "+s.unit.name)
+		}
+		parts = append(parts, s.unit.notes...)
+		if len(s.seg.paired) > 0 {
+			parts = append(parts, "contains inline code placeholders")
+		}
+
+		node := gotlai.TextNode{
+			ID:       fmt.Sprintf("node-%d", len(nodes)),
+			Text:     s.seg.text,
+			Hash:     s.hash,
+			NodeType: "xliff_segment",
+			Context:  strings.Join(parts, " | "),
+			Metadata: map[string]string{
+				"unit_id":      s.unit.id,
+				"placeholders": encodePlaceholders(s.seg.paired),
+			},
+		}
+		if s.unit.name != "" {
+			node.Metadata["resname"] = s.unit.name
+		}
+		nodes = append(nodes, node)
+	}
+
+	return px, nodes, nil
+}
+
+// parseXLIFF walks the XLIFF tokens and collects segments that need translation.
+func parseXLIFF(src string) (*parsedXLIFF, error) {
+	px := &parsedXLIFF{src: src}
+	dec := xml.NewDecoder(strings.NewReader(src))
+
+	var unit *xliffUnit
+	var cur *xliffSegment
+	translate := true
+	state := ""
+	hasTarget := false
+
+	// finish keeps the current segment if it still needs a translation
+	finish := func() {
+		if cur != nil && cur.seg != nil && translate && !hasTarget && hasText(cur.seg) {
+			cur.hash = gotlai.HashText(cur.seg.text)
+			px.segments = append(px.segments, cur)
+		}
+		cur, state, hasTarget = nil, "", false
+	}
+
+	for {
+		start := int(dec.InputOffset())
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		openEnd := int(dec.InputOffset())
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := t.Name.Local
+			switch {
+			case name == "xliff":
+				px.version2 = strings.HasPrefix(xmlAttr(t.Attr, "version"), "2")
+				if px.version2 && xmlAttr(t.Attr, "trgLang") == "" {
+					px.langTag = &xliffSpan{start: start, openEnd: openEnd}
+				}
+			case name == "file" && !px.version2:
+				if xmlAttr(t.Attr, "target-language") == "" {
+					px.langTag = &xliffSpan{start: start, openEnd: openEnd}
+				}
+			case name == "trans-unit" || name == "unit":
+				unit = &xliffUnit{id: xmlAttr(t.Attr, "id"), name: xmlAttr(t.Attr, "resname")}
+				if name == "unit" {
+					unit.name = xmlAttr(t.Attr, "name")
+				}
+				translate = xmlAttr(t.Attr, "translate") != "no" && xmlAttr(t.Attr, "approved") != "yes"
+				if name == "trans-unit" {
+					cur = &xliffSegment{unit: unit}
+				}
+			case name == "segment" && unit != nil:
+				cur = &xliffSegment{unit: unit, segmentTag: &xliffSpan{start: start, openEnd: openEnd}}
+				state = xmlAttr(t.Attr, "state")
+			case xliffSkippedElements[name]:
+				if err := dec.Skip(); err != nil {
+					return nil, err
+				}
+			case name == "note" && unit != nil:
+				var note struct {
+					Text string `xml:",chardata"`
+				}
+				if err := dec.DecodeElement(&note, &t); err != nil {
+					return nil, err
+				}
+				if text := strings.TrimSpace(note.Text); text != "" {
+					unit.notes = append(unit.notes, text)
+				}
+			case (name == "source" || name == "target") && cur != nil:
+				if err := dec.Skip(); err != nil {
+					return nil, err
+				}
+				span := xliffSpan{start: start, openEnd: openEnd, end: int(dec.InputOffset())}
+				inner := xmlInner(src, span)
+
+				if name == "target" {
+					cur.target = &span
+					untranslated := state == "initial"
+					if !px.version2 {
+						state = xmlAttr(t.Attr, "state")
+						untranslated = xliffUntranslatedStates[state]
+					}
+					hasTarget = strings.TrimSpace(inner) != "" && !untranslated
+					continue
+				}
+
+				seg, err := buildXMLSegment(inner, xliffPairedCodes, nil)
+				if err != nil {
+					return nil, err
+				}
+				trimmed := strings.TrimSpace(seg.text)
+				cur.leading = seg.text[:strings.Index(seg.text, trimmed)]
+				cur.trailing = seg.text[len(cur.leading)+len(trimmed):]
+				seg.text = trimmed
+				cur.seg = seg
+				cur.source = span
+				cur.indent = lineIndent(src, start)
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "trans-unit":
+				finish()
+				unit = nil
+			case "segment":
+				finish()
+			case "unit":
+				unit = nil
+			}
+		}
+	}
+
+	return px, nil
+}
+
+// Apply applies translations to the XLIFF source.
+func (p *XLIFFProcessor) Apply(parsed interface{}, nodes []gotlai.TextNode, translations map[string]string) (string, error) {
+	return p.ApplyTarget(parsed, nodes, translations, "")
+}
+
+// ApplyTarget applies translations, also setting the target language of the
+// document when it has none.
+func (p *XLIFFProcessor) ApplyTarget(parsed interface{}, nodes []gotlai.TextNode, translations map[string]string, targetLang string) (string, error) {
+	px, ok := parsed.(*parsedXLIFF)
+	if !ok {
+		return "", &gotlai.ProcessorError{
+			Message:     "invalid parsed content type",
+			ContentType: "xliff",
+		}
+	}
+
+	type edit struct {
+		start, end int
+		text       string
+	}
+	var edits []edit
+
+	if targetLang != "" && px.langTag != nil {
+		attr := "target-language"
+		if px.version2 {
+			attr = "trgLang"
+		}
+		tag := px.src[px.langTag.start:px.langTag.openEnd]
+		edits = append(edits, edit{px.langTag.start, px.langTag.openEnd, setXMLAttr(tag, attr, gotlai.ToHTMLLang(targetLang))})
+	}
+
+	for _, s := range px.segments {
+		translated, ok := translations[s.hash]
+		if !ok {
+			continue
+		}
+		items, err := parseSegment(translated, s.seg.paired)
+		if err != nil {
+			continue
+		}
+		content := s.leading + s.seg.render(items, escapeSegmentText) + s.trailing
+
+		// Target elements reuse the source element's namespace prefix
+		sourceTag := xmlTagName(px.src[s.source.start:s.source.openEnd])
+		targetTag := strings.TrimSuffix(sourceTag, "source") + "target"
+		open := "<" + targetTag + ">"
+		if s.target != nil {
+			targetTag = xmlTagName(px.src[s.target.start:s.target.openEnd])
+			open = strings.TrimSuffix(strings.TrimSuffix(px.src[s.target.start:s.target.openEnd], ">"), "/")
+			open = strings.TrimRight(open, " \t\r\n") + ">"
+		}
+
+		if px.version2 {
+			tag := px.src[s.segmentTag.start:s.segmentTag.openEnd]
+			tag = setXMLAttr(tag, "state", "translated")
+			if p.needsReview {
+				tag = setXMLAttr(tag, "subState", "gotlai:needs-review")
+			}
+			edits = append(edits, edit{s.segmentTag.start, s.segmentTag.openEnd, tag})
+		} else {
+			state := "translated"
+			if p.needsReview {
+				state = "needs-review-translation"
+			}
+			open = setXMLAttr(open, "state", state)
+		}
+
+		element := open + content + "</" + targetTag + ">"
+		if s.target != nil {
+			edits = append(edits, edit{s.target.start, s.target.end, element})
+		} else {
+			edits = append(edits, edit{s.source.end, s.source.end, "\n" + s.indent + element})
+		}
+	}
+
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+
+	var b strings.Builder
+	pos := 0
+	for _, e := range edits {
+		b.WriteString(px.src[pos:e.start])
+		b.WriteString(e.text)
+		pos = e.end
+	}
+	b.WriteString(px.src[pos:])
+
+	return b.String(), nil
+}
+
+// ValidateTranslation checks that a translation keeps every inline code placeholder.
+func (p *XLIFFProcessor) ValidateTranslation(node gotlai.TextNode, translated string) error {
+	if node.NodeType != "xliff_segment" {
+		return nil
+	}
+	_, err := parseSegment(translated, decodePlaceholders(node.Metadata["placeholders"]))
+	return err
+}
+
+// ContentType returns "xliff".
+func (p *XLIFFProcessor) ContentType() string {
+	return "xliff"
+}
+
+// xmlAttr returns the value of the attribute with the given local name.
+func xmlAttr(attrs []xml.Attr, name string) string {
+	for _, attr := range attrs {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// xmlInner returns the content of an element, or "" if it is self-closing.
+func xmlInner(src string, span xliffSpan) string {
+	closeStart := strings.LastIndex(src[:span.end], "</")
+	if closeStart < span.openEnd {
+		return ""
+	}
+	return src[span.openEnd:closeStart]
+}
+
+// xmlTagName returns the qualified name of a raw start tag.
+func xmlTagName(tag string) string {
+	name := strings.TrimPrefix(tag, "<")
+	if i := strings.IndexAny(name, " \t\r\n/>"); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// lineIndent returns the whitespace between the start of the line and offset, or "".
+func lineIndent(src string, offset int) string {
+	indent := src[strings.LastIndexByte(src[:offset], '\n')+1 : offset]
+	if strings.TrimSpace(indent) != "" {
+		return ""
+	}
+	return indent
+}
+
+// xmlAttrPatterns caches the attribute patterns of setXMLAttr by attribute name.
+var xmlAttrPatterns sync.Map
+
+// setXMLAttr sets an attribute in a raw start tag, replacing an existing value.
+func setXMLAttr(tag, name, value string) string {
+	value = strings.NewReplacer("&", "&amp;", `"`, "&quot;", "<", "&lt;").Replace(value)
+	pattern, ok := xmlAttrPatterns.Load(name)
+	if !ok {
+		pattern, _ = xmlAttrPatterns.LoadOrStore(name, regexp.MustCompile(`\s`+regexp.QuoteMeta(name)+`\s*=\s*("[^"]*"|'[^']*')`))
+	}
+	if m := pattern.(*regexp.Regexp).FindStringSubmatchIndex(tag); m != nil {
+		return tag[:m[2]] + `"` + value + `"` + tag[m[3]:]
+	}
+
+	end := len(tag) - 1
+	if strings.HasSuffix(tag, "/>") {
+		end--
+	}
+	return tag[:end] + " " + name + `="` + value + `"` + tag[end:]
+}
+
+// Verify XLIFFProcessor implements ContentProcessor, TranslationValidator and TargetApplier
+var (
+	_ ContentProcessor            = (*XLIFFProcessor)(nil)
+	_ gotlai.TranslationValidator = (*XLIFFProcessor)(nil)
+	_ gotlai.TargetApplier        = (*XLIFFProcessor)(nil)
+)
@@ -0,0 +1,234 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+)
+
+const xliff12Doc = `<?xml version="1.0" encoding="UTF-8"?>
+<xliff version="1.2" xmlns="urn:oasis:names:tc:xliff:document:1.2">
+  <file source-language="en" datatype="plaintext" original="app">
+    <body>
+      <trans-unit id="1" resname="home.greeting">
+        <source>Hello <g id="1">dear</g> user<x id="2"/>!</source>
+        <note>Shown after login</note>
+      </trans-unit>
+      <trans-unit id="2">
+        <source>Save &amp; close</source>
+        <target state="needs-translation">Old</target>
+      </trans-unit>
+      <trans-unit id="3">
+        <source>Done</source>
+        <target state="translated">Hecho</target>
+      </trans-unit>
+      <trans-unit id="4" translate="no">
+        <source>ACME</source>
+      </trans-unit>
+      <trans-unit id="5">
+        <source>Delete <ph id="1">&lt;b&gt;</ph>now</source>
+        <alt-trans><source>Ignored</source></alt-trans>
+        <target/>
+      </trans-unit>
+    </body>
+  </file>
+</xliff>
+`
+
+const xliff20Doc = `<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="2.0" srcLang="en">
+  <file id="f1">
+    <unit id="u1" name="cart.title">
+      <notes><note>Page heading</note></notes>
+      <segment id="s1">
+        <source>Your <pc id="1">cart</pc></source>
+      </segment>
+      <segment id="s2" state="final">
+        <source>Checkout</source>
+        <target>Pagar</target>
+      </segment>
+    </unit>
+  </file>
+</xliff>
+`
+
+func TestXLIFFProcessor_Extract12(t *testing.T) {
+	p := NewXLIFFProcessor()
+
+	_, nodes, err := p.Extract(xliff12Doc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	var texts []string
+	for _, n := range nodes {
+		texts = append(texts, n.Text)
+	}
+
+	expected := []string{"Hello <x1>dear</x1> user<x2/>!", "Save &amp; close", "Delete <x1/>now"}
+	if strings.Join(texts, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, texts)
+	}
+
+	if nodes[0].Context != "XLIFF translation unit | resname: home.greeting | Shown after login | contains inline code placeholders" {
+		t.Errorf("Unexpected context %q", nodes[0].Context)
+	}
+	if nodes[0].Metadata["resname"] != "home.greeting" || nodes[0].Metadata["unit_id"] != "1" {
+		t.Errorf("Unexpected metadata %v", nodes[0].Metadata)
+	}
+}
+
+func TestXLIFFProcessor_ApplyTarget12(t *testing.T) {
+	p := NewXLIFFProcessor()
+
+	parsed, nodes, err := p.Extract(xliff12Doc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	translations := map[string]string{
+		nodes[0].Hash: "¡Hola<x2/> <x1>querido</x1> usuario!",
+		nodes[1].Hash: "Guardar &amp; cerrar",
+		nodes[2].Hash: "Borrar <x1/>ya",
+	}
+	result, err := p.ApplyTarget(parsed, nodes, translations, "es_ES")
+	if err != nil {
+		t.Fatalf("ApplyTarget failed: %v", err)
+	}
+
+	expected := strings.NewReplacer(
+		`original="app">`, `original="app" target-language="es-ES">`,
+		`user<x id="2"/>!</source>`, `user<x id="2"/>!</source>
+        <target state="translated">¡Hola<x id="2"/> <g id="1">querido</g> usuario!</target>`,
+		`<target state="needs-translation">Old</target>`, `<target state="translated">Guardar &amp; cerrar</target>`,
+		`<target/>`, `<target state="translated">Borrar <ph id="1">&lt;b&gt;</ph>ya</target>`,
+	).Replace(xliff12Doc)
+	if result != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, result)
+	}
+}
+
+func TestXLIFFProcessor_Apply20(t *testing.T) {
+	p := NewXLIFFProcessor(WithNeedsReview())
+
+	parsed, nodes, err := p.Extract(xliff20Doc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].Text != "Your <x1>cart</x1>" || nodes[0].Metadata["resname"] != "cart.title" {
+		t.Fatalf("Unexpected nodes %+v", nodes)
+	}
+	if !strings.Contains(nodes[0].Context, "Page heading") {
+		t.Errorf("Expected note in context, got %q", nodes[0].Context)
+	}
+
+	result, err := p.ApplyTarget(parsed, nodes, map[string]string{nodes[0].Hash: "Tu <x1>carrito</x1>"}, "es_ES")
+	if err != nil {
+		t.Fatalf("ApplyTarget failed: %v", err)
+	}
+
+	expected := strings.NewReplacer(
+		`srcLang="en">`, `srcLang="en" trgLang="es-ES">`,
+		`<segment id="s1">`, `<segment id="s1" state="translated" subState="gotlai:needs-review">`,
+		`<source>Your <pc id="1">cart</pc></source>`, `<source>Your <pc id="1">cart</pc></source>
+        <target>Tu <pc id="1">carrito</pc></target>`,
+	).Replace(xliff20Doc)
+	if result != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, result)
+	}
+}
+
+func TestXLIFFProcessor_SegmentState20(t *testing.T) {
+	p := NewXLIFFProcessor()
+	src := `<xliff version="2.0" srcLang="en" trgLang="de"><file id="f"><unit id="u">
+<segment state="initial"><source>Hello</source><target>Hallo?</target></segment>
+<segment state="translated"><source>Bye</source><target>Tschüss</target></segment>
+<segment><source>Yes</source><target>Ja</target></segment>
+</unit></file></xliff>`
+
+	parsed, nodes, err := p.Extract(src)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].Text != "Hello" {
+		t.Fatalf("Expected only the initial segment, got %+v", nodes)
+	}
+
+	result, err := p.Apply(parsed, nodes, map[string]string{nodes[0].Hash: "Hallo"})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	expected := strings.Replace(src, `<segment state="initial"><source>Hello</source><target>Hallo?</target>`,
+		`<segment state="translated"><source>Hello</source><target>Hallo</target>`, 1)
+	if result != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, result)
+	}
+}
+
+func TestXLIFFProcessor_NeedsReview12(t *testing.T) {
+	p := NewXLIFFProcessor(WithNeedsReview())
+	src := `<xliff version="1.2"><file target-language="fr"><body><trans-unit id="a"><source>Hi</source></trans-unit></body></file></xliff>`
+
+	parsed, nodes, err := p.Extract(src)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	result, err := p.ApplyTarget(parsed, nodes, bracketAll(nodes), "de_DE")
+	if err != nil {
+		t.Fatalf("ApplyTarget failed: %v", err)
+	}
+
+	expected := `<xliff version="1.2"><file target-language="fr"><body><trans-unit id="a"><source>Hi</source>
+<target state="needs-review-translation">[Hi]</target></trans-unit></body></file></xliff>`
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestXLIFFProcessor_ValidateTranslation(t *testing.T) {
+	p := NewXLIFFProcessor()
+
+	parsed, nodes, err := p.Extract(xliff12Doc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if err := p.ValidateTranslation(nodes[0], "Hola usuario"); err == nil {
+		t.Error("Expected validation error for missing inline codes")
+	}
+
+	result, err := p.Apply(parsed, nodes, map[string]string{nodes[0].Hash: "Hola usuario"})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if result != xliff12Doc {
+		t.Errorf("Expected document unchanged for invalid translation, got:\n%s", result)
+	}
+}
+
+func TestXLIFFProcessor_InvalidXML(t *testing.T) {
+	p := NewXLIFFProcessor()
+	if _, _, err := p.Extract(`<xliff version="1.2"><file><trans-unit>`); err == nil {
+		t.Error("Expected error for unterminated XLIFF")
+	}
+}
+
+func TestXLIFFProcessor_ContentType(t *testing.T) {
+	p := NewXLIFFProcessor()
+	if p.ContentType() != "xliff" {
+		t.Errorf("Expected 'xliff', got %q", p.ContentType())
+	}
+}
+
+func TestSetXMLAttr(t *testing.T) {
+	tests := []struct {
+		tag, name, value, expected string
+	}{
+		{`<target state='new'>`, "state", "translated", `<target state="translated">`},
+		{`<segment subState="x" state="initial">`, "state", "a&b", `<segment subState="x" state="a&amp;b">`},
+		{`<item quantity="other"/>`, "quantity", "few", `<item quantity="few"/>`},
+		{`<file>`, "target-language", "de", `<file target-language="de">`},
+	}
+	for _, tt := range tests {
+		if got := setXMLAttr(tt.tag, tt.name, tt.value); got != tt.expected {
+			t.Errorf("setXMLAttr(%q, %q) = %q, want %q", tt.tag, tt.name, got, tt.expected)
+		}
+	}
+}
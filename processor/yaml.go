@@ -0,0 +1,395 @@
+package processor
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ZaguanLabs/gotlai"
+)
+
+// yamlNonStringPattern matches plain scalars that YAML reads as booleans or null.
+var yamlNonStringPattern = regexp.MustCompile(`^(?i:true|false|yes|no|on|off|null|~)$`)
+
+// YAMLProcessor extracts and applies translations to YAML files such as Rails
+// locale bundles and Hugo data files. String scalars in block mappings and
+// sequences are translated, including literal (|) and folded (>) block
+// scalars; numbers, booleans, aliases and flow collections are kept. When the
+// file has a single root key naming a locale (Rails-style "en:"), key paths
+// are relative to it and it is renamed to the target language. Translations
+// are spliced into the source, so comments, anchors and key order are kept.
+type YAMLProcessor struct {
+	keys KeyFilter
+}
+
+// YAMLProcessorOption configures the YAML processor.
+type YAMLProcessorOption func(*YAMLProcessor)
+
+// WithYAMLKeys restricts translation to strings whose key path passes filter,
+// e.g. KeyFilter{Include: []string{"**.title", "**.description"}}.
+func WithYAMLKeys(filter KeyFilter) YAMLProcessorOption {
+	return func(p *YAMLProcessor) {
+		p.keys = filter
+	}
+}
+
+// NewYAMLProcessor creates a new YAML processor.
+func NewYAMLProcessor(opts ...YAMLProcessorOption) *YAMLProcessor {
+	p := &YAMLProcessor{}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// yamlFrame is an open mapping key or sequence item while scanning lines.
+type yamlFrame struct {
+	indent int
+	key    string
+	seq    bool
+	index  int
+}
+
+// Extract parses YAML and extracts its translatable strings.
+func (p *YAMLProcessor) Extract(content string) (interface{}, []gotlai.TextNode, error) {
+	pc, err := parseYAML(content)
+	if err != nil {
+		return nil, nil, &gotlai.ProcessorError{
+			Message:     "failed to parse YAML",
+			Cause:       err,
+			ContentType: "yaml",
+		}
+	}
+
+	return pc, configNodes(pc, p.keys, "YAML"), nil
+}
+
+// parseYAML scans the block structure of a YAML stream line by line.
+func parseYAML(src string) (*parsedConfig, error) {
+	pc := &parsedConfig{src: src}
+	lines := splitLines(src, 0)
+
+	var stack []*yamlFrame
+	var roots []string
+
+	keyPath := func() string {
+		parts := make([]string, len(stack))
+		for i, f := range stack {
+			parts[i] = f.key
+			if f.seq {
+				parts[i] = strconv.Itoa(f.index)
+			}
+		}
+		return strings.Join(parts, ".")
+	}
+
+	for i := 0; i < len(lines); i++ {
+		start, end := lines[i][0], lines[i][1]
+		line := src[start:end]
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || trimmed[0] == '#' {
+			continue
+		}
+		if trimmed[0] == '\t' {
+			return nil, fmt.Errorf("line %d: tabs are not allowed in indentation", i+1)
+		}
+
+		col := len(line) - len(trimmed)
+		if col == 0 && (trimmed[0] == '%' || strings.HasPrefix(trimmed, "---") || trimmed == "...") {
+			stack = nil
+			continue
+		}
+
+		// Sequence items, possibly holding a mapping ("- key: value")
+		parent := col
+		for trimmed == "-" || strings.HasPrefix(trimmed, "- ") {
+			for len(stack) > 0 && stack[len(stack)-1].indent > col {
+				stack = stack[:len(stack)-1]
+			}
+			if top := len(stack) - 1; top >= 0 && stack[top].indent == col && stack[top].seq {
+				stack[top].index++
+			} else {
+				stack = append(stack, &yamlFrame{indent: col, seq: true})
+			}
+			parent = col
+			rest := strings.TrimLeft(trimmed[1:], " ")
+			col += len(trimmed) - len(rest)
+			trimmed = rest
+		}
+		if trimmed == "" || trimmed[0] == '#' {
+			continue
+		}
+
+		value, valueCol := trimmed, col
+		if key, keyStart, keyEnd, valueOffset, ok := yamlKey(trimmed); ok {
+			for len(stack) > 0 && stack[len(stack)-1].indent >= col {
+				stack = stack[:len(stack)-1]
+			}
+			stack = append(stack, &yamlFrame{indent: col, key: key})
+			if col == 0 {
+				roots = append(roots, key)
+				pc.localeKeys = append(pc.localeKeys, [2]int{start + keyStart, start + keyEnd})
+			}
+			parent = col
+			value, valueCol = trimmed[valueOffset:], col+valueOffset
+		}
+
+		// Anchors and tags before the value
+		nonString := false
+		for value != "" && (value[0] == '&' || value[0] == '!') {
+			n := strings.IndexAny(value, " \t")
+			if n < 0 {
+				value = ""
+				break
+			}
+			if value[0] == '!' && value[:n] != "!!str" {
+				nonString = true
+			}
+			rest := strings.TrimLeft(value[n:], " \t")
+			valueCol += len(value) - len(rest)
+			value = rest
+		}
+		if value == "" || value[0] == '#' {
+			continue
+		}
+
+		// Lines indented past the parent continue the value
+		last := i
+		blockIndent := -1
+		for j := i + 1; j < len(lines); j++ {
+			l := src[lines[j][0]:lines[j][1]]
+			t := strings.TrimLeft(l, " ")
+			if t == "" {
+				continue
+			}
+			indent := len(l) - len(t)
+			if indent <= parent || (blockIndent >= 0 && indent < blockIndent) {
+				break
+			}
+			if blockIndent < 0 {
+				blockIndent = indent
+			}
+			last = j
+		}
+
+		if value[0] == '|' || value[0] == '>' {
+			if !nonString && last > i && !strings.ContainsAny(strings.Fields(value)[0], "0123456789") {
+				if v := yamlBlockValue(src, lines[i+1:last+1], value[:1], blockIndent); v != nil {
+					v.keyPath = keyPath()
+					pc.values = append(pc.values, v)
+				}
+			}
+			i = last
+			continue
+		}
+		if last > i {
+			// Multi-line flow scalars and collections are kept as they are
+			i = last
+			continue
+		}
+		if nonString || value[0] == '*' {
+			continue
+		}
+
+		v, ok := frontMatterScalar(value, false)
+		if !ok || !hasLetter(v.text) || (v.block.quote == "" && yamlNonStringPattern.MatchString(v.text)) {
+			continue
+		}
+		pc.values = append(pc.values, &configValue{
+			keyPath: keyPath(),
+			text:    v.text,
+			hash:    gotlai.HashText(strings.TrimSpace(v.text)),
+			start:   start + valueCol,
+			end:     start + valueCol + v.block.end,
+			quote:   v.block.quote,
+		})
+	}
+
+	// A single root key naming a locale is a Rails-style locale bundle
+	if len(roots) > 0 && isLocaleKey(roots[0]) {
+		pc.localeRoot = roots[0]
+		for _, root := range roots {
+			if root != pc.localeRoot {
+				pc.localeRoot = ""
+				break
+			}
+		}
+	}
+	if pc.localeRoot == "" {
+		pc.localeKeys = nil
+	} else {
+		pc.stripLocaleRoot()
+	}
+
+	return pc, nil
+}
+
+// yamlKey parses the mapping key at the start of s. It returns the key, the
+// range of its text without quotes, and the offset of the value.
+func yamlKey(s string) (key string, keyStart, keyEnd, valueOffset int, ok bool) {
+	i := 0
+	switch s[0] {
+	case '"':
+		end := closingQuote(s)
+		if end < 0 {
+			return "", 0, 0, 0, false
+		}
+		k, err := strconv.Unquote(s[:end+1])
+		if err != nil {
+			return "", 0, 0, 0, false
+		}
+		key, keyStart, keyEnd, i = k, 1, end, end+1
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+		if i == len(s) || s[i] != ':' {
+			return "", 0, 0, 0, false
+		}
+	case '\'':
+		end := 1
+		for ; end < len(s); end++ {
+			if s[end] == '\'' {
+				if end+1 < len(s) && s[end+1] == '\'' {
+					end++
+					continue
+				}
+				break
+			}
+		}
+		if end >= len(s) {
+			return "", 0, 0, 0, false
+		}
+		key, keyStart, keyEnd, i = strings.ReplaceAll(s[1:end], "''", "'"), 1, end, end+1
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+		if i == len(s) || s[i] != ':' {
+			return "", 0, 0, 0, false
+		}
+	default:
+		if strings.IndexByte("[{#&*!|>%@`?,", s[0]) >= 0 {
+			return "", 0, 0, 0, false
+		}
+		for {
+			n := strings.IndexByte(s[i:], ':')
+			if n < 0 {
+				return "", 0, 0, 0, false
+			}
+			i += n
+			if i+1 == len(s) || s[i+1] == ' ' || s[i+1] == '\t' {
+				break
+			}
+			i++
+		}
+		if strings.Contains(s[:i], " #") {
+			return "", 0, 0, 0, false
+		}
+		key = strings.TrimRight(s[:i], " \t")
+		keyStart, keyEnd = 0, len(key)
+	}
+
+	// Skip the colon and the whitespace after it
+	i++
+	if i < len(s) && s[i] != ' ' && s[i] != '\t' {
+		return "", 0, 0, 0, false
+	}
+	for i < len(s) && (s[i] == ' ' || s[i] == '\t') {
+		i++
+	}
+	return key, keyStart, keyEnd, i, true
+}
+
+// yamlBlockValue reads the content lines of a literal (|) or folded (>) block
+// scalar. It returns nil when the content has no text.
+func yamlBlockValue(src string, lines [][2]int, style string, indent int) *configValue {
+	// Content starts at the first non-empty line and ends at the last
+	first, last := -1, -1
+	for k, l := range lines {
+		if strings.TrimSpace(src[l[0]:l[1]]) != "" {
+			if first < 0 {
+				first = k
+			}
+			last = k
+		}
+	}
+	if first < 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	for k := first; k <= last; k++ {
+		text := ""
+		if l := src[lines[k][0]:lines[k][1]]; len(l) > indent {
+			text = l[indent:]
+		}
+
+		switch {
+		case k == first:
+		case style == "|":
+			b.WriteByte('\n')
+		case text == "":
+			b.WriteByte('\n')
+			continue
+		case strings.TrimSpace(src[lines[k-1][0]:lines[k-1][1]]) != "":
+			b.WriteByte(' ')
+		}
+		b.WriteString(text)
+	}
+
+	text := b.String()
+	if !hasLetter(text) {
+		return nil
+	}
+	return &configValue{
+		text:   text,
+		hash:   gotlai.HashText(strings.TrimSpace(text)),
+		start:  lines[first][0] + indent,
+		end:    lines[last][1],
+		quote:  style,
+		indent: strings.Repeat(" ", indent),
+	}
+}
+
+// yamlBlockScalar encodes text as the content lines of a block scalar. Line
+// breaks of folded scalars are written as empty lines.
+func yamlBlockScalar(text, style, indent string) string {
+	lines := strings.Split(text, "\n")
+	if style == ">" {
+		lines = strings.Split(strings.ReplaceAll(text, "\n", "\n\n"), "\n")
+	}
+
+	var b strings.Builder
+	for k, line := range lines {
+		if k > 0 {
+			b.WriteByte('\n')
+			if line != "" {
+				b.WriteString(indent)
+			}
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+// ApplyTarget splices translated strings into the YAML source and renames the
+// root locale key to the target language.
+func (p *YAMLProcessor) ApplyTarget(parsed interface{}, nodes []gotlai.TextNode, translations map[string]string, targetLang string) (string, error) {
+	return applyConfig(parsed, translations, targetLang, "yaml")
+}
+
+// Apply splices translated strings into the YAML source.
+func (p *YAMLProcessor) Apply(parsed interface{}, nodes []gotlai.TextNode, translations map[string]string) (string, error) {
+	return applyConfig(parsed, translations, "", "yaml")
+}
+
+// ContentType returns "yaml".
+func (p *YAMLProcessor) ContentType() string {
+	return "yaml"
+}
+
+// Verify YAMLProcessor implements ContentProcessor and TargetApplier
+var (
+	_ ContentProcessor     = (*YAMLProcessor)(nil)
+	_ gotlai.TargetApplier = (*YAMLProcessor)(nil)
+)
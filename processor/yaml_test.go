@@ -0,0 +1,232 @@
+package processor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ZaguanLabs/gotlai"
+)
+
+const railsLocaleDoc = `# Rails locale bundle
+en:
+  defaults: &defaults
+    save: Save
+  users:
+    <<: *defaults
+    title: "Users"
+    empty: 'No users yet'
+    count: 3
+    enabled: true
+    help: |
+      Invite people
+      by email.
+    intro: >
+      Welcome to
+      the app.
+
+      Enjoy!
+  days:
+    - Monday
+    - Tuesday
+`
+
+func TestYAMLProcessor_Extract(t *testing.T) {
+	p := NewYAMLProcessor()
+
+	_, nodes, err := p.Extract(railsLocaleDoc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	var texts, paths []string
+	for _, n := range nodes {
+		texts = append(texts, n.Text)
+		paths = append(paths, n.Metadata["key_path"])
+	}
+
+	expected := []string{"Save", "Users", "No users yet", "Invite people\nby email.", "Welcome to the app.\nEnjoy!", "Monday", "Tuesday"}
+	if strings.Join(texts, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, texts)
+	}
+
+	expectedPaths := []string{"defaults.save", "users.title", "users.empty", "users.help", "users.intro", "days.0", "days.1"}
+	if strings.Join(paths, "|") != strings.Join(expectedPaths, "|") {
+		t.Errorf("Expected paths %q, got %q", expectedPaths, paths)
+	}
+
+	if nodes[1].Metadata["locale_root"] != "en" {
+		t.Errorf("Expected locale root, got %v", nodes[1].Metadata)
+	}
+	if nodes[1].Context != `UI message "users.title" in a YAML resource file; keep placeholders such as {name} and {{name}} unchanged` {
+		t.Errorf("Unexpected context %q", nodes[1].Context)
+	}
+}
+
+func TestYAMLProcessor_ApplyTarget(t *testing.T) {
+	p := NewYAMLProcessor()
+
+	parsed, nodes, err := p.Extract(railsLocaleDoc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	translations := map[string]string{
+		nodes[0].Hash: "Enregistrer",
+		nodes[1].Hash: "Utilisateurs",
+		nodes[2].Hash: "Pas encore d'utilisateurs",
+		nodes[3].Hash: "Invitez des personnes\npar e-mail.",
+		nodes[4].Hash: "Bienvenue dans l'application.\nBonne visite !",
+		nodes[5].Hash: "Lundi",
+	}
+
+	result, err := p.ApplyTarget(parsed, nodes, translations, "fr")
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	expected := `# Rails locale bundle
+fr:
+  defaults: &defaults
+    save: Enregistrer
+  users:
+    <<: *defaults
+    title: "Utilisateurs"
+    empty: 'Pas encore d''utilisateurs'
+    count: 3
+    enabled: true
+    help: |
+      Invitez des personnes
+      par e-mail.
+    intro: >
+      Bienvenue dans l'application.
+
+      Bonne visite !
+  days:
+    - Lundi
+    - Tuesday
+`
+	if result != expected {
+		t.Errorf("Unexpected output:\n%s", result)
+	}
+
+	// Apply keeps the locale key
+	result, err = p.Apply(parsed, nodes, translations)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if !strings.HasPrefix(result, "# Rails locale bundle\nen:\n") {
+		t.Errorf("Expected the locale key to be kept, got:\n%s", result)
+	}
+}
+
+func TestYAMLProcessor_Sequences(t *testing.T) {
+	p := NewYAMLProcessor()
+
+	content := `---
+menu:
+- name: Home
+  url: /
+- name: "About us"
+  weight: 2
+tags: [a, b]
+quote: "one
+  two"
+`
+	parsed, nodes, err := p.Extract(content)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	var paths []string
+	for _, n := range nodes {
+		paths = append(paths, n.Metadata["key_path"]+"="+n.Text)
+		if _, ok := n.Metadata["locale_root"]; ok {
+			t.Errorf("Unexpected locale root in %v", n.Metadata)
+		}
+	}
+	expected := []string{"menu.0.name=Home", "menu.1.name=About us"}
+	if strings.Join(paths, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, paths)
+	}
+
+	result, err := p.ApplyTarget(parsed, nodes, map[string]string{nodes[1].Hash: `Über "uns"`}, "de")
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if !strings.Contains(result, `- name: "Über \"uns\""`) || !strings.Contains(result, "- name: Home\n") {
+		t.Errorf("Unexpected output:\n%s", result)
+	}
+}
+
+func TestYAMLProcessor_KeyFilter(t *testing.T) {
+	p := NewYAMLProcessor(WithYAMLKeys(KeyFilter{Include: []string{"users.*"}, Exclude: []string{"**.help"}}))
+
+	_, nodes, err := p.Extract(railsLocaleDoc)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	var paths []string
+	for _, n := range nodes {
+		paths = append(paths, n.Metadata["key_path"])
+	}
+	expected := []string{"users.title", "users.empty", "users.intro"}
+	if strings.Join(paths, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, paths)
+	}
+}
+
+func TestYAMLProcessor_TabIndentation(t *testing.T) {
+	p := NewYAMLProcessor()
+
+	_, _, err := p.Extract("en:\n\ttitle: Hello\n")
+	var perr *gotlai.ProcessorError
+	if !errors.As(err, &perr) || perr.Message != "failed to parse YAML" {
+		t.Errorf("Expected a parse error, got %v", err)
+	}
+}
+
+func TestYAMLProcessor_NotALocale(t *testing.T) {
+	p := NewYAMLProcessor()
+
+	parsed, nodes, err := p.Extract("faq:\n  title: Questions\n")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].Metadata["key_path"] != "faq.title" {
+		t.Fatalf("Unexpected nodes %v", nodes)
+	}
+
+	result, err := p.ApplyTarget(parsed, nodes, map[string]string{nodes[0].Hash: "Fragen"}, "de")
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if result != "faq:\n  title: Fragen\n" {
+		t.Errorf("Unexpected output %q", result)
+	}
+
+	// Plain translations that read as booleans are quoted
+	result, err = p.Apply(parsed, nodes, map[string]string{nodes[0].Hash: "No"})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if result != "faq:\n  title: \"No\"\n" {
+		t.Errorf("Unexpected output %q", result)
+	}
+}
+
+func TestLocaleKey(t *testing.T) {
+	tests := []struct {
+		root, target, expected string
+	}{
+		{"en", "fr", "fr"},
+		{"en", "pt_BR", "pt-BR"},
+		{"en_US", "pt-BR", "pt_BR"},
+	}
+	for _, tt := range tests {
+		if got := localeKey(tt.root, tt.target); got != tt.expected {
+			t.Errorf("localeKey(%q, %q) = %q, want %q", tt.root, tt.target, got, tt.expected)
+		}
+	}
+}
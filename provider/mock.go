@@ -3,13 +3,16 @@ package provider
 import (
 	"context"
 	"fmt"
+	"sync"
 )
 
-// MockProvider is a mock AI provider for testing.
+// MockProvider is a mock AI provider for testing. It is safe for concurrent use.
 type MockProvider struct {
 	Translations map[string]string // Map of source text to translation
 	CallCount    int               // Number of times Translate was called
 	LastRequest  *TranslateRequest // Last request received
+
+	mu sync.Mutex
 }
 
 // NewMockProvider creates a new mock provider with default translations.
@@ -26,6 +29,9 @@ func NewMockProvider() *MockProvider {
 
 // Translate returns mock translations.
 func (m *MockProvider) Translate(ctx context.Context, req TranslateRequest) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.CallCount++
 	m.LastRequest = &req
 
@@ -44,6 +50,9 @@ func (m *MockProvider) Translate(ctx context.Context, req TranslateRequest) ([]s
 
 // Reset resets the call count and last request.
 func (m *MockProvider) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.CallCount = 0
 	m.LastRequest = nil
 }
@@ -92,6 +92,11 @@ func (p *OpenAIProvider) Translate(ctx context.Context, req TranslateRequest) ([
 	return translations, nil
 }
 
+// Model returns the OpenAI model used for translations.
+func (p *OpenAIProvider) Model() string {
+	return p.model
+}
+
 func (p *OpenAIProvider) buildSystemPrompt(req TranslateRequest) string {
 	sourceLang := req.SourceLang
 	if sourceLang == "" {
@@ -129,6 +134,8 @@ Translate the provided texts into idiomatic %s.
 - **Idioms**: Never translate idioms literally. Replace English idioms with natural %s equivalents.
 - **HTML/Code Safety**: Do NOT translate HTML tags, class names, IDs, attributes, URLs, email addresses, or content inside backticks or <code> blocks.
 - **Interpolation**: Do NOT translate variables or placeholders (e.g., {{name}}, {count}, %%s, $1).
+- **Inline Markup**: Keep inline placeholders such as <x1>...</x1> and <x2/> exactly once each. You may move them to match natural word order, but keep the text they wrap inside them.
+- **Length Limits**: When a text's context gives a character limit (e.g. SEO titles and descriptions), keep the translation within it.
 - **Formatting**: Preserve meaningful whitespace (leading/trailing spaces, multiple spaces, newlines). Use idiomatic punctuation for the target language.
 - **Context Hints**: If you see {{__ctx__:...}}, use that hint to disambiguate the translation, then REMOVE the hint from your output.`, targetName, contextText, styleDesc, targetName, targetName)
 
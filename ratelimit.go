@@ -130,3 +130,8 @@ func (p *RateLimitedProvider) Translate(ctx context.Context, req TranslateReques
 func (p *RateLimitedProvider) Limiter() *RateLimiter {
 	return p.limiter
 }
+
+// Model reports the model of the wrapped provider, if it reports one.
+func (p *RateLimitedProvider) Model() string {
+	return providerModel(p.provider)
+}
@@ -108,3 +108,8 @@ func (p *RetryableProvider) Translate(ctx context.Context, req TranslateRequest)
 		return p.provider.Translate(ctx, req)
 	})
 }
+
+// Model reports the model of the wrapped provider, if it reports one.
+func (p *RetryableProvider) Model() string {
+	return providerModel(p.provider)
+}
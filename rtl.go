@@ -0,0 +1,494 @@
+package gotlai
+
+import (
+	"maps"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// DefaultRTLClassMap mirrors common Bootstrap and Tailwind directional classes.
+// Keys ending in "*" match any class with that prefix, e.g. "ml-*" maps "ml-4" to "mr-4".
+var DefaultRTLClassMap = map[string]string{
+	"text-left":   "text-right",
+	"text-right":  "text-left",
+	"float-left":  "float-right",
+	"float-right": "float-left",
+	"pull-left":   "pull-right",
+	"pull-right":  "pull-left",
+	"ml-*":        "mr-*",
+	"mr-*":        "ml-*",
+	"-ml-*":       "-mr-*",
+	"-mr-*":       "-ml-*",
+	"pl-*":        "pr-*",
+	"pr-*":        "pl-*",
+	"left-*":      "right-*",
+	"right-*":     "left-*",
+	"border-l":    "border-r",
+	"border-r":    "border-l",
+	"border-l-*":  "border-r-*",
+	"border-r-*":  "border-l-*",
+	"rounded-l":   "rounded-r",
+	"rounded-r":   "rounded-l",
+	"rounded-l-*": "rounded-r-*",
+	"rounded-r-*": "rounded-l-*",
+}
+
+// ltrRunPattern matches runs of Latin words, codes and URLs, e.g. "SKU-123" or
+// "https://example.com/a?b=1".
+var ltrRunPattern = regexp.MustCompile(`[A-Za-z0-9][\w.:/@#%?=&+~-]*(?:[ \t]+[A-Za-z0-9][\w.:/@#%?=&+~-]*)*`)
+
+// hasLatinLetter matches text containing a Latin letter.
+var hasLatinLetter = regexp.MustCompile(`[A-Za-z]`)
+
+// rtlSkipElements contains elements whose content is not isolated: raw text,
+// code, and elements that cannot contain <bdi>. Elements with a dir other than
+// "rtl" are skipped too.
+var rtlSkipElements = map[string]bool{
+	"script":   true,
+	"style":    true,
+	"code":     true,
+	"pre":      true,
+	"textarea": true,
+	"noscript": true,
+	"title":    true,
+	"option":   true,
+	"bdi":      true,
+	"bdo":      true,
+	"svg":      true,
+	"math":     true,
+}
+
+// rtlMirroredValues contains CSS properties whose left/right keyword values are mirrored.
+var rtlMirroredValues = map[string]bool{
+	"text-align":            true,
+	"float":                 true,
+	"clear":                 true,
+	"background-position":   true,
+	"background-position-x": true,
+	"transform-origin":      true,
+}
+
+// rtlBoxShorthands contains CSS shorthands whose 4-value form is top, right, bottom, left.
+var rtlBoxShorthands = map[string]bool{
+	"margin":         true,
+	"padding":        true,
+	"inset":          true,
+	"border-width":   true,
+	"border-style":   true,
+	"border-color":   true,
+	"scroll-margin":  true,
+	"scroll-padding": true,
+}
+
+// entityPattern matches character references in raw HTML text.
+var entityPattern = regexp.MustCompile(`&(?:#[0-9]+|#[xX][0-9A-Fa-f]+|[A-Za-z][A-Za-z0-9]*);?`)
+
+// RTLRewriter is an HTMLPostProcessor for right-to-left target languages.
+// It does nothing for left-to-right targets. Tags and text it does not change
+// are left byte for byte.
+type RTLRewriter struct {
+	// IsolateLTR wraps embedded left-to-right runs such as product codes and
+	// URLs in <bdi>, and sets dir="auto" on elements whose whole text is such a run.
+	IsolateLTR bool
+
+	// ClassMap mirrors directional class names. Keys ending in "*" match prefixes.
+	// Responsive variants such as "md:ml-4" are mirrored too.
+	ClassMap map[string]string
+
+	// MirrorStyles swaps left and right in inline style attributes: property
+	// names (margin-left, border-top-left-radius), left/right keywords of
+	// text-align, float, clear, background-position and transform-origin,
+	// 4-value box shorthands (margin: 0 1px 0 2px) and border-radius corners.
+	// Percentage and length positions, transforms and gradients are not
+	// mirrored, and logical properties need no mirroring.
+	MirrorStyles bool
+}
+
+// NewRTLRewriter creates an RTLRewriter with every rewrite enabled and a copy of DefaultRTLClassMap.
+func NewRTLRewriter() *RTLRewriter {
+	return &RTLRewriter{
+		IsolateLTR:   true,
+		ClassMap:     maps.Clone(DefaultRTLClassMap),
+		MirrorStyles: true,
+	}
+}
+
+// rtlToken is a token awaiting rewriting.
+type rtlToken struct {
+	tokenType html.TokenType
+	raw       string
+	token     html.Token
+}
+
+// PostProcessHTML rewrites content for an RTL targetLang.
+func (r *RTLRewriter) PostProcessHTML(content string, targetLang string) (string, error) {
+	if !IsRTL(targetLang) {
+		return content, nil
+	}
+
+	var tokens []rtlToken
+	z := html.NewTokenizer(strings.NewReader(content))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		raw := string(z.Raw())
+		tokens = append(tokens, rtlToken{tokenType: tt, raw: raw, token: z.Token()})
+	}
+
+	type openElement struct {
+		tag  string
+		skip bool
+	}
+	var stack []openElement
+	skipping := func() bool {
+		for _, el := range stack {
+			if el.skip {
+				return true
+			}
+		}
+		return false
+	}
+
+	var b strings.Builder
+	for i, t := range tokens {
+		switch t.tokenType {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := t.token
+			changed := r.mirrorAttrs(&tok)
+
+			// Elements holding only an LTR run get dir="auto" instead of <bdi>
+			if r.IsolateLTR && !skipping() && !hasAttr(tok, "dir") && r.onlyLTR(tokens, i) {
+				setAttr(&tok, "dir", "auto")
+				changed = true
+			}
+
+			if changed {
+				b.WriteString(tok.String())
+			} else {
+				b.WriteString(t.raw)
+			}
+
+			if t.tokenType == html.StartTagToken && !voidElement(tok.Data) {
+				stack = append(stack, openElement{
+					tag:  tok.Data,
+					skip: rtlSkipElements[tok.Data] || (hasAttr(tok, "dir") && !strings.EqualFold(getAttr(tok, "dir"), "rtl")),
+				})
+			}
+		case html.EndTagToken:
+			for j := len(stack) - 1; j >= 0; j-- {
+				if stack[j].tag == t.token.Data {
+					stack = stack[:j]
+					break
+				}
+			}
+			b.WriteString(t.raw)
+		case html.TextToken:
+			if r.IsolateLTR && !skipping() {
+				b.WriteString(isolateLTR(t.raw))
+			} else {
+				b.WriteString(t.raw)
+			}
+		default:
+			b.WriteString(t.raw)
+		}
+	}
+
+	return b.String(), nil
+}
+
+// onlyLTR reports whether the start tag at i is followed by a single text token
+// that is entirely an LTR run and then its end tag.
+func (r *RTLRewriter) onlyLTR(tokens []rtlToken, i int) bool {
+	if tokens[i].tokenType != html.StartTagToken || i+2 >= len(tokens) || rtlSkipElements[tokens[i].token.Data] {
+		return false
+	}
+	text, end := tokens[i+1], tokens[i+2]
+	if text.tokenType != html.TextToken || end.tokenType != html.EndTagToken || end.token.Data != tokens[i].token.Data {
+		return false
+	}
+
+	trimmed := strings.TrimSpace(text.token.Data)
+	return trimmed != "" && ltrRunPattern.FindString(trimmed) == trimmed && ltrRun(trimmed) == trimmed
+}
+
+// mirrorAttrs mirrors class and style attributes, reporting whether any changed.
+func (r *RTLRewriter) mirrorAttrs(tok *html.Token) bool {
+	changed := false
+	for i, attr := range tok.Attr {
+		val := attr.Val
+		switch {
+		case attr.Key == "class" && len(r.ClassMap) > 0:
+			val = mirrorClasses(val, r.ClassMap)
+		case attr.Key == "style" && r.MirrorStyles:
+			val = mirrorStyle(val)
+		}
+		if val != attr.Val {
+			tok.Attr[i].Val = val
+			changed = true
+		}
+	}
+	return changed
+}
+
+// ltrRun returns s trimmed to its LTR run, without trailing punctuation, or "" if
+// s has no Latin letters.
+func ltrRun(s string) string {
+	if !hasLatinLetter.MatchString(s) {
+		return ""
+	}
+	return strings.TrimRight(s, ".,:;?!")
+}
+
+// isolateLTR wraps LTR runs in raw text with <bdi>. Runs are found in the
+// decoded text, but the raw text, entities included, is written unchanged.
+func isolateLTR(raw string) string {
+	text, offsets := unescapeWithOffsets(raw)
+
+	var b strings.Builder
+	pos := 0
+	for _, m := range ltrRunPattern.FindAllStringIndex(text, -1) {
+		run := ltrRun(text[m[0]:m[1]])
+		if run == "" {
+			continue
+		}
+		start, end := offsets[m[0]], offsets[m[0]+len(run)]
+		b.WriteString(raw[pos:start])
+		b.WriteString("<bdi>")
+		b.WriteString(raw[start:end])
+		b.WriteString("</bdi>")
+		pos = end
+	}
+	if pos == 0 {
+		return raw
+	}
+	b.WriteString(raw[pos:])
+
+	return b.String()
+}
+
+// unescapeWithOffsets decodes character references in raw text. offsets[i] is
+// the offset in raw of the character starting at byte i of the decoded text;
+// offsets[len(text)] is len(raw).
+func unescapeWithOffsets(raw string) (string, []int) {
+	var b strings.Builder
+	offsets := make([]int, 0, len(raw)+1)
+
+	pos := 0
+	for _, m := range entityPattern.FindAllStringIndex(raw, -1) {
+		for i := pos; i < m[0]; i++ {
+			offsets = append(offsets, i)
+		}
+		b.WriteString(raw[pos:m[0]])
+
+		decoded := html.UnescapeString(raw[m[0]:m[1]])
+		for range len(decoded) {
+			offsets = append(offsets, m[0])
+		}
+		b.WriteString(decoded)
+		pos = m[1]
+	}
+	for i := pos; i <= len(raw); i++ {
+		offsets = append(offsets, i)
+	}
+	b.WriteString(raw[pos:])
+
+	return b.String(), offsets
+}
+
+// mirrorClasses mirrors each class in a class attribute.
+func mirrorClasses(classes string, m map[string]string) string {
+	fields := strings.Fields(classes)
+	changed := false
+	for i, class := range fields {
+		// Keep responsive and state variants such as "md:" or "hover:"
+		variant, name := "", class
+		if j := strings.LastIndex(class, ":"); j >= 0 {
+			variant, name = class[:j+1], class[j+1:]
+		}
+		if mirrored := mirrorClass(name, m); mirrored != name {
+			fields[i] = variant + mirrored
+			changed = true
+		}
+	}
+	if !changed {
+		return classes
+	}
+	return strings.Join(fields, " ")
+}
+
+// mirrorClass maps a class through m, preferring exact matches over the longest prefix pattern.
+func mirrorClass(class string, m map[string]string) string {
+	if to, ok := m[class]; ok {
+		return to
+	}
+
+	best := ""
+	for from := range m {
+		prefix, ok := strings.CutSuffix(from, "*")
+		if ok && strings.HasPrefix(class, prefix) && len(from) > len(best) {
+			best = from
+		}
+	}
+	if best == "" {
+		return class
+	}
+	return strings.TrimSuffix(m[best], "*") + class[len(best)-1:]
+}
+
+// mirrorStyle swaps left and right in the declarations of an inline style.
+func mirrorStyle(style string) string {
+	decls := strings.Split(style, ";")
+	for i, decl := range decls {
+		prop, val, ok := strings.Cut(decl, ":")
+		if !ok {
+			continue
+		}
+
+		name := strings.TrimSpace(prop)
+		lower := strings.ToLower(name)
+		newName := swapLeftRight(lower)
+		newVal := val
+
+		if rtlMirroredValues[lower] {
+			newVal = swapValueKeywords(val)
+		}
+		if rtlBoxShorthands[lower] {
+			newVal = swapBoxValue(val)
+		}
+		if lower == "border-radius" {
+			newVal = swapRadiusValue(val)
+		}
+
+		if newName != lower || newVal != val {
+			if newName == lower {
+				newName = name
+			}
+			decls[i] = strings.Replace(prop, name, newName, 1) + ":" + newVal
+		}
+	}
+	return strings.Join(decls, ";")
+}
+
+// swapLeftRight swaps "left" and "right" parts of a hyphenated CSS name.
+func swapLeftRight(name string) string {
+	parts := strings.Split(name, "-")
+	for i, part := range parts {
+		switch part {
+		case "left":
+			parts[i] = "right"
+		case "right":
+			parts[i] = "left"
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// swapValueKeywords swaps the left and right keywords of a value, e.g.
+// "right 10px top" in background-position, keeping everything else.
+func swapValueKeywords(val string) string {
+	var b strings.Builder
+	i := 0
+	for i < len(val) {
+		j := i
+		for j < len(val) && (val[j] == '-' || ('a' <= val[j]|0x20 && val[j]|0x20 <= 'z')) {
+			j++
+		}
+		if j == i {
+			b.WriteByte(val[i])
+			i++
+			continue
+		}
+
+		word := val[i:j]
+		switch strings.ToLower(word) {
+		case "left":
+			word = "right"
+		case "right":
+			word = "left"
+		}
+		b.WriteString(word)
+		i = j
+	}
+	return b.String()
+}
+
+// swapBoxValue swaps the right and left values of a 4-value box shorthand.
+func swapBoxValue(val string) string {
+	trimmed := strings.TrimSpace(val)
+	if strings.ContainsAny(trimmed, "()") {
+		return val
+	}
+
+	body, important := splitImportant(trimmed)
+	fields := strings.Fields(body)
+	if len(fields) != 4 || fields[1] == fields[3] {
+		return val
+	}
+	fields[1], fields[3] = fields[3], fields[1]
+	return strings.Replace(val, trimmed, strings.Join(fields, " ")+important, 1)
+}
+
+// swapRadiusValue mirrors a border-radius shorthand. Each side of an optional
+// "/" lists the top-left, top-right, bottom-right and bottom-left radii.
+func swapRadiusValue(val string) string {
+	trimmed := strings.TrimSpace(val)
+	if strings.ContainsAny(trimmed, "()") {
+		return val
+	}
+
+	body, important := splitImportant(trimmed)
+	halves := strings.Split(body, "/")
+	if len(halves) > 2 {
+		return val
+	}
+
+	changed := false
+	for i, half := range halves {
+		f := strings.Fields(half)
+		var mirrored []string
+		switch len(f) {
+		case 1:
+			mirrored = f
+		case 2:
+			mirrored = []string{f[1], f[0]}
+		case 3:
+			mirrored = []string{f[1], f[0], f[1], f[2]}
+		case 4:
+			mirrored = []string{f[1], f[0], f[3], f[2]}
+		default:
+			return val
+		}
+		if strings.Join(mirrored, " ") != strings.Join(f, " ") {
+			changed = true
+		}
+		halves[i] = strings.Join(mirrored, " ")
+	}
+	if !changed {
+		return val
+	}
+	return strings.Replace(val, trimmed, strings.Join(halves, " / ")+important, 1)
+}
+
+// splitImportant splits a trimmed value into its body and " !important" suffix.
+func splitImportant(value string) (body, important string) {
+	if i := strings.Index(value, "!"); i >= 0 {
+		return strings.TrimSpace(value[:i]), " " + value[i:]
+	}
+	return value, ""
+}
+
+// voidElement reports whether an HTML element never has an end tag.
+func voidElement(tag string) bool {
+	switch tag {
+	case "area", "base", "br", "col", "embed", "hr", "img", "input", "link", "meta", "param", "source", "track", "wbr":
+		return true
+	}
+	return false
+}
+
+// Verify RTLRewriter implements HTMLPostProcessor
+var _ HTMLPostProcessor = (*RTLRewriter)(nil)
@@ -0,0 +1,123 @@
+package gotlai
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRTLRewriter_LTRTargetUnchanged(t *testing.T) {
+	content := `<p class="text-left">Order SKU-123</p>`
+	result, err := NewRTLRewriter().PostProcessHTML(content, "es_ES")
+	if err != nil {
+		t.Fatalf("PostProcessHTML failed: %v", err)
+	}
+	if result != content {
+		t.Errorf("Expected content unchanged, got %q", result)
+	}
+}
+
+func TestRTLRewriter_IsolateLTR(t *testing.T) {
+	content := `<html dir="rtl"><body>
+<p>اطلب المنتج SKU-123 الآن.</p>
+<p>زر https://example.com/help?x=1 للمساعدة</p>
+<p><span>ABC-42</span></p>
+<p>رقم 123 فقط</p>
+<code>x = 1</code>
+<p dir="ltr">Already LTR</p>
+<p>اشترِ A&amp;B</p>
+<p>&quot;مرحبا&quot;&nbsp;SKU&#45;7 &copy;</p>
+</body></html>`
+
+	result, err := NewRTLRewriter().PostProcessHTML(content, "ar_SA")
+	if err != nil {
+		t.Fatalf("PostProcessHTML failed: %v", err)
+	}
+
+	expected := []string{
+		`<p>اطلب المنتج <bdi>SKU-123</bdi> الآن.</p>`,
+		`<p>زر <bdi>https://example.com/help?x=1</bdi> للمساعدة</p>`,
+		`<span dir="auto">ABC-42</span>`,
+		`<p>رقم 123 فقط</p>`,
+		`<code>x = 1</code>`,
+		`<p dir="ltr">Already LTR</p>`,
+		`<html dir="rtl">`,
+		`<p>اشترِ <bdi>A&amp;B</bdi></p>`,
+		`<p>&quot;مرحبا&quot;&nbsp;<bdi>SKU&#45;7</bdi> &copy;</p>`,
+	}
+	for _, s := range expected {
+		if !strings.Contains(result, s) {
+			t.Errorf("Expected %q in result, got:\n%s", s, result)
+		}
+	}
+}
+
+func TestRTLRewriter_MirrorClasses(t *testing.T) {
+	content := `<div class="text-left ml-4 md:pr-2 -ml-1 border-l-2 card">مرحبا</div><div class="card">مرحبا</div>`
+
+	result, err := NewRTLRewriter().PostProcessHTML(content, "he_IL")
+	if err != nil {
+		t.Fatalf("PostProcessHTML failed: %v", err)
+	}
+
+	expected := `<div class="text-right mr-4 md:pl-2 -mr-1 border-r-2 card">مرحبا</div><div class="card">مرحبا</div>`
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestRTLRewriter_CustomClassMap(t *testing.T) {
+	r := &RTLRewriter{ClassMap: map[string]string{"start-*": "end-*"}}
+
+	result, err := r.PostProcessHTML(`<i class="start-3 ml-4">مرحبا SKU-1</i>`, "ar")
+	if err != nil {
+		t.Fatalf("PostProcessHTML failed: %v", err)
+	}
+
+	expected := `<i class="end-3 ml-4">مرحبا SKU-1</i>`
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestRTLRewriter_MirrorStyles(t *testing.T) {
+	tests := []struct {
+		style    string
+		expected string
+	}{
+		{"margin-left: 4px", "margin-right: 4px"},
+		{"padding-right:2px; color: red", "padding-left:2px; color: red"},
+		{"text-align: left", "text-align: right"},
+		{"float:right", "float:left"},
+		{"border-top-left-radius: 3px", "border-top-right-radius: 3px"},
+		{"margin: 1px 2px 3px 4px", "margin: 1px 4px 3px 2px"},
+		{"padding: 0 1em 0 2em !important", "padding: 0 2em 0 1em !important"},
+		{"margin: 1px 2px", "margin: 1px 2px"},
+		{"left: 0", "right: 0"},
+		{"color: red", "color: red"},
+		{"border-radius: 4px 0 0 4px", "border-radius: 0 4px 4px 0"},
+		{"border-radius: 1px 2px 3px", "border-radius: 2px 1px 2px 3px"},
+		{"border-radius: 1px 2px / 3px", "border-radius: 2px 1px / 3px"},
+		{"border-radius: 4px", "border-radius: 4px"},
+		{"background-position: right 10px top", "background-position: left 10px top"},
+		{"background-position: 20% center", "background-position: 20% center"},
+		{"scroll-padding: 0 1px 0 2px", "scroll-padding: 0 2px 0 1px"},
+	}
+
+	for _, tt := range tests {
+		if got := mirrorStyle(tt.style); got != tt.expected {
+			t.Errorf("mirrorStyle(%q) = %q, want %q", tt.style, got, tt.expected)
+		}
+	}
+}
+
+func TestRTLRewriter_KeepsUnchangedTags(t *testing.T) {
+	content := `<div   id=main data-x='1'><img src=a.png></div>`
+
+	result, err := NewRTLRewriter().PostProcessHTML(content, "ar")
+	if err != nil {
+		t.Fatalf("PostProcessHTML failed: %v", err)
+	}
+	if result != content {
+		t.Errorf("Expected content unchanged, got %q", result)
+	}
+}
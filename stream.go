@@ -0,0 +1,116 @@
+package gotlai
+
+import (
+	"context"
+	"io"
+)
+
+// StreamProcessor is implemented by processors that can translate content
+// incrementally, writing output as they go instead of holding the whole
+// document in memory.
+type StreamProcessor interface {
+	Stream(ctx context.Context, r io.Reader, w io.Writer, translator StreamTranslator) error
+}
+
+// StreamTranslator translates windows of text nodes on behalf of a StreamProcessor.
+type StreamTranslator interface {
+	// Translate returns translations keyed by node hash.
+	Translate(ctx context.Context, nodes []TextNode) (map[string]string, error)
+	// TargetLang returns the target language code.
+	TargetLang() string
+}
+
+// ProcessStream translates content read from r and writes the result to w.
+// Processors implementing StreamProcessor translate in windows and write output
+// progressively; others fall back to reading the whole input and calling Process.
+// The returned ProcessedContent carries statistics only; its Content is empty.
+// Streaming HTML returns an error when HTML post-processors are configured.
+func (t *Translator) ProcessStream(ctx context.Context, r io.Reader, w io.Writer, contentType string) (*ProcessedContent, error) {
+	// Skip if source == target
+	if t.isSourceLang() {
+		if _, err := io.Copy(w, r); err != nil {
+			return nil, err
+		}
+		return &ProcessedContent{}, nil
+	}
+
+	// Get processor
+	processor, ok := t.processors[contentType]
+	if !ok {
+		return nil, &ProcessorError{
+			Message:     "no processor registered for content type",
+			ContentType: contentType,
+		}
+	}
+
+	sp, ok := processor.(StreamProcessor)
+	if !ok {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		result, err := t.Process(ctx, string(data), contentType)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(w, result.Content); err != nil {
+			return nil, err
+		}
+		result.Content = ""
+		return result, nil
+	}
+
+	// Post-processors need the whole document, so they cannot be skipped silently
+	if processor.ContentType() == "html" && len(t.postProcessors) > 0 {
+		return nil, &ProcessorError{
+			Message:     "HTML post-processors are not supported when streaming; use Process",
+			ContentType: contentType,
+		}
+	}
+
+	validator, _ := processor.(TranslationValidator)
+	session := &streamSession{translator: t, validator: validator, stats: &ProcessedContent{}}
+	if err := sp.Stream(ctx, r, w, session); err != nil {
+		return nil, err
+	}
+
+	return session.stats, nil
+}
+
+// streamSession adapts a Translator to StreamTranslator and accumulates statistics.
+type streamSession struct {
+	translator *Translator
+	validator  TranslationValidator
+	stats      *ProcessedContent
+}
+
+// Translate translates one window of nodes, using the cache where possible.
+func (s *streamSession) Translate(ctx context.Context, nodes []TextNode) (map[string]string, error) {
+	if len(nodes) == 0 {
+		return map[string]string{}, nil
+	}
+
+	batch, err := s.translator.translateBatch(ctx, nodes, s.validator)
+	if err != nil {
+		return nil, err
+	}
+
+	// Renumber chunks so indexes stay unique across windows
+	for _, chunk := range batch.chunks {
+		chunk.Index = len(s.stats.Chunks)
+		s.stats.Chunks = append(s.stats.Chunks, chunk)
+	}
+
+	s.stats.TotalNodes += len(nodes)
+	s.stats.TranslatedCount += batch.translatedCount
+	s.stats.CachedCount += batch.cachedCount
+	s.stats.Failed = append(s.stats.Failed, batch.failed...)
+	s.stats.Errors = append(s.stats.Errors, batch.errors...)
+
+	return batch.translations, nil
+}
+
+// TargetLang returns the target language code.
+func (s *streamSession) TargetLang() string {
+	return s.translator.targetLang
+}
@@ -0,0 +1,62 @@
+package gotlai
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTranslator_ProcessStream_Fallback(t *testing.T) {
+	provider := newMockProvider()
+
+	translator := NewTranslator("es_ES", provider,
+		WithProcessor(&mockHTMLProcessor{}),
+	)
+
+	var out bytes.Buffer
+	result, err := translator.ProcessStream(context.Background(), strings.NewReader("<p>Hello</p>"), &out, "html")
+	if err != nil {
+		t.Fatalf("ProcessStream failed: %v", err)
+	}
+
+	if !strings.Contains(out.String(), "Hola") {
+		t.Errorf("Output should contain 'Hola', got: %s", out.String())
+	}
+	if result.Content != "" {
+		t.Error("Result content should be empty when streaming")
+	}
+	if result.TranslatedCount != 1 {
+		t.Errorf("Expected TranslatedCount 1, got %d", result.TranslatedCount)
+	}
+}
+
+func TestTranslator_ProcessStream_SourceEqualsTarget(t *testing.T) {
+	provider := newMockProvider()
+
+	translator := NewTranslator("en_US", provider,
+		WithProcessor(&mockHTMLProcessor{}),
+	)
+
+	var out bytes.Buffer
+	if _, err := translator.ProcessStream(context.Background(), strings.NewReader("<p>Hello</p>"), &out, "html"); err != nil {
+		t.Fatalf("ProcessStream failed: %v", err)
+	}
+
+	if out.String() != "<p>Hello</p>" {
+		t.Errorf("Output should be unchanged, got: %s", out.String())
+	}
+	if provider.callCount != 0 {
+		t.Error("Provider should not be called when source==target")
+	}
+}
+
+func TestTranslator_ProcessStream_NoProcessor(t *testing.T) {
+	translator := NewTranslator("es_ES", newMockProvider())
+
+	var out bytes.Buffer
+	_, err := translator.ProcessStream(context.Background(), strings.NewReader("<p>Hello</p>"), &out, "html")
+	if _, ok := err.(*ProcessorError); !ok {
+		t.Errorf("Expected ProcessorError, got %T", err)
+	}
+}
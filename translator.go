@@ -2,9 +2,12 @@ package gotlai
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"strings"
+	"sync"
 
-	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
 )
 
 // Translator is the main translation engine.
@@ -18,6 +21,15 @@ type Translator struct {
 	glossary      map[string]string
 	style         TranslationStyle
 	processors    map[string]ContentProcessor
+	chunking      ChunkConfig
+	concurrency   int
+	partial       bool
+
+	cacheKeyStrategy CacheKeyStrategy
+	model            string
+	fingerprint      string // Computed once from the options in NewTranslator
+
+	postProcessors []HTMLPostProcessor
 }
 
 // AIProvider is the interface for AI translation backends.
@@ -44,12 +56,37 @@ type TranslationCache interface {
 }
 
 // ContentProcessor is the interface for content processing.
+// Apply must leave parsed content unchanged so one extraction can be applied
+// for several target languages (see ProcessMany).
 type ContentProcessor interface {
 	Extract(content string) (interface{}, []TextNode, error)
 	Apply(parsed interface{}, nodes []TextNode, translations map[string]string) (string, error)
 	ContentType() string
 }
 
+// TranslationValidator is implemented by processors that can check a translation
+// before it is cached and applied, e.g. that markup placeholders survived.
+// Invalid translations are treated like failed requests for their node.
+type TranslationValidator interface {
+	ValidateTranslation(node TextNode, translated string) error
+}
+
+// TargetApplier is implemented by processors whose output depends on the target
+// language, e.g. gettext catalogs that need the language's plural forms. The
+// Translator calls ApplyTarget instead of Apply.
+type TargetApplier interface {
+	ApplyTarget(parsed interface{}, nodes []TextNode, translations map[string]string, targetLang string) (string, error)
+}
+
+// TargetNodeProvider is implemented by processors that need extra nodes for
+// some target languages, e.g. the plural forms a language has beyond those of
+// the source. TargetNodes returns the nodes to add to those from Extract; the
+// Translator translates them together and passes all of them to ApplyTarget.
+// TargetNodes must not modify parsed, which is shared across languages.
+type TargetNodeProvider interface {
+	TargetNodes(parsed interface{}, nodes []TextNode, targetLang string) []TextNode
+}
+
 // TranslatorOption is a functional option for configuring the Translator.
 type TranslatorOption func(*Translator)
 
@@ -95,6 +132,16 @@ func WithStyle(style TranslationStyle) TranslatorOption {
 	}
 }
 
+// WithPartialResults keeps going when a provider request fails. Nodes from failed
+// chunks keep their source text and are reported in ProcessedContent.Failed, while
+// successfully translated chunks are still applied and cached. Context
+// cancellation still aborts processing with an error.
+func WithPartialResults() TranslatorOption {
+	return func(t *Translator) {
+		t.partial = true
+	}
+}
+
 // WithProcessor registers a content processor.
 func WithProcessor(processor ContentProcessor) TranslatorOption {
 	return func(t *Translator) {
@@ -105,17 +152,20 @@ func WithProcessor(processor ContentProcessor) TranslatorOption {
 // NewTranslator creates a new Translator with the given target language and provider.
 func NewTranslator(targetLang string, provider AIProvider, opts ...TranslatorOption) *Translator {
 	t := &Translator{
-		targetLang: targetLang,
-		sourceLang: "en",
-		provider:   provider,
-		style:      StyleNeutral,
-		processors: make(map[string]ContentProcessor),
+		targetLang:  targetLang,
+		sourceLang:  "en",
+		provider:    provider,
+		style:       StyleNeutral,
+		processors:  make(map[string]ContentProcessor),
+		concurrency: 1,
 	}
 
 	for _, opt := range opts {
 		opt(t)
 	}
 
+	t.fingerprint = t.Fingerprint()
+
 	return t
 }
 
@@ -145,6 +195,7 @@ func (t *Translator) Process(ctx context.Context, content string, contentType st
 	if err != nil {
 		return nil, err
 	}
+	nodes = t.targetNodes(processor, parsed, nodes)
 
 	if len(nodes) == 0 {
 		return &ProcessedContent{
@@ -156,37 +207,79 @@ func (t *Translator) Process(ctx context.Context, content string, contentType st
 	}
 
 	// Translate batch
-	translations, cachedCount, translatedCount, err := t.translateBatch(ctx, nodes)
+	validator, _ := processor.(TranslationValidator)
+	batch, err := t.translateBatch(ctx, nodes, validator)
 	if err != nil {
 		return nil, err
 	}
 
-	// Apply translations
-	result, err := processor.Apply(parsed, nodes, translations)
+	return t.applyBatch(processor, parsed, nodes, batch)
+}
+
+// ProcessHTML is a convenience method for processing HTML content.
+func (t *Translator) ProcessHTML(ctx context.Context, html string) (*ProcessedContent, error) {
+	return t.Process(ctx, html, "html")
+}
+
+// targetNodes appends the processor's extra nodes for the target language, if any.
+func (t *Translator) targetNodes(processor ContentProcessor, parsed interface{}, nodes []TextNode) []TextNode {
+	provider, ok := processor.(TargetNodeProvider)
+	if !ok {
+		return nodes
+	}
+	extra := provider.TargetNodes(parsed, nodes, t.targetLang)
+	if len(extra) == 0 {
+		return nodes
+	}
+	return append(nodes[:len(nodes):len(nodes)], extra...)
+}
+
+// applyBatch applies a translated batch to parsed content and builds the result.
+func (t *Translator) applyBatch(processor ContentProcessor, parsed interface{}, nodes []TextNode, batch *batchResult) (*ProcessedContent, error) {
+	var result string
+	var err error
+	if applier, ok := processor.(TargetApplier); ok {
+		result, err = applier.ApplyTarget(parsed, nodes, batch.translations, t.targetLang)
+	} else {
+		result, err = processor.Apply(parsed, nodes, batch.translations)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	// Set HTML attributes if applicable
-	if contentType == "html" {
-		result = t.setHTMLAttributes(result)
+	// Set HTML attributes and run post-processors if applicable
+	if processor.ContentType() == "html" {
+		result, err = t.postProcessHTML(result)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return &ProcessedContent{
 		Content:         result,
-		TranslatedCount: translatedCount,
-		CachedCount:     cachedCount,
+		TranslatedCount: batch.translatedCount,
+		CachedCount:     batch.cachedCount,
 		TotalNodes:      len(nodes),
+		Chunks:          batch.chunks,
+		Failed:          batch.failed,
+		Errors:          batch.errors,
 	}, nil
 }
 
-// ProcessHTML is a convenience method for processing HTML content.
-func (t *Translator) ProcessHTML(ctx context.Context, html string) (*ProcessedContent, error) {
-	return t.Process(ctx, html, "html")
+// batchResult holds the outcome of translating a batch of nodes.
+type batchResult struct {
+	translations    map[string]string
+	cachedCount     int
+	translatedCount int
+	chunks          []ChunkResult
+	failed          []TextNode
+	errors          []error
+	validator       TranslationValidator // Optional check applied to new translations
 }
 
 // translateBatch translates nodes, using cache where possible.
-func (t *Translator) translateBatch(ctx context.Context, nodes []TextNode) (map[string]string, int, int, error) {
+// New translations are checked with validator when it is non-nil.
+func (t *Translator) translateBatch(ctx context.Context, nodes []TextNode, validator TranslationValidator) (*batchResult, error) {
 	translations := make(map[string]string)
 	var cacheMisses []TextNode
 	seenHashes := make(map[string]bool)
@@ -194,7 +287,7 @@ func (t *Translator) translateBatch(ctx context.Context, nodes []TextNode) (map[
 
 	// Check cache for each node
 	for _, node := range nodes {
-		cacheKey := CacheKey(node.Hash, t.targetLang)
+		cacheKey := t.cacheKey(node.Hash)
 
 		if t.cache != nil {
 			if cached, ok := t.cache.Get(cacheKey); ok {
@@ -211,42 +304,188 @@ func (t *Translator) translateBatch(ctx context.Context, nodes []TextNode) (map[
 		}
 	}
 
-	// Translate cache misses via AI
-	translatedCount := 0
-	if len(cacheMisses) > 0 && t.provider != nil {
-		texts := make([]string, len(cacheMisses))
-		textContexts := make([]string, len(cacheMisses))
-		for i, node := range cacheMisses {
-			texts[i] = node.Text
-			textContexts[i] = node.Context
-		}
+	batch := &batchResult{
+		translations: translations,
+		cachedCount:  cachedCount,
+		validator:    validator,
+	}
 
-		results, err := t.provider.Translate(ctx, TranslateRequest{
-			Texts:         texts,
-			TargetLang:    t.targetLang,
-			SourceLang:    t.sourceLang,
-			ExcludedTerms: t.excludedTerms,
-			Context:       t.context,
-			TextContexts:  textContexts,
-			Glossary:      t.glossary,
-			Style:         t.style,
-		})
-		if err != nil {
-			return nil, 0, 0, err
+	if err := t.translateMisses(ctx, cacheMisses, batch); err != nil {
+		return nil, err
+	}
+
+	return batch, nil
+}
+
+// translateMisses translates cache misses via the provider, one request per chunk,
+// and merges the results into batch by hash. Chunks are dispatched to a bounded
+// pool of workers; results are merged and cached on the calling goroutine, so the
+// cache does not need to be safe for concurrent use. The first failing chunk
+// cancels the chunks that have not started yet, unless partial results are
+// enabled, in which case failed chunks are recorded and the rest still run.
+func (t *Translator) translateMisses(ctx context.Context, cacheMisses []TextNode, batch *batchResult) error {
+	if len(cacheMisses) == 0 || t.provider == nil {
+		return nil
+	}
+
+	chunks := ChunkNodes(cacheMisses, t.chunking)
+
+	workers := t.concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(chunks) {
+		workers = len(chunks)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type chunkOutput struct {
+		results []string
+		result  ChunkResult
+	}
+
+	jobs := make(chan int)
+	outputs := make(chan chunkOutput, len(chunks))
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				results, chunkResult := t.translateChunk(ctx, i, chunks[i])
+				outputs <- chunkOutput{results: results, result: chunkResult}
+			}
+		}()
+	}
+
+	// Feed chunks to workers until done or cancelled
+	go func() {
+		defer close(jobs)
+		for i := range chunks {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(outputs)
+	}()
+
+	var firstErr error
+	var invalid []TextNode
+	var invalidErrs []error
+	chunkResults := make([]ChunkResult, 0, len(chunks))
+	for out := range outputs {
+		chunkResults = append(chunkResults, out.result)
+		if out.result.Err != nil {
+			if t.partial {
+				continue
+			}
+			if firstErr == nil {
+				firstErr = out.result.Err
+				cancel()
+			}
+			continue
 		}
 
 		// Cache and store results
-		for i, node := range cacheMisses {
-			translations[node.Hash] = results[i]
+		for j, node := range chunks[out.result.Index] {
+			if batch.validator != nil {
+				if err := batch.validator.ValidateTranslation(node, out.results[j]); err != nil {
+					err = &TranslationError{
+						Message: fmt.Sprintf("invalid translation for node %s", node.ID),
+						Cause:   err,
+					}
+					if t.partial {
+						invalid = append(invalid, node)
+						invalidErrs = append(invalidErrs, err)
+					} else if firstErr == nil {
+						firstErr = err
+						cancel()
+					}
+					continue
+				}
+			}
+
+			batch.translations[node.Hash] = out.results[j]
 			if t.cache != nil {
-				cacheKey := CacheKey(node.Hash, t.targetLang)
-				_ = t.cache.Set(cacheKey, results[i]) // Ignore cache set errors
+				cacheKey := t.cacheKey(node.Hash)
+				_ = t.cache.Set(cacheKey, out.results[j]) // Ignore cache set errors
 			}
-			translatedCount++
+			batch.translatedCount++
 		}
 	}
 
-	return translations, cachedCount, translatedCount, nil
+	sort.Slice(chunkResults, func(i, j int) bool {
+		return chunkResults[i].Index < chunkResults[j].Index
+	})
+	batch.chunks = append(batch.chunks, chunkResults...)
+
+	for _, chunkResult := range chunkResults {
+		if t.partial && chunkResult.Err != nil {
+			batch.failed = append(batch.failed, chunks[chunkResult.Index]...)
+			batch.errors = append(batch.errors, chunkResult.Err)
+		}
+	}
+	batch.failed = append(batch.failed, invalid...)
+	batch.errors = append(batch.errors, invalidErrs...)
+
+	// Chunks that never started because the caller cancelled
+	if firstErr == nil && len(chunkResults) < len(chunks) {
+		firstErr = ctx.Err()
+	}
+
+	// Partial results never cancel internally, so this is the caller's cancellation
+	if firstErr == nil && t.partial && ctx.Err() != nil {
+		firstErr = ctx.Err()
+	}
+
+	return firstErr
+}
+
+// translateChunk sends a single chunk of nodes to the provider.
+func (t *Translator) translateChunk(ctx context.Context, index int, chunk []TextNode) ([]string, ChunkResult) {
+	texts := make([]string, len(chunk))
+	textContexts := make([]string, len(chunk))
+	tokens := 0
+	for i, node := range chunk {
+		texts[i] = node.Text
+		textContexts[i] = node.Context
+		tokens += estimateNodeTokens(node)
+	}
+
+	chunkResult := ChunkResult{
+		Index:           index,
+		Texts:           len(chunk),
+		EstimatedTokens: tokens,
+	}
+
+	results, err := t.provider.Translate(ctx, TranslateRequest{
+		Texts:         texts,
+		TargetLang:    t.targetLang,
+		SourceLang:    t.sourceLang,
+		ExcludedTerms: t.excludedTerms,
+		Context:       t.context,
+		TextContexts:  textContexts,
+		Glossary:      t.glossary,
+		Style:         t.style,
+	})
+	if err == nil && len(results) != len(chunk) {
+		err = &CountMismatchError{Expected: len(chunk), Got: len(results)}
+	}
+	chunkResult.Err = err
+
+	return results, chunkResult
 }
 
 // isSourceLang checks if target matches source (no translation needed).
@@ -260,25 +499,50 @@ func (t *Translator) isSourceLang() bool {
 	return target == source
 }
 
-// setHTMLAttributes sets lang and dir attributes on the <html> tag.
-func (t *Translator) setHTMLAttributes(html string) string {
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
-	if err != nil {
-		return html
-	}
-
-	htmlTag := doc.Find("html")
-	if htmlTag.Length() > 0 {
-		htmlTag.SetAttr("lang", ToHTMLLang(t.targetLang))
-		htmlTag.SetAttr("dir", GetDirection(t.targetLang))
-	}
+// setHTMLAttributes sets lang and dir attributes on the <html> tag. Only that tag
+// is rewritten; content without one, such as a fragment, is returned unchanged.
+func (t *Translator) setHTMLAttributes(content string) string {
+	z := html.NewTokenizer(strings.NewReader(content))
+	offset := 0
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return content
+		}
+		size := len(z.Raw())
+
+		if tt == html.StartTagToken || tt == html.SelfClosingTagToken {
+			name, hasAttr := z.TagName()
+			if string(name) == "html" {
+				values := map[string]string{
+					"lang": ToHTMLLang(t.targetLang),
+					"dir":  GetDirection(t.targetLang),
+				}
+
+				var attrs []html.Attribute
+				for hasAttr {
+					var key, val []byte
+					key, val, hasAttr = z.TagAttr()
+					attr := html.Attribute{Key: string(key), Val: string(val)}
+					if v, ok := values[attr.Key]; ok {
+						attr.Val = v
+						delete(values, attr.Key)
+					}
+					attrs = append(attrs, attr)
+				}
+				for _, key := range []string{"lang", "dir"} {
+					if v, ok := values[key]; ok {
+						attrs = append(attrs, html.Attribute{Key: key, Val: v})
+					}
+				}
+
+				tag := html.Token{Type: tt, Data: "html", Attr: attrs}.String()
+				return content[:offset] + tag + content[offset+size:]
+			}
+		}
 
-	result, err := doc.Html()
-	if err != nil {
-		return html
+		offset += size
 	}
-
-	return result
 }
 
 // TargetLang returns the target language.
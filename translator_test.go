@@ -2,12 +2,15 @@ package gotlai
 
 import (
 	"context"
+	"errors"
 	"strings"
+	"sync"
 	"testing"
 )
 
 // mockProvider is a simple mock for testing
 type mockProvider struct {
+	mu           sync.Mutex
 	translations map[string]string
 	callCount    int
 	lastTexts    []string
@@ -27,8 +30,10 @@ func newMockProvider() *mockProvider {
 }
 
 func (m *mockProvider) Translate(ctx context.Context, req TranslateRequest) ([]string, error) {
+	m.mu.Lock()
 	m.callCount++
 	m.lastTexts = req.Texts
+	m.mu.Unlock()
 
 	results := make([]string, len(req.Texts))
 	for i, text := range req.Texts {
@@ -410,3 +415,231 @@ func TestTranslator_DefaultStyle(t *testing.T) {
 		t.Errorf("Default style should be StyleNeutral, got %q", translator.Style())
 	}
 }
+
+// flakyProvider fails any request containing one of the given texts.
+type flakyProvider struct {
+	*mockProvider
+	failOn map[string]bool
+}
+
+func (p *flakyProvider) Translate(ctx context.Context, req TranslateRequest) ([]string, error) {
+	for _, text := range req.Texts {
+		if p.failOn[text] {
+			return nil, &ProviderError{Message: "upstream unavailable", Retryable: true}
+		}
+	}
+	return p.mockProvider.Translate(ctx, req)
+}
+
+func TestTranslator_PartialResults(t *testing.T) {
+	provider := &flakyProvider{
+		mockProvider: newMockProvider(),
+		failOn:       map[string]bool{"World": true},
+	}
+	cache := newMockCache()
+
+	translator := NewTranslator("es_ES", provider,
+		WithCache(cache),
+		WithProcessor(&mockHTMLProcessor{}),
+		WithChunking(ChunkConfig{MaxTexts: 1}),
+		WithPartialResults(),
+	)
+
+	result, err := translator.Process(context.Background(), "<p>Hello</p><p>World</p><p>Translate me</p>", "html")
+	if err != nil {
+		t.Fatalf("Process should succeed with partial results, got: %v", err)
+	}
+
+	if !strings.Contains(result.Content, "<p>Hola</p>") {
+		t.Errorf("Successful chunk should be applied, got: %s", result.Content)
+	}
+	if !strings.Contains(result.Content, "<p>World</p>") {
+		t.Errorf("Failed node should keep source text, got: %s", result.Content)
+	}
+	if result.TranslatedCount != 2 {
+		t.Errorf("Expected TranslatedCount 2, got %d", result.TranslatedCount)
+	}
+
+	if len(result.Failed) != 1 || result.Failed[0].Text != "World" {
+		t.Fatalf("Expected 'World' as the only failed node, got %v", result.Failed)
+	}
+	if len(result.Errors) != 1 {
+		t.Fatalf("Expected 1 error, got %d", len(result.Errors))
+	}
+	var providerErr *ProviderError
+	if !errors.As(result.Errors[0], &providerErr) {
+		t.Errorf("Expected ProviderError, got %T", result.Errors[0])
+	}
+
+	// Successful chunks are cached, failed ones are not
+	if _, ok := cache.Get(CacheKey(HashText("Hello"), "es_ES")); !ok {
+		t.Error("Successful translation should be cached")
+	}
+	if _, ok := cache.Get(CacheKey(HashText("World"), "es_ES")); ok {
+		t.Error("Failed translation should not be cached")
+	}
+}
+
+func TestTranslator_PartialResultsDisabled(t *testing.T) {
+	provider := &flakyProvider{
+		mockProvider: newMockProvider(),
+		failOn:       map[string]bool{"World": true},
+	}
+
+	translator := NewTranslator("es_ES", provider,
+		WithProcessor(&mockHTMLProcessor{}),
+	)
+
+	result, err := translator.Process(context.Background(), "<p>Hello</p><p>World</p>", "html")
+	if err == nil {
+		t.Fatal("Expected error without partial results")
+	}
+	if result != nil {
+		t.Error("Result should be nil on error")
+	}
+}
+
+func TestTranslator_PartialResultsCancelled(t *testing.T) {
+	translator := NewTranslator("es_ES", newMockProvider(),
+		WithProcessor(&mockHTMLProcessor{}),
+		WithPartialResults(),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := translator.Process(ctx, "<p>Hello</p>", "html"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+// validatingProcessor rejects bracketed fallback translations.
+type validatingProcessor struct {
+	mockHTMLProcessor
+}
+
+func (p *validatingProcessor) ValidateTranslation(node TextNode, translated string) error {
+	if strings.HasPrefix(translated, "[") {
+		return errors.New("untranslated")
+	}
+	return nil
+}
+
+func TestTranslator_ValidateTranslation(t *testing.T) {
+	// mockProvider brackets unknown texts, which the validator rejects
+	cache := newMockCache()
+	translator := NewTranslator("es_ES", newMockProvider(),
+		WithCache(cache),
+		WithProcessor(&validatingProcessor{}),
+	)
+
+	_, err := translator.Process(context.Background(), "<p>Hello</p><p>Unknown</p>", "html")
+	var transErr *TranslationError
+	if !errors.As(err, &transErr) {
+		t.Fatalf("Expected TranslationError, got %v", err)
+	}
+
+	translator = NewTranslator("es_ES", newMockProvider(),
+		WithCache(cache),
+		WithProcessor(&validatingProcessor{}),
+		WithPartialResults(),
+	)
+
+	result, err := translator.Process(context.Background(), "<p>Hello</p><p>Unknown</p>", "html")
+	if err != nil {
+		t.Fatalf("Process should succeed with partial results, got: %v", err)
+	}
+	if !strings.Contains(result.Content, "<p>Hola</p>") {
+		t.Errorf("Valid translation should be applied, got: %s", result.Content)
+	}
+	if len(result.Failed) != 1 || result.Failed[0].Text != "Unknown" {
+		t.Errorf("Expected 'Unknown' as the only failed node, got %v", result.Failed)
+	}
+	if _, ok := cache.Get(CacheKey(HashText("Unknown"), "es_ES")); ok {
+		t.Error("Invalid translation should not be cached")
+	}
+}
+
+// targetProcessor records the target language passed to ApplyTarget.
+type targetProcessor struct {
+	mockHTMLProcessor
+	targetLang string
+}
+
+func (p *targetProcessor) ApplyTarget(parsed interface{}, nodes []TextNode, translations map[string]string, targetLang string) (string, error) {
+	p.targetLang = targetLang
+	return p.Apply(parsed, nodes, translations)
+}
+
+func (p *targetProcessor) ContentType() string {
+	return "text"
+}
+
+func TestTranslator_TargetApplier(t *testing.T) {
+	processor := &targetProcessor{}
+	translator := NewTranslator("es_ES", newMockProvider(), WithProcessor(processor))
+
+	result, err := translator.Process(context.Background(), "<p>Hello</p>", "text")
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if result.Content != "<p>Hola</p>" {
+		t.Errorf("Expected translated content, got %q", result.Content)
+	}
+	if processor.targetLang != "es_ES" {
+		t.Errorf("Expected ApplyTarget to receive es_ES, got %q", processor.targetLang)
+	}
+
+	results, err := translator.ProcessMany(context.Background(), "<p>Hello</p>", "text", []string{"fr_FR"})
+	if err != nil {
+		t.Fatalf("ProcessMany failed: %v", err)
+	}
+	if results["fr_FR"] == nil || processor.targetLang != "fr_FR" {
+		t.Errorf("Expected ApplyTarget to receive fr_FR, got %q", processor.targetLang)
+	}
+}
+
+// targetNodesProcessor adds a "World" node for French targets and appends its
+// translation to the output.
+type targetNodesProcessor struct {
+	targetProcessor
+}
+
+func (p *targetNodesProcessor) TargetNodes(parsed interface{}, nodes []TextNode, targetLang string) []TextNode {
+	if !strings.HasPrefix(targetLang, "fr") {
+		return nil
+	}
+	return []TextNode{{ID: "extra", Text: "World", Hash: HashText("World"), NodeType: "text"}}
+}
+
+func (p *targetNodesProcessor) ApplyTarget(parsed interface{}, nodes []TextNode, translations map[string]string, targetLang string) (string, error) {
+	result, err := p.Apply(parsed, nodes, translations)
+	if err == nil && len(nodes) > 1 {
+		result += translations[nodes[1].Hash]
+	}
+	return result, err
+}
+
+func TestTranslator_TargetNodeProvider(t *testing.T) {
+	provider := newMockProvider()
+	translator := NewTranslator("fr_FR", provider, WithProcessor(&targetNodesProcessor{}))
+
+	result, err := translator.Process(context.Background(), "<p>Hello</p>", "text")
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if result.Content != "<p>Hola</p>Mundo" || result.TranslatedCount != 2 {
+		t.Errorf("Expected the extra node translated, got %q (%d)", result.Content, result.TranslatedCount)
+	}
+
+	results, err := translator.ProcessMany(context.Background(), "<p>Hello</p>", "text", []string{"fr_CA", "de_DE"})
+	if err != nil {
+		t.Fatalf("ProcessMany failed: %v", err)
+	}
+	if results["fr_CA"].Content != "<p>Hola</p>Mundo" {
+		t.Errorf("Expected the extra node for fr_CA, got %q", results["fr_CA"].Content)
+	}
+	if results["de_DE"].Content != "<p>Hola</p>" {
+		t.Errorf("Expected no extra node for de_DE, got %q", results["de_DE"].Content)
+	}
+}
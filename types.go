@@ -21,7 +21,7 @@ const (
 type TextNode struct {
 	ID       string            // Unique identifier (UUID)
 	Text     string            // Original text content (trimmed)
-	Hash     string            // SHA-256 hash of Text
+	Hash     string            // SHA-256 hash of Text (plus context key when context-sensitive)
 	NodeType string            // Content type: "html_text", "go_comment", etc.
 	Context  string            // Disambiguation context for AI
 	Metadata map[string]string // Additional info (parent tag, line number, etc.)
@@ -43,6 +43,17 @@ type ProcessedContent struct {
 	TranslatedCount int    // Number of newly translated items
 	CachedCount     int    // Number of cache hits
 	TotalNodes      int    // Total translatable nodes found
+
+	// Chunks reports the outcome of each provider request made for cache misses.
+	Chunks []ChunkResult
+
+	// Failed lists nodes left in the source language because their request failed.
+	// Only populated when partial results are enabled.
+	Failed []TextNode
+
+	// Errors holds the errors of failed requests in chunk order, followed by
+	// one validation error per node whose translation was rejected.
+	Errors []error
 }
 
 // RTLLanguages contains language codes that use right-to-left text direction.